@@ -0,0 +1,120 @@
+/*
+
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// CronTriggerSpec defines the desired state of CronTrigger
+// A CronTrigger invokes a Workflow on a schedule.
+// At each tick, the operator creates a WorkflowExecution owned by the CronTrigger,
+// which invokes the Workflow through its runtime Service and records the result.
+type CronTriggerSpec struct {
+	// Workflow is the name of the Workflow in the same namespace
+	Workflow string `json:"workflow"`
+	// Schedule is the schedule in the cron format, e.g. "*/5 * * * *" or "@daily"
+	// +kubebuilder:validation:MinLength=1
+	Schedule string `json:"schedule"`
+	// TimeZone is the IANA time zone the Schedule is interpreted in, e.g. "Asia/Shanghai"
+	// If no value is specified, it's UTC
+	// +optional
+	TimeZone string `json:"timeZone,omitempty"`
+	// Input is the JSON input of each invocation, e.g. {"report": "daily"}
+	// +optional
+	Input string `json:"input,omitempty"`
+	// ConcurrencyPolicy claims how to treat the invocations of the CronTrigger running at the same time
+	// Valid values are:
+	// - Allow: The invocations run at the same time, it's the default;
+	// - Forbid: The tick is skipped if the previous invocation is still running;
+	// - Replace: The running invocation is cancelled and replaced by the new one;
+	// +optional
+	ConcurrencyPolicy ConcurrencyPolicy `json:"concurrencyPolicy,omitempty"`
+	// Suspend stops the following ticks, the running invocations are not affected
+	// +optional
+	Suspend bool `json:"suspend,omitempty"`
+	// SucceededHistoryLimit is the number of succeeded WorkflowExecutions kept
+	// If no value is specified, it's 3
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	SucceededHistoryLimit *int32 `json:"succeededHistoryLimit,omitempty"`
+	// FailedHistoryLimit is the number of failed WorkflowExecutions kept
+	// If no value is specified, it's 1
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	FailedHistoryLimit *int32 `json:"failedHistoryLimit,omitempty"`
+}
+
+// ConcurrencyPolicy claims how to treat the invocations of a CronTrigger running at the same time
+// +kubebuilder:validation:Enum=Allow;Forbid;Replace
+type ConcurrencyPolicy string
+
+const (
+	// AllowConcurrent allows the invocations to run at the same time
+	AllowConcurrent ConcurrencyPolicy = "Allow"
+	// ForbidConcurrent skips the tick if the previous invocation is still running
+	ForbidConcurrent ConcurrencyPolicy = "Forbid"
+	// ReplaceConcurrent cancels the running invocation and starts a new one
+	ReplaceConcurrent ConcurrencyPolicy = "Replace"
+)
+
+// CronTriggerStatus defines the observed state of CronTrigger
+type CronTriggerStatus struct {
+	// Active lists the names of the running WorkflowExecutions
+	// +optional
+	Active []string `json:"active,omitempty"`
+	// LastScheduleTime is the time of the last tick
+	// +optional
+	LastScheduleTime *metav1.Time `json:"lastScheduleTime,omitempty"`
+	// LastSuccessfulTime is the time the last succeeded invocation completed
+	// +optional
+	LastSuccessfulTime *metav1.Time `json:"lastSuccessfulTime,omitempty"`
+	// NextScheduleTime is the time of the next tick
+	// +optional
+	NextScheduleTime *metav1.Time `json:"nextScheduleTime,omitempty"`
+	// Message is a human readable message of the last tick, e.g. why it's skipped
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Workflow",type=string,JSONPath=`.spec.workflow`
+// +kubebuilder:printcolumn:name="Schedule",type=string,JSONPath=`.spec.schedule`
+// +kubebuilder:printcolumn:name="Suspend",type=boolean,JSONPath=`.spec.suspend`
+// +kubebuilder:printcolumn:name="Last Schedule",type=date,JSONPath=`.status.lastScheduleTime`
+
+// CronTrigger is the Schema for the crontriggers API
+type CronTrigger struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   CronTriggerSpec   `json:"spec,omitempty"`
+	Status CronTriggerStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// CronTriggerList contains a list of CronTrigger
+type CronTriggerList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []CronTrigger `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&CronTrigger{}, &CronTriggerList{})
+}
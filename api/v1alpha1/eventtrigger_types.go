@@ -0,0 +1,127 @@
+/*
+
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// EventTriggerSpec defines the desired state of EventTrigger
+// An EventTrigger invokes a Workflow with the messages on a topic of a message queue.
+// The operator deploys a dispatcher Deployment per EventTrigger, which consumes the messages
+// and POSTs them to the Service of the Workflow.
+// The delivery is at-least-once: a message is acknowledged only when the invocation succeeded,
+// or when it has been published to the DeadLetter topic after MaxAttempts failures.
+type EventTriggerSpec struct {
+	// Workflow is the name of the Workflow in the same namespace
+	Workflow string `json:"workflow"`
+	// Flow is the Flow the invocations start from, the entrance of the Workflow if empty
+	// +optional
+	Flow string `json:"flow,omitempty"`
+	// Source is the message queue the messages come from
+	Source EventSource `json:"source"`
+	// DeadLetter is the topic on the same message queue where the messages failing all the attempts go
+	// If no value is specified, a failing message is redelivered until the invocation succeeds
+	// +optional
+	DeadLetter string `json:"deadLetter,omitempty"`
+	// MaxAttempts is the number of times the Workflow is invoked with a message before it goes to
+	// the DeadLetter topic. If no value is specified, it's 3
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	MaxAttempts *int32 `json:"maxAttempts,omitempty"`
+	// Replicas is the number of the dispatchers, they share the messages as a consumer group
+	// If no value is specified, it's 1
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	Replicas *int32 `json:"replicas,omitempty"`
+}
+
+// EventSource claims the topic of a message queue
+// A sample of EventSource
+// ```yaml
+// source:
+//   type: nats
+//   servers:
+//   - nats://nats:4222
+//   topic: orders
+//   group: order-workflow
+// ```
+type EventSource struct {
+	// Type is the type of the message queue
+	// Valid values are:
+	// - nats: The Servers are the NATS servers, and the Topic is a subject of a JetStream stream;
+	//   the stream, and the one of the DeadLetter if any, are managed by the administrators of NATS;
+	Type EventSourceType `json:"type"`
+	// Servers lists the addresses of the message queue, e.g. "nats://nats:4222"
+	// +kubebuilder:validation:MinItems=1
+	Servers []string `json:"servers"`
+	// Topic is the topic the messages are consumed from
+	// +kubebuilder:validation:MinLength=1
+	Topic string `json:"topic"`
+	// Group is the consumer group the dispatchers join, the durable consumer of JetStream for NATS
+	// The characters JetStream does not accept in a durable name, e.g. ".", are replaced with "_"
+	// If no value is specified, it's "<namespace>.<EventTrigger name>"
+	// +optional
+	Group string `json:"group,omitempty"`
+}
+
+// EventSourceType is the type of a message queue
+// +kubebuilder:validation:Enum=nats
+type EventSourceType string
+
+const (
+	// NATSSource is a NATS message queue with JetStream
+	NATSSource EventSourceType = "nats"
+)
+
+// EventTriggerStatus defines the observed state of EventTrigger
+type EventTriggerStatus struct {
+	// ObservedGeneration is the generation of the EventTrigger the dispatchers are deployed from
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+	// ReadyReplicas is the number of the dispatchers ready
+	// +optional
+	ReadyReplicas int32 `json:"readyReplicas,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Workflow",type=string,JSONPath=`.spec.workflow`
+// +kubebuilder:printcolumn:name="Source",type=string,JSONPath=`.spec.source.type`
+// +kubebuilder:printcolumn:name="Topic",type=string,JSONPath=`.spec.source.topic`
+// +kubebuilder:printcolumn:name="Ready",type=integer,JSONPath=`.status.readyReplicas`
+
+// EventTrigger is the Schema for the eventtriggers API
+type EventTrigger struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   EventTriggerSpec   `json:"spec,omitempty"`
+	Status EventTriggerStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// EventTriggerList contains a list of EventTrigger
+type EventTriggerList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []EventTrigger `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&EventTrigger{}, &EventTriggerList{})
+}
@@ -61,6 +61,7 @@ type FunctionStatus struct {
 }
 
 // +kubebuilder:object:root=true
+// +kubebuilder:storageversion
 
 // Function is the Schema for the functions API
 type Function struct {
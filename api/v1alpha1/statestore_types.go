@@ -0,0 +1,123 @@
+/*
+
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// StateStoreSpec defines the desired state of StateStore
+// A StateStore is the Redis the schedulers of the WorkflowRuntimes referencing it coordinate through.
+// The operator provisions a Redis Deployment and Service with a password Secret,
+// or points at an existing Redis when External is set.
+type StateStoreSpec struct {
+	// External is an existing Redis, the operator provisions nothing when it's set
+	// +optional
+	External *ExternalStore `json:"external,omitempty"`
+	// Redis configures the Redis the operator provisions
+	// +optional
+	Redis *RedisStore `json:"redis,omitempty"`
+}
+
+// ExternalStore is a Redis not managed by the operator
+type ExternalStore struct {
+	// Host is the host name or the IP of the Redis
+	// +kubebuilder:validation:MinLength=1
+	Host string `json:"host"`
+	// Port is the port of the Redis, 6379 if no value is specified
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=65535
+	// +optional
+	Port *int32 `json:"port,omitempty"`
+	// PasswordSecret is the key of a Secret in the same namespace holding the password,
+	// the Redis requires no password if no value is specified
+	// +optional
+	PasswordSecret *corev1.SecretKeySelector `json:"passwordSecret,omitempty"`
+}
+
+// RedisStore configures the Redis the operator provisions
+type RedisStore struct {
+	// Image is the Redis image, "redis:6.2-alpine" if no value is specified
+	// +optional
+	Image string `json:"image,omitempty"`
+	// Persistence keeps the data in a PersistentVolumeClaim, the data is lost with the Pod if no value is specified
+	// +optional
+	Persistence *StorePersistence `json:"persistence,omitempty"`
+	// Resources is the compute resources of the Redis container
+	// +optional
+	Resources corev1.ResourceRequirements `json:"resources,omitempty"`
+}
+
+// StorePersistence claims the volume of the data of the Redis
+type StorePersistence struct {
+	// Size is the requested size of the PersistentVolumeClaim
+	Size resource.Quantity `json:"size"`
+	// StorageClassName is the StorageClass of the PersistentVolumeClaim, the default one if no value is specified
+	// +optional
+	StorageClassName *string `json:"storageClassName,omitempty"`
+}
+
+// StateStoreStatus defines the observed state of StateStore
+// It's how the WorkflowRuntimes connect to the store.
+type StateStoreStatus struct {
+	// Host is the host name the schedulers connect to
+	// +optional
+	Host string `json:"host,omitempty"`
+	// Port is the port the schedulers connect to
+	// +optional
+	Port int32 `json:"port,omitempty"`
+	// PasswordSecret is the key of the Secret holding the password, nil if the store requires no password
+	// +optional
+	PasswordSecret *corev1.SecretKeySelector `json:"passwordSecret,omitempty"`
+	// Ready is whether the store can be connected to
+	// +optional
+	Ready bool `json:"ready,omitempty"`
+	// Message is why the store is not ready
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Host",type=string,JSONPath=`.status.host`
+// +kubebuilder:printcolumn:name="Port",type=integer,JSONPath=`.status.port`
+// +kubebuilder:printcolumn:name="Ready",type=boolean,JSONPath=`.status.ready`
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+
+// StateStore is the Schema for the statestores API
+type StateStore struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   StateStoreSpec   `json:"spec,omitempty"`
+	Status StateStoreStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// StateStoreList contains a list of StateStore
+type StateStoreList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []StateStore `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&StateStore{}, &StateStoreList{})
+}
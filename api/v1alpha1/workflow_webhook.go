@@ -0,0 +1,131 @@
+/*
+
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package v1alpha1
+
+import (
+	"strings"
+
+	ctrl "sigs.k8s.io/controller-runtime"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+)
+
+// log is for logging in this package.
+var workflowlog = logf.Log.WithName("workflow-resource")
+
+// SetupWebhookWithManager registers the webhooks of Workflow, the conversion webhook is served at /convert
+func (r *Workflow) SetupWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).
+		For(r).
+		Complete()
+}
+
+// +kubebuilder:webhook:path=/mutate-serverless-tass-io-v1alpha1-workflow,mutating=true,failurePolicy=fail,groups=serverless.tass.io,resources=workflows,verbs=create;update,versions=v1alpha1,name=mworkflow.kb.io
+
+var _ webhook.Defaulter = &Workflow{}
+
+// Default implements webhook.Defaulter so a webhook will be registered for the type
+// It makes the semantics the runtime relies on explicit in the stored object:
+// - An empty Statement is "direct"
+// - An empty Target of a predicate Condition or a Foreach is "$"
+// - An empty Role is inferred from the graph: "orphan" for the Flow of a single-flow workflow,
+//   "start" for the only Flow without upstream Flows, and "end" for the Flows without Outputs
+// - The keys of Env are trimmed, the entries with an empty key are dropped
+func (r *Workflow) Default() {
+	workflowlog.Info("default", "name", r.Name)
+
+	for i := range r.Spec.Spec {
+		flow := &r.Spec.Spec[i]
+		if flow.Statement == "" {
+			flow.Statement = Direct
+		}
+		if flow.Foreach != nil && flow.Foreach.Target == "" {
+			flow.Foreach.Target = "$"
+		}
+		for _, condition := range flow.Conditions {
+			if condition == nil || condition.Target != "" {
+				continue
+			}
+			if len(condition.All) != 0 || len(condition.Any) != 0 || condition.Not != "" {
+				// a logical group doesn't compare anything
+				continue
+			}
+			condition.Target = "$"
+		}
+	}
+	r.defaultRoles()
+	r.defaultEnv()
+}
+
+// defaultRoles infers the Roles which are not specified
+func (r *Workflow) defaultRoles() {
+	flows := r.Spec.Spec
+	if len(flows) == 1 {
+		if flows[0].Role == "" {
+			flows[0].Role = Orphan
+		}
+		return
+	}
+
+	// the upstream Flows include the ones whose errors go to the Flow
+	predecessors := map[string]int{}
+	for _, flow := range flows {
+		for _, next := range flow.Outputs {
+			predecessors[next]++
+		}
+		for _, handler := range flow.OnError {
+			for _, next := range handler.Flows {
+				predecessors[next]++
+			}
+		}
+	}
+	entrances := 0
+	for _, flow := range flows {
+		if predecessors[flow.Name] == 0 {
+			entrances++
+		}
+	}
+
+	for i := range flows {
+		flow := &flows[i]
+		if flow.Role != "" {
+			continue
+		}
+		switch {
+		case predecessors[flow.Name] == 0 && entrances == 1:
+			flow.Role = Start
+		case len(flow.Outputs) == 0:
+			flow.Role = End
+		}
+	}
+}
+
+// defaultEnv trims the keys of Env and drops the entries with an empty key
+func (r *Workflow) defaultEnv() {
+	if r.Spec.Env == nil {
+		return
+	}
+	env := make(map[string]string, len(r.Spec.Env))
+	for k, v := range r.Spec.Env {
+		if k = strings.TrimSpace(k); k != "" {
+			env[k] = v
+		}
+	}
+	if len(env) == 0 {
+		env = nil
+	}
+	r.Spec.Env = env
+}
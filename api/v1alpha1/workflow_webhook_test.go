@@ -0,0 +1,123 @@
+package v1alpha1
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestWorkflowDefault(t *testing.T) {
+	tests := []struct {
+		name  string
+		flows []Flow
+		want  map[string]Role
+	}{
+		{
+			name:  "single flow",
+			flows: []Flow{{Name: "a"}},
+			want:  map[string]Role{"a": Orphan},
+		},
+		{
+			name: "chain",
+			flows: []Flow{
+				{Name: "a", Outputs: []string{"b"}},
+				{Name: "b", Outputs: []string{"c"}},
+				{Name: "c"},
+			},
+			want: map[string]Role{"a": Start, "b": "", "c": End},
+		},
+		{
+			name: "error handler is not an entrance",
+			flows: []Flow{
+				{Name: "a", Outputs: []string{"b"}, OnError: []ErrorHandler{{Flows: []string{"compensate"}}}},
+				{Name: "b"},
+				{Name: "compensate"},
+			},
+			want: map[string]Role{"a": Start, "b": End, "compensate": End},
+		},
+		{
+			name: "specified roles are kept",
+			flows: []Flow{
+				{Name: "a", Outputs: []string{"b"}, Role: Orphan},
+				{Name: "b", Role: Start},
+			},
+			want: map[string]Role{"a": Orphan, "b": Start},
+		},
+		{
+			name: "two entrances",
+			flows: []Flow{
+				{Name: "a", Outputs: []string{"c"}},
+				{Name: "b", Outputs: []string{"c"}},
+				{Name: "c"},
+			},
+			want: map[string]Role{"a": "", "b": "", "c": End},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			wf := &Workflow{Spec: WorkflowSpec{Spec: tt.flows}}
+			wf.Default()
+			for _, flow := range wf.Spec.Spec {
+				if flow.Role != tt.want[flow.Name] {
+					t.Errorf("flow %s role = %q, want %q", flow.Name, flow.Role, tt.want[flow.Name])
+				}
+				if flow.Statement != Direct {
+					t.Errorf("flow %s statement = %q, want %q", flow.Name, flow.Statement, Direct)
+				}
+			}
+		})
+	}
+}
+
+func TestWorkflowDefaultTargets(t *testing.T) {
+	wf := &Workflow{Spec: WorkflowSpec{Spec: []Flow{{
+		Name:      "a",
+		Statement: Switch,
+		Outputs:   []string{"b"},
+		Conditions: []*Condition{
+			{Name: "root", All: []string{"big"}},
+			{Name: "big", Type: Int, Operator: Gt, Comparison: "10"},
+			{Name: "vip", Type: Bool, Operator: Eq, Target: "$.vip", Comparison: "true"},
+		},
+	}, {
+		Name:      "b",
+		Statement: Foreach,
+		Foreach:   &Iteration{},
+	}}}}
+	wf.Default()
+
+	flows := wf.Spec.Spec
+	if flows[0].Statement != Switch || flows[1].Statement != Foreach {
+		t.Errorf("specified statements should be kept, got %q and %q", flows[0].Statement, flows[1].Statement)
+	}
+	targets := []string{}
+	for _, c := range flows[0].Conditions {
+		targets = append(targets, c.Target)
+	}
+	if want := []string{"", "$", "$.vip"}; !reflect.DeepEqual(targets, want) {
+		t.Errorf("condition targets = %q, want %q", targets, want)
+	}
+	if flows[1].Foreach.Target != "$" {
+		t.Errorf("foreach target = %q, want %q", flows[1].Foreach.Target, "$")
+	}
+}
+
+func TestWorkflowDefaultEnv(t *testing.T) {
+	tests := []struct {
+		name string
+		env  map[string]string
+		want map[string]string
+	}{
+		{"nil", nil, nil},
+		{"trimmed", map[string]string{" KEY ": "v", "OTHER": " kept "}, map[string]string{"KEY": "v", "OTHER": " kept "}},
+		{"empty key dropped", map[string]string{" ": "v"}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			wf := &Workflow{Spec: WorkflowSpec{Env: tt.env}}
+			wf.Default()
+			if !reflect.DeepEqual(wf.Spec.Env, tt.want) {
+				t.Errorf("env = %v, want %v", wf.Spec.Env, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,135 @@
+/*
+
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// WorkflowExecutionSpec defines the desired state of WorkflowExecution
+// A WorkflowExecution is the record of a Workflow invocation.
+// Users create a WorkflowExecution to trigger a run, the operator invokes the Workflow
+// and records the result in the status.
+// The schedulers create WorkflowExecutions with Reported set for the invocations they served,
+// and fill the status themselves.
+type WorkflowExecutionSpec struct {
+	// Workflow is the name of the Workflow in the same namespace
+	Workflow string `json:"workflow"`
+	// Input is the JSON input of the invocation, e.g. {"amount": 120}
+	// +optional
+	Input string `json:"input,omitempty"`
+	// Flow is the Flow the invocation starts from, the entrance of the Workflow if empty
+	// +optional
+	Flow string `json:"flow,omitempty"`
+	// Reported means the invocation has been served by a scheduler and the WorkflowExecution is
+	// only a record of it, the operator never triggers a run for a reported WorkflowExecution
+	// +optional
+	Reported bool `json:"reported,omitempty"`
+}
+
+// WorkflowExecutionStatus defines the observed state of WorkflowExecution
+type WorkflowExecutionStatus struct {
+	// Phase is the phase of the invocation
+	// +optional
+	Phase ExecutionPhase `json:"phase,omitempty"`
+	// StartTime is the time the invocation started
+	// +optional
+	StartTime *metav1.Time `json:"startTime,omitempty"`
+	// CompletionTime is the time the invocation succeeded or failed
+	// +optional
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
+	// Flows lists the Flows the invocation went through in the order they started
+	// +optional
+	Flows []FlowExecution `json:"flows,omitempty"`
+	// Decisions lists the branch decisions made on the way
+	// +optional
+	Decisions []BranchDecision `json:"decisions,omitempty"`
+	// Output is the JSON result of the invocation
+	// +optional
+	Output string `json:"output,omitempty"`
+	// Error is the error the invocation failed with
+	// +optional
+	Error *ExecutionError `json:"error,omitempty"`
+}
+
+// ExecutionPhase is the phase of a WorkflowExecution
+// +kubebuilder:validation:Enum=Running;Succeeded;Failed
+type ExecutionPhase string
+
+const (
+	// ExecutionRunning means the Workflow has been invoked and the result hasn't come back
+	ExecutionRunning ExecutionPhase = "Running"
+	// ExecutionSucceeded means the invocation returned a result
+	ExecutionSucceeded ExecutionPhase = "Succeeded"
+	// ExecutionFailed means the invocation returned an error, or it could not be invoked
+	ExecutionFailed ExecutionPhase = "Failed"
+)
+
+// FlowExecution records how a Flow ran in an invocation
+type FlowExecution struct {
+	// Flow is the name of the Flow
+	Flow string `json:"flow"`
+	// Instance is the name of the instance, the Pod, which served the Flow
+	// +optional
+	Instance string `json:"instance,omitempty"`
+	// StartTime is the time the Flow started
+	// +optional
+	StartTime *metav1.Time `json:"startTime,omitempty"`
+	// EndTime is the time the Flow returned
+	// +optional
+	EndTime *metav1.Time `json:"endTime,omitempty"`
+	// Error is the error the Function of the Flow failed with
+	// +optional
+	Error *ExecutionError `json:"error,omitempty"`
+}
+
+// ExecutionError is an error reported by the Function runtime or the scheduler
+type ExecutionError struct {
+	// Type is the type of the error, e.g. "TimeoutError"
+	// +optional
+	Type string `json:"type,omitempty"`
+	// Message is the error message
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Workflow",type=string,JSONPath=`.spec.workflow`
+// +kubebuilder:printcolumn:name="Phase",type=string,JSONPath=`.status.phase`
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+
+// WorkflowExecution is the Schema for the workflowexecutions API
+type WorkflowExecution struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   WorkflowExecutionSpec   `json:"spec,omitempty"`
+	Status WorkflowExecutionStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// WorkflowExecutionList contains a list of WorkflowExecution
+type WorkflowExecutionList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []WorkflowExecution `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&WorkflowExecution{}, &WorkflowExecutionList{})
+}
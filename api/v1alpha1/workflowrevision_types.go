@@ -0,0 +1,83 @@
+/*
+
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// WorkflowRevisionSpec defines the snapshot of a Workflow
+// A WorkflowRevision is created by the operator for each accepted change of the Workflow,
+// like the ControllerRevision of a StatefulSet, it's owned by the Workflow and is never changed
+// except Revision, which is bumped when the Workflow goes back to the same snapshot.
+type WorkflowRevisionSpec struct {
+	// Workflow is the name of the Workflow in the same namespace
+	Workflow string `json:"workflow"`
+	// Revision is the sequence number of the snapshot, it increases with each change of the Workflow
+	// +kubebuilder:validation:Minimum=1
+	Revision int64 `json:"revision"`
+	// Hash is the hash of the fields the WorkflowRuntime serves, the same as the revision in the rollout status
+	Hash string `json:"hash"`
+	// Snapshot is the WorkflowSpec accepted
+	Snapshot WorkflowSpec `json:"snapshot"`
+	// Functions lists the Functions the Flows resolved to when the snapshot was taken
+	// +optional
+	Functions []FunctionSnapshot `json:"functions,omitempty"`
+}
+
+// FunctionSnapshot is the version of a Function a WorkflowRevision resolved to
+type FunctionSnapshot struct {
+	// Name is the name of the Function
+	Name string `json:"name"`
+	// ResourceVersion is the resourceVersion of the Function when the snapshot was taken
+	// +optional
+	ResourceVersion string `json:"resourceVersion,omitempty"`
+	// Spec is the FunctionSpec when the snapshot was taken
+	// +optional
+	Spec *FunctionSpec `json:"spec,omitempty"`
+}
+
+// WorkflowRevisionStatus defines the observed state of WorkflowRevision
+type WorkflowRevisionStatus struct {
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:printcolumn:name="Workflow",type=string,JSONPath=`.spec.workflow`
+// +kubebuilder:printcolumn:name="Revision",type=integer,JSONPath=`.spec.revision`
+// +kubebuilder:printcolumn:name="Hash",type=string,JSONPath=`.spec.hash`
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+
+// WorkflowRevision is the Schema for the workflowrevisions API
+type WorkflowRevision struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   WorkflowRevisionSpec   `json:"spec,omitempty"`
+	Status WorkflowRevisionStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// WorkflowRevisionList contains a list of WorkflowRevision
+type WorkflowRevisionList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []WorkflowRevision `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&WorkflowRevision{}, &WorkflowRevisionList{})
+}
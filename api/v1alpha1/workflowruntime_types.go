@@ -33,6 +33,45 @@ type WorkflowRuntimeSpec struct {
 	// +optional
 	Replicas *int32 `json:"replicas,omitempty"`
 
+	// Deadline is the upper bound of the time a whole workflow invocation may take
+	// It is copied from the Workflow by the operator
+	// +optional
+	Deadline *metav1.Duration `json:"deadline,omitempty"`
+
+	// FlowTimeouts records the timeout of each Flow which has claimed one
+	// The key is the name of the Flow
+	// It is copied from the Workflow by the operator
+	// +optional
+	FlowTimeouts map[string]metav1.Duration `json:"flowTimeouts,omitempty"`
+
+	// Revision is the revision of the Workflow the runtime serves
+	// It is set by the operator
+	// +optional
+	Revision string `json:"revision,omitempty"`
+
+	// Flows is the snapshot of the Flows of the Revision, the schedulers run the Flows here
+	// so that the runtimes of two revisions of a Workflow can serve side by side during a rollout.
+	// It is copied from the Workflow by the operator
+	// +optional
+	Flows []Flow `json:"flows,omitempty"`
+
+	// Env is the environment variables of the Workflow, they are set on the scheduler container
+	// and inherited by the function processes it starts
+	// It is copied from the Workflow by the operator
+	// +optional
+	Env map[string]string `json:"env,omitempty"`
+
+	// StateStore is the name of the StateStore in the same namespace the schedulers coordinate through,
+	// its connection is passed to the schedulers, the default Redis is used if no value is specified
+	// It is copied from the Workflow by the operator
+	// +optional
+	StateStore string `json:"stateStore,omitempty"`
+
+	// SecurityContext configures the security context of the scheduler container
+	// It is copied from the Workflow by the operator
+	// +optional
+	SecurityContext *SchedulerSecurity `json:"securityContext,omitempty"`
+
 	// TODO: Add some fields
 
 	// FIXME: Here we add status in Spec, logically put them into Status are resonable
@@ -57,6 +96,14 @@ type WorkflowRuntimeStatus struct {
 
 }
 
+const (
+	// PlaceholderInstance is the Instance a WorkflowRuntime is created with, it's not a Pod
+	// The Instances should not be empty, or the first JSON patch adding a Pod to them fails.
+	PlaceholderInstance = "init"
+	// PlaceholderPodIP is the Pod IP of the PlaceholderInstance
+	PlaceholderPodIP = "localhost"
+)
+
 // Instances is a Pod List that WorkflowRuntime manages
 // When the Deployment created or updated, Instances should be updated
 // The key is the name of the Pod, for example "sample-c65c4f67-skbml"
@@ -93,6 +140,7 @@ type ProcessRuntime struct {
 }
 
 // +kubebuilder:object:root=true
+// +kubebuilder:storageversion
 
 // WorkflowRuntime is the Schema for the workflowruntimes API
 type WorkflowRuntime struct {
@@ -0,0 +1,144 @@
+/*
+
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// WorkflowTestSpec defines the desired state of WorkflowTest
+// A WorkflowTest is a dry run of a Workflow, no Function is really invoked.
+// Users supply an input and the mock results of the Flows,
+// the operator computes the path through the Flows and writes it to the status.
+type WorkflowTestSpec struct {
+	// Workflow is the name of the Workflow under test in the same namespace
+	Workflow string `json:"workflow"`
+	// Input is the JSON input of the workflow invocation, e.g. {"amount": 120}
+	// It is the input of the entrance Flow.
+	// +optional
+	Input string `json:"input,omitempty"`
+	// Mocks lists the mock results of the Flows
+	// A Flow without a mock passes its input to the downstream Flows as its result
+	// +optional
+	Mocks []FlowMock `json:"mocks,omitempty"`
+}
+
+// FlowMock is the mock result of a Flow in a WorkflowTest
+// One and only one of Output and Error can be specified
+type FlowMock struct {
+	// Flow is the name of the Flow
+	Flow string `json:"flow"`
+	// Output is the JSON result of the user code, e.g. {"vip": true}
+	// It is wrapped as {"$": {"vip": true}} like the Flow runtime does
+	// +optional
+	Output string `json:"output,omitempty"`
+	// Error mocks a failure of the Function, it goes to the OnError handlers of the Flow
+	// +optional
+	Error *MockError `json:"error,omitempty"`
+}
+
+// MockError is the error a mocked Flow fails with
+type MockError struct {
+	// Type is the type of the error, e.g. "TimeoutError"
+	// +optional
+	Type string `json:"type,omitempty"`
+	// Message is the error message
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+// WorkflowTestStatus defines the observed state of WorkflowTest
+type WorkflowTestStatus struct {
+	// Phase is the result of the dry run
+	// +optional
+	Phase WorkflowTestPhase `json:"phase,omitempty"`
+	// ObservedGeneration is the generation of the WorkflowTest the status is computed from
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+	// WorkflowGeneration is the generation of the Workflow the status is computed from
+	// +optional
+	WorkflowGeneration int64 `json:"workflowGeneration,omitempty"`
+	// VisitedFlows lists the Flows the invocation goes through in the order they run
+	// +optional
+	VisitedFlows []string `json:"visitedFlows,omitempty"`
+	// Decisions lists the branch decisions made on the way
+	// +optional
+	Decisions []BranchDecision `json:"decisions,omitempty"`
+	// DeadEnds lists the Flows where the invocation stops unexpectedly
+	// +optional
+	DeadEnds []DeadEnd `json:"deadEnds,omitempty"`
+	// Message is a human readable message of the dry run
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+// WorkflowTestPhase is the result of a WorkflowTest
+// +kubebuilder:validation:Enum=Succeeded;Failed
+type WorkflowTestPhase string
+
+const (
+	// TestSucceeded means the invocation reached an exit of the Workflow without any dead end
+	TestSucceeded WorkflowTestPhase = "Succeeded"
+	// TestFailed means the invocation has dead ends, or the WorkflowTest itself is illegal
+	TestFailed WorkflowTestPhase = "Failed"
+)
+
+// BranchDecision records a decision made when the result of a Flow goes to the downstream
+type BranchDecision struct {
+	// Flow is the name of the Flow
+	Flow string `json:"flow"`
+	// Condition is the name of the Condition evaluated,
+	// or "onError[i]" when the error goes to the i-th OnError handler
+	Condition string `json:"condition"`
+	// Result is whether the Condition is satisfied
+	Result bool `json:"result"`
+}
+
+// DeadEnd records a Flow where the invocation stops unexpectedly
+type DeadEnd struct {
+	// Flow is the name of the Flow
+	Flow string `json:"flow"`
+	// Reason describes why the invocation stops
+	Reason string `json:"reason"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Workflow",type=string,JSONPath=`.spec.workflow`
+// +kubebuilder:printcolumn:name="Phase",type=string,JSONPath=`.status.phase`
+
+// WorkflowTest is the Schema for the workflowtests API
+type WorkflowTest struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   WorkflowTestSpec   `json:"spec,omitempty"`
+	Status WorkflowTestStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// WorkflowTestList contains a list of WorkflowTest
+type WorkflowTestList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []WorkflowTest `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&WorkflowTest{}, &WorkflowTestList{})
+}
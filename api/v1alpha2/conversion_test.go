@@ -0,0 +1,225 @@
+package v1alpha2
+
+import (
+	"math/rand"
+	"testing"
+	"time"
+
+	fuzz "github.com/google/gofuzz"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/diff"
+
+	"github.com/tass-io/tass-operator/api/v1alpha1"
+)
+
+const fuzzIterations = 200
+
+// newFuzzer returns a fuzzer filling the values the API server could store,
+// the times are in seconds as they are serialized in RFC 3339.
+// The empty slices and maps become nil through the JSON, they are equal in the semantic comparison.
+func newFuzzer(seed int64) *fuzz.Fuzzer {
+	return fuzz.New().RandSource(rand.NewSource(seed)).NilChance(0.2).NumElements(0, 3).Funcs(
+		func(t *metav1.Time, c fuzz.Continue) {
+			*t = metav1.Unix(c.Int63n(1<<32), 0)
+		},
+		func(d *metav1.Duration, c fuzz.Continue) {
+			d.Duration = time.Duration(c.Int63n(1<<40)) * time.Millisecond
+		},
+		func(q *resource.Quantity, c fuzz.Continue) {
+			*q = *resource.NewMilliQuantity(c.Int63n(1<<20), resource.DecimalSI)
+		},
+		func(m *metav1.TypeMeta, c fuzz.Continue) {
+			// the conversion webhook sets the TypeMeta
+		},
+		func(m *metav1.ObjectMeta, c fuzz.Continue) {
+			c.Fuzz(&m.Name)
+			c.Fuzz(&m.Namespace)
+			c.Fuzz(&m.Labels)
+			c.Fuzz(&m.Annotations)
+			delete(m.Annotations, ResourceAnnotation)
+		},
+	)
+}
+
+func TestWorkflowRoundTrip(t *testing.T) {
+	for i := 0; i < fuzzIterations; i++ {
+		f := newFuzzer(int64(i))
+
+		hub := &v1alpha1.Workflow{}
+		f.Fuzz(hub)
+		spoke := &Workflow{}
+		if err := spoke.ConvertFrom(hub.DeepCopy()); err != nil {
+			t.Fatalf("ConvertFrom() error = %v", err)
+		}
+		back := &v1alpha1.Workflow{}
+		if err := spoke.ConvertTo(back); err != nil {
+			t.Fatalf("ConvertTo() error = %v", err)
+		}
+		if !equality.Semantic.DeepEqual(hub, back) {
+			t.Fatalf("v1alpha1 round trip lost data:\n%s", diff.ObjectReflectDiff(hub, back))
+		}
+
+		spoke = &Workflow{}
+		f.Fuzz(spoke)
+		hub = &v1alpha1.Workflow{}
+		if err := spoke.DeepCopy().ConvertTo(hub); err != nil {
+			t.Fatalf("ConvertTo() error = %v", err)
+		}
+		spokeBack := &Workflow{}
+		if err := spokeBack.ConvertFrom(hub); err != nil {
+			t.Fatalf("ConvertFrom() error = %v", err)
+		}
+		if !equality.Semantic.DeepEqual(spoke, spokeBack) {
+			t.Fatalf("v1alpha2 round trip lost data:\n%s", diff.ObjectReflectDiff(spoke, spokeBack))
+		}
+	}
+}
+
+func TestWorkflowFlowsMoved(t *testing.T) {
+	hub := &v1alpha1.Workflow{Spec: v1alpha1.WorkflowSpec{
+		Spec: []v1alpha1.Flow{{Name: "a", Function: "f", Statement: v1alpha1.Direct, Role: v1alpha1.Orphan}},
+	}}
+	spoke := &Workflow{}
+	if err := spoke.ConvertFrom(hub); err != nil {
+		t.Fatalf("ConvertFrom() error = %v", err)
+	}
+	if len(spoke.Spec.Flows) != 1 || spoke.Spec.Flows[0].Name != "a" || spoke.Spec.Flows[0].Role != Orphan {
+		t.Errorf("ConvertFrom() flows = %+v, want the flow a", spoke.Spec.Flows)
+	}
+}
+
+func TestWorkflowRuntimeRoundTrip(t *testing.T) {
+	for i := 0; i < fuzzIterations; i++ {
+		f := newFuzzer(int64(i))
+
+		hub := &v1alpha1.WorkflowRuntime{}
+		f.Fuzz(hub)
+		if hub.Spec == nil {
+			// a WorkflowRuntime without spec gets an empty one, see TestWorkflowRuntimeNilSpec
+			hub.Spec = &v1alpha1.WorkflowRuntimeSpec{}
+		}
+		spoke := &WorkflowRuntime{}
+		if err := spoke.ConvertFrom(hub.DeepCopy()); err != nil {
+			t.Fatalf("ConvertFrom() error = %v", err)
+		}
+		back := &v1alpha1.WorkflowRuntime{}
+		if err := spoke.ConvertTo(back); err != nil {
+			t.Fatalf("ConvertTo() error = %v", err)
+		}
+		if !equality.Semantic.DeepEqual(hub, back) {
+			t.Fatalf("v1alpha1 round trip lost data:\n%s", diff.ObjectReflectDiff(hub, back))
+		}
+
+		spoke = &WorkflowRuntime{}
+		f.Fuzz(spoke)
+		hub = &v1alpha1.WorkflowRuntime{}
+		if err := spoke.DeepCopy().ConvertTo(hub); err != nil {
+			t.Fatalf("ConvertTo() error = %v", err)
+		}
+		spokeBack := &WorkflowRuntime{}
+		if err := spokeBack.ConvertFrom(hub); err != nil {
+			t.Fatalf("ConvertFrom() error = %v", err)
+		}
+		if !equality.Semantic.DeepEqual(spoke, spokeBack) {
+			t.Fatalf("v1alpha2 round trip lost data:\n%s", diff.ObjectReflectDiff(spoke, spokeBack))
+		}
+	}
+}
+
+func TestWorkflowRuntimeNilSpec(t *testing.T) {
+	spoke := &WorkflowRuntime{}
+	if err := spoke.ConvertFrom(&v1alpha1.WorkflowRuntime{}); err != nil {
+		t.Fatalf("ConvertFrom() error = %v", err)
+	}
+	hub := &v1alpha1.WorkflowRuntime{}
+	if err := spoke.ConvertTo(hub); err != nil {
+		t.Fatalf("ConvertTo() error = %v", err)
+	}
+	if hub.Spec == nil {
+		t.Errorf("ConvertTo() should always set the spec")
+	}
+}
+
+func TestFunctionRoundTrip(t *testing.T) {
+	for i := 0; i < fuzzIterations; i++ {
+		f := newFuzzer(int64(i))
+
+		// the strings fuzzed are mostly not quantities, they are kept in the annotation
+		hub := &v1alpha1.Function{}
+		f.Fuzz(hub)
+		spoke := &Function{}
+		if err := spoke.ConvertFrom(hub.DeepCopy()); err != nil {
+			t.Fatalf("ConvertFrom() error = %v", err)
+		}
+		back := &v1alpha1.Function{}
+		if err := spoke.ConvertTo(back); err != nil {
+			t.Fatalf("ConvertTo() error = %v", err)
+		}
+		if !equality.Semantic.DeepEqual(hub, back) {
+			t.Fatalf("v1alpha1 round trip lost data:\n%s", diff.ObjectReflectDiff(hub, back))
+		}
+
+		spoke = &Function{}
+		f.Fuzz(spoke)
+		hub = &v1alpha1.Function{}
+		if err := spoke.DeepCopy().ConvertTo(hub); err != nil {
+			t.Fatalf("ConvertTo() error = %v", err)
+		}
+		spokeBack := &Function{}
+		if err := spokeBack.ConvertFrom(hub); err != nil {
+			t.Fatalf("ConvertFrom() error = %v", err)
+		}
+		if !equality.Semantic.DeepEqual(spoke, spokeBack) {
+			t.Fatalf("v1alpha2 round trip lost data:\n%s", diff.ObjectReflectDiff(spoke, spokeBack))
+		}
+	}
+}
+
+func TestFunctionResource(t *testing.T) {
+	tests := []struct {
+		name           string
+		cpu, memory    string
+		wantAnnotation bool
+		changedCPU     string
+		wantCPU        string
+	}{
+		{name: "canonical", cpu: "500m", memory: "128Mi", wantCPU: "500m"},
+		{name: "not canonical", cpu: "0.5", memory: "1024Mi", wantAnnotation: true, wantCPU: "0.5"},
+		{name: "not a quantity", cpu: "half", memory: "128Mi", wantAnnotation: true, wantCPU: "half"},
+		{name: "changed in v1alpha2", cpu: "0.5", memory: "128Mi", wantAnnotation: true,
+			changedCPU: "1", wantCPU: "1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hub := &v1alpha1.Function{Spec: v1alpha1.FunctionSpec{
+				Environment: v1alpha1.Golang,
+				Resource:    v1alpha1.Resource{ResourceCPU: tt.cpu, ResourceMemory: tt.memory},
+			}}
+			spoke := &Function{}
+			if err := spoke.ConvertFrom(hub); err != nil {
+				t.Fatalf("ConvertFrom() error = %v", err)
+			}
+			if _, ok := spoke.Annotations[ResourceAnnotation]; ok != tt.wantAnnotation {
+				t.Errorf("ConvertFrom() annotation = %v, want %v", ok, tt.wantAnnotation)
+			}
+			if tt.changedCPU != "" {
+				spoke.Spec.Resource.CPU = resource.MustParse(tt.changedCPU)
+			}
+			back := &v1alpha1.Function{}
+			if err := spoke.ConvertTo(back); err != nil {
+				t.Fatalf("ConvertTo() error = %v", err)
+			}
+			if back.Spec.Resource.ResourceCPU != tt.wantCPU {
+				t.Errorf("ConvertTo() cpu = %q, want %q", back.Spec.Resource.ResourceCPU, tt.wantCPU)
+			}
+			if back.Spec.Resource.ResourceMemory != tt.memory {
+				t.Errorf("ConvertTo() memory = %q, want %q", back.Spec.Resource.ResourceMemory, tt.memory)
+			}
+			if _, ok := back.Annotations[ResourceAnnotation]; ok {
+				t.Errorf("ConvertTo() should drop the annotation")
+			}
+		})
+	}
+}
@@ -0,0 +1,115 @@
+/*
+
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package v1alpha2
+
+import (
+	"encoding/json"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+	"sigs.k8s.io/controller-runtime/pkg/conversion"
+
+	"github.com/tass-io/tass-operator/api/v1alpha1"
+)
+
+// ResourceAnnotation keeps the Resource of a v1alpha1 Function which is not in the canonical form,
+// e.g. "0.5" for "500m", or not a quantity at all, so that converting it back to v1alpha1 loses nothing
+const ResourceAnnotation = "serverless.tass.io/v1alpha1-resource"
+
+// ConvertTo converts the Function to the hub version v1alpha1, the quantities are written in the canonical form
+// unless they are unchanged since the Function was converted from v1alpha1
+func (src *Function) ConvertTo(dstRaw conversion.Hub) error {
+	dst := dstRaw.(*v1alpha1.Function)
+	dst.ObjectMeta = src.ObjectMeta
+	dst.Spec = v1alpha1.FunctionSpec{
+		Environment: v1alpha1.Environment(src.Spec.Environment),
+		Resource: v1alpha1.Resource{
+			ResourceCPU:    src.Spec.Resource.CPU.String(),
+			ResourceMemory: src.Spec.Resource.Memory.String(),
+		},
+	}
+	dst.Status = v1alpha1.FunctionStatus{}
+
+	original, ok := src.Annotations[ResourceAnnotation]
+	if !ok {
+		return nil
+	}
+	dst.Annotations = make(map[string]string, len(src.Annotations))
+	for k, v := range src.Annotations {
+		if k != ResourceAnnotation {
+			dst.Annotations[k] = v
+		}
+	}
+	if len(dst.Annotations) == 0 {
+		dst.Annotations = nil
+	}
+	var resource v1alpha1.Resource
+	if err := json.Unmarshal([]byte(original), &resource); err != nil {
+		// the annotation is broken, the canonical form is used
+		return nil
+	}
+	if canonical(resource.ResourceCPU) == dst.Spec.Resource.ResourceCPU {
+		dst.Spec.Resource.ResourceCPU = resource.ResourceCPU
+	}
+	if canonical(resource.ResourceMemory) == dst.Spec.Resource.ResourceMemory {
+		dst.Spec.Resource.ResourceMemory = resource.ResourceMemory
+	}
+	return nil
+}
+
+// ConvertFrom converts the Function from the hub version v1alpha1,
+// the Resource not in the canonical form is kept in the ResourceAnnotation
+func (dst *Function) ConvertFrom(srcRaw conversion.Hub) error {
+	src := srcRaw.(*v1alpha1.Function)
+	dst.ObjectMeta = src.ObjectMeta
+	dst.Spec = FunctionSpec{
+		Environment: Environment(src.Spec.Environment),
+		Resource: Resource{
+			CPU:    quantity(src.Spec.Resource.ResourceCPU),
+			Memory: quantity(src.Spec.Resource.ResourceMemory),
+		},
+	}
+	dst.Status = FunctionStatus{}
+
+	if dst.Spec.Resource.CPU.String() == src.Spec.Resource.ResourceCPU &&
+		dst.Spec.Resource.Memory.String() == src.Spec.Resource.ResourceMemory {
+		return nil
+	}
+	original, err := json.Marshal(&src.Spec.Resource)
+	if err != nil {
+		return err
+	}
+	dst.Annotations = make(map[string]string, len(src.Annotations)+1)
+	for k, v := range src.Annotations {
+		dst.Annotations[k] = v
+	}
+	dst.Annotations[ResourceAnnotation] = string(original)
+	return nil
+}
+
+// quantity parses the quantity of v1alpha1, the value which is not a quantity is taken as zero
+func quantity(value string) resource.Quantity {
+	q, err := resource.ParseQuantity(value)
+	if err != nil {
+		return resource.Quantity{}
+	}
+	return q
+}
+
+// canonical returns the canonical form of the quantity of v1alpha1
+func canonical(value string) string {
+	q := quantity(value)
+	return q.String()
+}
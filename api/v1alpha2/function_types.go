@@ -0,0 +1,81 @@
+/*
+
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package v1alpha2
+
+import (
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// FunctionSpec defines the desired state of Function
+type FunctionSpec struct {
+	// Environment represents the language environment of the code segments
+	// The scheduler wil then launch the corresponding language environment
+	Environment Environment `json:"environment"`
+	// Resource claims the resource provisioning for Function process
+	// It now contains cpu and memory
+	Resource Resource `json:"resource"`
+}
+
+// Resource claims the resource provisioning for Function process
+// The quantities are validated by the API server, they are free strings in v1alpha1
+type Resource struct {
+	// CPU, in cores. (500m = .5 cores)
+	CPU resource.Quantity `json:"cpu"`
+	// Memory, in bytes. (500Gi = 500GiB = 500 * 1024 * 1024 * 1024)
+	Memory resource.Quantity `json:"memory"`
+}
+
+// Environment defines the language environments that tass supports
+// +kubebuilder:validation:Enum=Golang;Python;JavaScript
+type Environment string
+
+const (
+	// Golang means the language environment is Golang
+	Golang Environment = "Golang"
+	// Python means the language environment is Python
+	Python Environment = "Python"
+	// JavaScript means the language environment is JavaScript
+	JavaScript Environment = "JavaScript"
+)
+
+// FunctionStatus defines the observed state of Function
+type FunctionStatus struct {
+}
+
+// +kubebuilder:object:root=true
+
+// Function is the Schema for the functions API
+type Function struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   FunctionSpec   `json:"spec,omitempty"`
+	Status FunctionStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// FunctionList contains a list of Function
+type FunctionList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []Function `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&Function{}, &FunctionList{})
+}
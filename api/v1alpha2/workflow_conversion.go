@@ -0,0 +1,52 @@
+/*
+
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package v1alpha2
+
+import (
+	"sigs.k8s.io/controller-runtime/pkg/conversion"
+
+	"github.com/tass-io/tass-operator/api/v1alpha1"
+)
+
+// ConvertTo converts the Workflow to the hub version v1alpha1, the Flows go back to "spec.spec"
+func (src *Workflow) ConvertTo(dstRaw conversion.Hub) error {
+	dst := dstRaw.(*v1alpha1.Workflow)
+	dst.ObjectMeta = src.ObjectMeta
+	dst.Spec = v1alpha1.WorkflowSpec{}
+	dst.Status = v1alpha1.WorkflowStatus{}
+	if err := convertJSON(&src.Spec, &dst.Spec); err != nil {
+		return err
+	}
+	if err := convertJSON(src.Spec.Flows, &dst.Spec.Spec); err != nil {
+		return err
+	}
+	return convertJSON(&src.Status, &dst.Status)
+}
+
+// ConvertFrom converts the Workflow from the hub version v1alpha1, "spec.spec" becomes "spec.flows"
+func (dst *Workflow) ConvertFrom(srcRaw conversion.Hub) error {
+	src := srcRaw.(*v1alpha1.Workflow)
+	dst.ObjectMeta = src.ObjectMeta
+	dst.Spec = WorkflowSpec{}
+	dst.Status = WorkflowStatus{}
+	if err := convertJSON(&src.Spec, &dst.Spec); err != nil {
+		return err
+	}
+	if err := convertJSON(src.Spec.Spec, &dst.Spec.Flows); err != nil {
+		return err
+	}
+	return convertJSON(&src.Status, &dst.Status)
+}
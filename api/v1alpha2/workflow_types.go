@@ -0,0 +1,729 @@
+/*
+
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha2
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// WorkflowSpec defines the desired state of Workflow
+type WorkflowSpec struct {
+	// Env is the environment variables for the Workflow
+	// It is defined by users, the scheduler and the function processes of the Workflow run with them.
+	// HOSTNAME and the names prefixed with TASS_ or KUBERNETES_ are reserved by the runtime.
+	// +optional
+	Env map[string]string `json:"env,omitempty"`
+
+	// Flows is the list of Flows, it's named "spec" in v1alpha1
+	Flows []Flow `json:"flows"`
+
+	// Deadline is the upper bound of the time a whole workflow invocation may take,
+	// e.g. "30s" or "5m". The timeouts of the Flows along the longest path
+	// of the workflow must fit under the Deadline.
+	// If no value is specified, the invocation has no time limit
+	// +optional
+	Deadline *metav1.Duration `json:"deadline,omitempty"`
+
+	// History claims how long the WorkflowExecutions of the Workflow are kept
+	// If no value is specified, the default limits are applied
+	// +optional
+	History *ExecutionHistory `json:"history,omitempty"`
+
+	// HTTP exposes the Workflow out of the cluster through an Ingress
+	// If no value is specified, the Workflow is only reachable in the cluster
+	// +optional
+	HTTP *HTTPTrigger `json:"http,omitempty"`
+
+	// Rollout claims how a change of the Workflow goes live, it requires the HTTPTrigger
+	// If no value is specified, a change goes live in one step
+	// +optional
+	Rollout *Rollout `json:"rollout,omitempty"`
+
+	// RevisionHistoryLimit is the number of the WorkflowRevisions kept for rolling back
+	// If no value is specified, it's 10
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	RevisionHistoryLimit *int32 `json:"revisionHistoryLimit,omitempty"`
+
+	// StateStore is the name of the StateStore in the same namespace the schedulers of the Workflow
+	// coordinate through, the default Redis of the runtime is used if no value is specified
+	// +optional
+	StateStore string `json:"stateStore,omitempty"`
+
+	// SecurityContext configures the security context of the scheduler container
+	// If no value is specified, the scheduler runs with the least privilege, see SchedulerSecurity
+	// +optional
+	SecurityContext *SchedulerSecurity `json:"securityContext,omitempty"`
+
+	// RollbackTo is the revision the Workflow is rolled back to
+	// The operator restores the Env, Flows and Deadline of the WorkflowRevision and clears the field,
+	// the other fields, like the triggers, are kept as they are. 0 means the previous revision.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	RollbackTo *int64 `json:"rollbackTo,omitempty"`
+}
+
+// ExecutionHistory claims the retention of the WorkflowExecutions of a Workflow
+// Only the finished WorkflowExecutions are removed, the running ones are always kept.
+// A sample of ExecutionHistory
+// ```yaml
+// history:
+//   ttl: 24h
+//   succeededLimit: 5
+//   failedLimit: 20
+// ```
+type ExecutionHistory struct {
+	// TTL is how long a WorkflowExecution is kept after it finished
+	// If no value is specified, the WorkflowExecutions are only removed by the limits
+	// +optional
+	TTL *metav1.Duration `json:"ttl,omitempty"`
+	// SucceededLimit is the number of succeeded WorkflowExecutions kept, the oldest ones are removed first
+	// If no value is specified, it's 10
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	SucceededLimit *int32 `json:"succeededLimit,omitempty"`
+	// FailedLimit is the number of failed WorkflowExecutions kept, the oldest ones are removed first
+	// If no value is specified, it's 10
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	FailedLimit *int32 `json:"failedLimit,omitempty"`
+}
+
+// HTTPTrigger claims the Ingress routing the requests to the WorkflowRuntime Service
+// The requests are rewritten to /v1/workflow/ of the local scheduler.
+// The Ingress relies on the annotations of ingress-nginx for the rewriting, the methods and the auth,
+// so the IngressClass should be served by ingress-nginx.
+// The methods are enforced by a configuration-snippet, which ingress-nginx only accepts
+// when allow-snippet-annotations is enabled in the ConfigMap of its controller.
+// A sample of HTTPTrigger
+// ```yaml
+// http:
+//   host: tass.example.com
+//   path: /orders
+//   methods:
+//   - POST
+//   tlsSecret: tass-example-com
+//   auth:
+//     type: basic
+//     secret: orders-htpasswd
+// ```
+type HTTPTrigger struct {
+	// Host is the host name the Ingress matches, e.g. "tass.example.com"
+	// If no value is specified, the Ingress matches any host,
+	// and the URL is reported with the address of the load balancer
+	// +optional
+	Host string `json:"host,omitempty"`
+	// Path is the path prefix the Ingress matches, it should start with "/"
+	// If no value is specified, it's "/<workflow name>"
+	// +optional
+	Path string `json:"path,omitempty"`
+	// Methods lists the HTTP methods accepted, others are denied
+	// If no value is specified, only POST is accepted
+	// +optional
+	Methods []HTTPMethod `json:"methods,omitempty"`
+	// IngressClass is the class of the Ingress, it's "nginx" by default
+	// It should be a class of ingress-nginx, named "nginx" or prefixed with "nginx-"
+	// +optional
+	IngressClass string `json:"ingressClass,omitempty"`
+	// TLSSecret is the name of the Secret holding the TLS certificate of the Host
+	// If specified, the Ingress terminates TLS and the URL is reported in https
+	// +optional
+	TLSSecret string `json:"tlsSecret,omitempty"`
+	// Auth claims how the requests are authenticated
+	// If no value is specified, the requests are not authenticated
+	// +optional
+	Auth *HTTPAuth `json:"auth,omitempty"`
+}
+
+// HTTPMethod is an HTTP method accepted by a HTTPTrigger
+// +kubebuilder:validation:Enum=GET;POST;PUT;PATCH;DELETE
+type HTTPMethod string
+
+// HTTPAuth claims how the requests of a HTTPTrigger are authenticated
+type HTTPAuth struct {
+	// Type is the type of the authentication
+	// Valid values are:
+	// - basic: The basic authentication, the Secret holds the htpasswd file in the key "auth";
+	// - external: The requests are sent to the URL first, and allowed when it responds 2xx;
+	Type HTTPAuthType `json:"type"`
+	// Secret is the name of the Secret for the basic authentication
+	// +optional
+	Secret string `json:"secret,omitempty"`
+	// URL is the URL of the external authentication service
+	// +optional
+	URL string `json:"url,omitempty"`
+}
+
+// HTTPAuthType is the type of the authentication of a HTTPTrigger
+// +kubebuilder:validation:Enum=basic;external
+type HTTPAuthType string
+
+const (
+	// BasicAuth is the basic authentication with a htpasswd Secret
+	BasicAuth HTTPAuthType = "basic"
+	// ExternalAuth is the authentication by an external service
+	ExternalAuth HTTPAuthType = "external"
+)
+
+// Rollout claims a canary rollout of the changes of a Workflow
+// The runtime of the previous revision keeps serving, and a canary runtime is created for the new revision.
+// The requests of the HTTPTrigger are split between them by the Weight of the steps, so it requires the HTTPTrigger.
+// The invocations in the cluster, i.e. the WorkflowExecutions, CronTriggers and EventTriggers,
+// keep going to the previous revision, so the canary is only checked on the requests of the HTTPTrigger.
+// After the Pause of each step, the canary is checked: it should be available,
+// and the failed WorkflowExecutions it served should not exceed MaxFailurePercent.
+// The new revision is promoted after the last step, or rolled back once a check fails.
+// A sample of Rollout
+// ```yaml
+// rollout:
+//   steps:
+//   - weight: 10
+//     pause: 5m
+//   - weight: 50
+//     pause: 10m
+//   maxFailurePercent: 5
+// ```
+type Rollout struct {
+	// Steps lists the weights the canary goes through
+	// +kubebuilder:validation:MinItems=1
+	Steps []RolloutStep `json:"steps"`
+	// MaxFailurePercent is the max percentage of the failed WorkflowExecutions the canary served in a step
+	// If no value is specified, it's 10
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=100
+	// +optional
+	MaxFailurePercent *int32 `json:"maxFailurePercent,omitempty"`
+	// MinExecutions is the number of the finished WorkflowExecutions the canary should serve in a step
+	// before the failure percentage is checked, the check passes with fewer WorkflowExecutions
+	// If no value is specified, it's 1
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	MinExecutions *int32 `json:"minExecutions,omitempty"`
+}
+
+// RolloutStep is a step of a Rollout
+type RolloutStep struct {
+	// Weight is the percentage of the requests the canary serves in the step
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=100
+	Weight int32 `json:"weight"`
+	// Pause is how long the step lasts before the canary is checked
+	Pause metav1.Duration `json:"pause"`
+}
+
+// SchedulerSecurity configures the security context of the scheduler container of a Workflow
+// The scheduler runs with the least privilege by default: as a non-root user, without privilege escalation,
+// with the runtime default seccomp profile and with all the capabilities dropped but NET_BIND_SERVICE,
+// which the scheduler needs to listen on port 80. It passes the "restricted" Pod Security Standard.
+// A sample of SchedulerSecurity opting in the privileged mode
+// ```yaml
+// securityContext:
+//   privileged: true
+// ```
+type SchedulerSecurity struct {
+	// Privileged runs the scheduler as a privileged container, the other fields are ignored then
+	// It's an opt-in for the function processes needing the access to the host, the Pods are rejected
+	// in the namespaces enforcing the "baseline" or the "restricted" Pod Security Standard.
+	// +optional
+	Privileged bool `json:"privileged,omitempty"`
+	// Capabilities are the Linux capabilities added to the scheduler, all the others are dropped
+	// If no value is specified, only NET_BIND_SERVICE is added
+	// +optional
+	Capabilities []corev1.Capability `json:"capabilities,omitempty"`
+	// RunAsNonRoot requires the scheduler to run as a non-root user
+	// If no value is specified, it's true
+	// +optional
+	RunAsNonRoot *bool `json:"runAsNonRoot,omitempty"`
+	// RunAsUser is the UID the scheduler runs as
+	// If no value is specified, it's 65532 when RunAsNonRoot is true and the user of the image otherwise
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	RunAsUser *int64 `json:"runAsUser,omitempty"`
+	// SeccompProfile is the seccomp profile of the scheduler,
+	// one of "runtime/default", "unconfined" and "localhost/<path of the profile>"
+	// It's set as the seccompProfile field of the security context of the scheduler container.
+	// If no value is specified, it's "runtime/default"
+	// +kubebuilder:validation:Pattern=`^(runtime/default|unconfined|localhost/.+)$`
+	// +optional
+	SeccompProfile string `json:"seccompProfile,omitempty"`
+	// ReadOnlyRootFilesystem mounts the root filesystem of the scheduler read-only,
+	// an emptyDir is mounted at /tmp for the files the scheduler and the function processes write
+	// +optional
+	ReadOnlyRootFilesystem bool `json:"readOnlyRootFilesystem,omitempty"`
+}
+
+// Flow defines the logic of a Function in a workflow
+type Flow struct {
+	// Name is the name of the flow which is unique in a workflow.
+	// A function may be called multiple times in different places in a workflow.
+	// So we need a Flow name to clear the logic.
+	Name string `json:"name"`
+	// Function is the function name which has been defined in Tass
+	// Function and Workflow are exclusive, one and only one of them should be specified
+	// +optional
+	Function string `json:"function,omitempty"`
+	// Workflow is the name of another Workflow in the same namespace which the flow invokes
+	// The input of the flow is sent to the WorkflowRuntime of that Workflow,
+	// and the result of that Workflow is the result of the flow.
+	// A Workflow must not include itself, directly or through other Workflows.
+	// Function and Workflow are exclusive, one and only one of them should be specified
+	// +optional
+	Workflow string `json:"workflow,omitempty"`
+	// Outputs specify where the result of this flow should go
+	// +optional
+	Outputs []string `json:"outputs"`
+	// Statement shows the flow control logic type
+	// Valid values are:
+	// - direct: The result of the flow go to downstream directly;
+	// - switch: The result of the flow go to downstream based on the switch condition;
+	// - foreach: The Function runs once per element of an array in the input,
+	// the aggregated result goes to downstream directly;
+	// If no value is specified, it's "direct"
+	// +optional
+	Statement Statement `json:"statement,omitempty"`
+	// Role is the role of the Flow
+	// Valid values are:
+	// - start: The role of the Flow is "start" which means it is the entrance of workflow instance
+	// - end: The role of the Flow is "end" which means it is the exit point of workflow instance
+	// - orphan: The role of the Flow is "orphan" which is a special case that
+	// the workflow instance has only one function
+	// If no value is specified, it means this is an intermediate Flow instance
+	// +optional
+	Role Role `json:"role,omitempty"`
+
+	// Foreach claims how the Function maps over an array in the input
+	// Only worked when the Statement is 'foreach'
+	// +optional
+	Foreach *Iteration `json:"foreach,omitempty"`
+
+	// Conditions are the control logic group of the flow
+	// The first element of the Conditions is the root control logic
+	// Only worked when the Statement is 'Switch'
+	// +optional
+	Conditions []*Condition `json:"conditions,omitempty"`
+
+	// OnError lists the error handlers of the flow
+	// When the Function of the flow fails, the error goes to the Flows of
+	// the first handler which matches the error, others are skipped.
+	// If no handler matches, the workflow invocation fails.
+	// +optional
+	OnError []ErrorHandler `json:"onError,omitempty"`
+
+	// Timeout is the upper bound of the time the Function of the Flow may run, e.g. "10s".
+	// When the Function doesn't return in time, the scheduler aborts the process.
+	// If no value is specified, the Flow has no time limit
+	// +optional
+	Timeout *metav1.Duration `json:"timeout,omitempty"`
+}
+
+// ErrorHandler routes the error of a Flow to compensation or fallback Flows
+// A sample of ErrorHandler
+// ```yaml
+// onError:
+// - type: TimeoutError
+//   flows:
+//   - retry-later
+// - message: "^quota .* exceeded$"
+//   flows:
+//   - fallback
+// - flows:       # catch all
+//   - compensate
+// ```
+type ErrorHandler struct {
+	// Type matches the type of the error reported by the Function runtime, e.g. "TimeoutError"
+	// If no value is specified, errors of any type match
+	// +optional
+	Type string `json:"type,omitempty"`
+	// Message is a regular expression which matches the error message
+	// If no value is specified, errors with any message match
+	// +optional
+	Message string `json:"message,omitempty"`
+	// Flows lists the Flows where the error goes
+	// The error is wrapped as {"$":{"type":"...","message":"..."}} as the input of the Flows
+	Flows []string `json:"flows"`
+}
+
+// Statement shows the flow control logic type
+// +kubebuilder:validation:Enum=direct;switch;foreach
+type Statement string
+
+const (
+	// Direct is the result of the flow go to downstream directly
+	Direct Statement = "direct"
+	// Switch is the result of the flow go to downstream based on the switch condition;
+	Switch Statement = "switch"
+	// Foreach is the Function runs once per element of an array in the input,
+	// and the aggregated result goes to downstream directly
+	Foreach Statement = "foreach"
+)
+
+// Iteration claims how the Function of a Flow maps over an array in the input
+// A sample of Iteration
+// ```yaml
+// statement: foreach
+// foreach:
+//   target: $.orders
+//   maxConcurrency: 5
+//   aggregation: list
+// ```
+// Let's say the input is {"$":{"orders":[{"id":1},{"id":2}]}},
+// the Function runs twice, with the input {"$":{"id":1}} and {"$":{"id":2}} respectively.
+// The results are aggregated by the Aggregation mode as the result of the Flow.
+type Iteration struct {
+	// Target shows the array in the input the Function maps over
+	// It uses the same style as Condition.Target, e.g. "$.orders"
+	// If users don't specify the Target field, or the Target value is just "$",
+	// the input itself should be an array
+	// +optional
+	Target string `json:"target,omitempty"`
+	// MaxConcurrency is the max number of the elements processed at the same time
+	// If no value is specified or the value is 0, all elements are processed concurrently
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	MaxConcurrency int32 `json:"maxConcurrency,omitempty"`
+	// Aggregation defines how the results of the elements are aggregated
+	// Valid values are:
+	// - list: The results are collected into an array in the order of the elements, it's the default;
+	// - merge: The results are JSON objects and merged into one object, later elements win on conflicts;
+	// - discard: The results are dropped, the input of the Flow goes to downstream;
+	// +optional
+	Aggregation Aggregation `json:"aggregation,omitempty"`
+}
+
+// Aggregation defines how the results of the elements in a foreach Flow are aggregated
+// +kubebuilder:validation:Enum=list;merge;discard
+type Aggregation string
+
+const (
+	// List means the results are collected into an array in the order of the elements
+	List Aggregation = "list"
+	// Merge means the results are JSON objects and merged into one object
+	Merge Aggregation = "merge"
+	// Discard means the results are dropped and the input of the Flow goes to downstream
+	Discard Aggregation = "discard"
+)
+
+// Role is the role of the Flow
+// +kubebuilder:validation:Enum=start;end;orphan
+type Role string
+
+const (
+	// Start means the role of the Flow is "start" which means it is the entrance of workflow instance
+	Start Role = "start"
+	// End means the role of the Flow is "end" which means it is the exit point of workflow instance
+	End Role = "end"
+	// Orphan means the role of the Flow is "orphan" which is a special case that
+	// the workflow instance has only one function
+	Orphan Role = "orphan"
+)
+
+// Condition is the control logic of the flow
+// A sample of Condition
+// ```yaml
+// condition:
+// 	 name: root
+// 	 type: int
+// 	 operator: gt
+// 	 target: $.a
+// 	 comparison: 50
+// 	 destination:
+// 		 isTrue:  # ...
+// 		 isFalse: # ...
+// ```
+// It is same as:
+// if $.a >= 50 {
+// 	 goto isTrue logic
+// } else {
+// 	 goto isFalse logic
+// }
+//
+// A Condition can also be a logical group of other Conditions in the same group,
+// in this case, Type, Operator, Target and Comparison are not used.
+// One and only one of All, Any and Not can be specified.
+// ```yaml
+// conditions:
+// - name: root
+// 	 all: [is-vip, big-order]
+// 	 destination: # ...
+// - name: is-vip
+// 	 type: bool
+// 	 operator: eq
+// 	 target: $.vip
+// 	 comparison: "true"
+// - name: big-order
+// 	 type: float
+// 	 operator: ge
+// 	 target: $.amount
+// 	 comparison: "99.9"
+// ```
+type Condition struct {
+	// Name is the name of a Condition, it's unique in a Condition group
+	Name string `json:"name"`
+	// Type is the data type that Tass workflow condition support
+	// It also implicitly shows the result type of the flow
+	// Valid values are:
+	// - string: The condition type is string
+	// - int: The condition type is int
+	// - float: The condition type is float
+	// - bool: The condition type is boolean
+	// Type is required unless the Condition is a logical group
+	// +optional
+	Type ConditionType `json:"type,omitempty"`
+	// Operator defines the illegal operation in workflow condition statement
+	// Valid values are:
+	// - eq: The result is equal to the target
+	// - ne: The result is not equal to the target
+	// - lt: The result is less than the target
+	// - le: The result is less than or equal to the target
+	// - gt: The result is greater than the target
+	// - ge: The result is greater than or equal to the target.
+	// - in: The result is one of the elements of the Comparison, a JSON array like ["cash","card"]
+	// - contains: The result string contains the Comparison
+	// - matches: The result string matches the regular expression in Comparison
+	// - exists: The Target exists in the result, Comparison is not used
+	// - isNull: The Target exists in the result and its value is null, Comparison is not used
+	// Operator is required unless the Condition is a logical group
+	// +optional
+	Operator OperatorType `json:"operator,omitempty"`
+	// Target shows the specific data that the flow result uses to compare with
+	// The result of the flow can be a simple type like string, bool or int
+	// But it can also be a complex object contains some fileds
+	// Whatever the result is, the Flow runtime will wrap the result to a JSON object
+	// to unifiy the transmission process.
+	// For example, the result of the user code is a string type, let's say "tass",
+	// and then it will be wrapped as a JSON object {"$": "tass"} as the result of the Flow.
+	//
+	// If the result of user code is not a simple type, it can be much more complex
+	// For example, the Flow result can be {"$":{"name": "tass","type": "faas"}}
+	// So in this case, if we want to use the "type" property
+	// to compare with Comparison, the Target value should be "$.type"
+	//
+	// If users don't specify the Target field,or the Target value is just "$",
+	// it means the user code result is just a simple type
+	// Otherwise, the user must provide a Target value to claim the property to use
+	// One more example to show how to get the key in Flow result
+	// Let's say the result is {"$":{"name":"tass","info":{"type":"fn","timeout":60}}}
+	// We want the "timeout" key, so the Target value is "$.info.timeout"
+	Target string `json:"target,omitempty"`
+	// Comparison is used to compare with the flow result
+	// Comparison can be a realistic value, like "cash", "5", "true"
+	// it can also be a property of the flow result, like "$.b"
+	// +optional
+	Comparison Comparison `json:"comparison,omitempty"`
+
+	// All lists the names of the Conditions in the same group which must all be satisfied
+	// +optional
+	All []string `json:"all,omitempty"`
+	// Any lists the names of the Conditions in the same group of which at least one must be satisfied
+	// +optional
+	Any []string `json:"any,omitempty"`
+	// Not is the name of the Condition in the same group which must not be satisfied
+	// +optional
+	Not string `json:"not,omitempty"`
+
+	// Destination defines the downstream Flows based on the condition result
+	// A Condition which is only used as an operand of a logical group may leave it empty
+	// +optional
+	Destination Destination `json:"destination,omitempty"`
+}
+
+// ConditionType is the data type that Tass workflow condition support
+// +kubebuilder:validation:Enum=string;int;float;bool
+type ConditionType string
+
+const (
+	// String means the condition type is string
+	String ConditionType = "string"
+	// Int means the condition type is int
+	Int ConditionType = "int"
+	// Float means the condition type is float
+	Float ConditionType = "float"
+	// Bool means the condition type is boolean
+	Bool ConditionType = "bool"
+)
+
+// OperatorType defines the illegal operation in workflow condition statement
+// +kubebuilder:validation:Enum=eq;ne;lt;le;gt;ge;in;contains;matches;exists;isNull
+type OperatorType string
+
+const (
+	// Eq means the result is equal to the target
+	Eq OperatorType = "eq"
+	// Ne means the result is not equal to the target
+	Ne OperatorType = "ne"
+	// Lt means the result is less than the target, bool not accept
+	Lt OperatorType = "lt"
+	// Le means the result is less than or equal to the target, bool not accept
+	Le OperatorType = "le"
+	// Gt means the result is greater than the target, bool not accept
+	Gt OperatorType = "gt"
+	// Ge means the result is greater than or equal to the target, bool not accept
+	Ge OperatorType = "ge"
+	// In means the result is one of the elements of the target JSON array
+	In OperatorType = "in"
+	// Contains means the result contains the target, string only
+	Contains OperatorType = "contains"
+	// Matches means the result matches the target regular expression, string only
+	Matches OperatorType = "matches"
+	// Exists means the result has the property, the comparison is not used
+	Exists OperatorType = "exists"
+	// IsNull means the property of the result is null, the comparison is not used
+	IsNull OperatorType = "isNull"
+)
+
+// Comparison is used to compare with the flow result
+// Comparison can be string, int, float or bool, or a JSON array of them for the "in" operator
+type Comparison string
+
+// Destination defines the downstream Flows based on the condition result
+// When a Flow finishes its task, the result of the Flow goes to the downstream Flows
+// After passing a Condition, the Flows where result goes is determinated by Destination field
+// The result can go to the downstream Flows directly,
+// or it needs a new round of Conditions, or both.
+// Here is a sample of Destination:
+// ```yaml
+// destination:
+//	 isTrue:
+//	 	 flows:
+//	 	 - flow-a       # this is a Flow Name
+//		 - flow-b
+//	 isFalse:
+//	 	 conditions:
+//	   - condition-a  # this is a Condition name
+// ``
+type Destination struct {
+	// IsTrue defines the downstream Flows if the condition is satisfied
+	// +optional
+	IsTrue Next `json:"isTrue,omitempty"`
+	// IsFalse defines the downstream Flows if the condition is not satisfied
+	// +optional
+	IsFalse Next `json:"isFalse,omitempty"`
+}
+
+// Next shows the next Condition or Flows the data goes
+type Next struct {
+	// Flows lists the Flows where the result of the current Flow goes
+	// +optional
+	Flows []string `json:"flows,omitempty"`
+	// Condition lists the Condition where the result of the current Flow goes
+	// It means that the result needs more control logic check
+	// +optional
+	Conditions []string `json:"conditions,omitempty"`
+}
+
+// WorkflowStatus defines the observed state of Workflow
+type WorkflowStatus struct {
+	// Graph is the Mermaid flowchart of the Flows, it is rendered from the Flows by the operator
+	// so that the docs and the reviews always show the real graph
+	// +optional
+	Graph string `json:"graph,omitempty"`
+
+	// URL is the public URL of the Workflow when it's exposed by a HTTPTrigger
+	// +optional
+	URL string `json:"url,omitempty"`
+
+	// Rollout is the progress of the rollout
+	// +optional
+	Rollout *RolloutStatus `json:"rollout,omitempty"`
+
+	// Revision is the sequence number of the WorkflowRevision of the current Flows
+	// +optional
+	Revision int64 `json:"revision,omitempty"`
+
+	// Message is a human readable message of the last rollback, e.g. why it failed
+	// +optional
+	Message string `json:"message,omitempty"`
+
+	// ValidationErrors are the checks the current Flows fails
+	// Only a Flows passing all the checks is recorded as a WorkflowRevision and rolled out,
+	// the last accepted revision keeps serving until they are fixed.
+	// +optional
+	ValidationErrors []string `json:"validationErrors,omitempty"`
+}
+
+// RolloutStatus is the progress of the rollout of a Workflow
+type RolloutStatus struct {
+	// StableRevision is the revision serving all the requests in the cluster
+	// +optional
+	StableRevision string `json:"stableRevision,omitempty"`
+	// CanaryRevision is the revision being rolled out
+	// +optional
+	CanaryRevision string `json:"canaryRevision,omitempty"`
+	// FailedRevision is the revision rolled back last time, it's not rolled out again
+	// +optional
+	FailedRevision string `json:"failedRevision,omitempty"`
+	// Phase is the phase of the rollout
+	// +optional
+	Phase RolloutPhase `json:"phase,omitempty"`
+	// Step is the index of the current step
+	// +optional
+	Step int32 `json:"step,omitempty"`
+	// Weight is the percentage of the requests of the HTTPTrigger the canary serves
+	// +optional
+	Weight int32 `json:"weight,omitempty"`
+	// StepStartTime is the time the current step started
+	// +optional
+	StepStartTime *metav1.Time `json:"stepStartTime,omitempty"`
+	// CanaryExecutions is the number of the finished WorkflowExecutions the canary served in the step
+	// +optional
+	CanaryExecutions int32 `json:"canaryExecutions,omitempty"`
+	// CanaryFailures is the number of the failed WorkflowExecutions the canary served in the step
+	// +optional
+	CanaryFailures int32 `json:"canaryFailures,omitempty"`
+	// Message is a human readable message of the rollout, e.g. why it's rolled back
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+// RolloutPhase is the phase of a rollout
+type RolloutPhase string
+
+const (
+	// RolloutStable means the stable revision serves all the requests
+	RolloutStable RolloutPhase = "Stable"
+	// RolloutProgressing means the canary serves part of the requests
+	RolloutProgressing RolloutPhase = "Progressing"
+	// RolloutRolledBack means the canary has been removed since a check failed
+	RolloutRolledBack RolloutPhase = "RolledBack"
+)
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+
+// Workflow is the Schema for the workflows API
+// The name of a Workflow must not end with "-canary", the suffix names the canary runtime of a Rollout.
+type Workflow struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   WorkflowSpec   `json:"spec,omitempty"`
+	Status WorkflowStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// WorkflowList contains a list of Workflow
+type WorkflowList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []Workflow `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&Workflow{}, &WorkflowList{})
+}
@@ -0,0 +1,52 @@
+/*
+
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package v1alpha2
+
+import (
+	ctrl "sigs.k8s.io/controller-runtime"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+
+	"github.com/tass-io/tass-operator/api/v1alpha1"
+)
+
+// log is for logging in this package.
+var workflowlog = logf.Log.WithName("workflow-resource")
+
+// SetupWebhookWithManager registers the defaulting webhook of the v1alpha2 Workflow
+func (r *Workflow) SetupWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).
+		For(r).
+		Complete()
+}
+
+// +kubebuilder:webhook:path=/mutate-serverless-tass-io-v1alpha2-workflow,mutating=true,failurePolicy=fail,groups=serverless.tass.io,resources=workflows,verbs=create;update,versions=v1alpha2,name=mworkflow.v1alpha2.kb.io
+
+var _ webhook.Defaulter = &Workflow{}
+
+// Default implements webhook.Defaulter so a webhook will be registered for the type
+// The defaults are the same as v1alpha1, the Workflow is defaulted through the hub version.
+func (r *Workflow) Default() {
+	hub := &v1alpha1.Workflow{}
+	if err := r.ConvertTo(hub); err != nil {
+		workflowlog.Error(err, "cannot convert to the hub version", "name", r.Name)
+		return
+	}
+	hub.Default()
+	if err := r.ConvertFrom(hub); err != nil {
+		workflowlog.Error(err, "cannot convert from the hub version", "name", r.Name)
+	}
+}
@@ -0,0 +1,13 @@
+package v1alpha2
+
+import (
+	"testing"
+)
+
+func TestWorkflowDefault(t *testing.T) {
+	wf := &Workflow{Spec: WorkflowSpec{Flows: []Flow{{Name: "a", Function: "f"}}}}
+	wf.Default()
+	if flow := wf.Spec.Flows[0]; flow.Statement != Direct || flow.Role != Orphan {
+		t.Errorf("Default() flow = %+v, want direct and orphan", flow)
+	}
+}
@@ -0,0 +1,50 @@
+/*
+
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package v1alpha2
+
+import (
+	"sigs.k8s.io/controller-runtime/pkg/conversion"
+
+	"github.com/tass-io/tass-operator/api/v1alpha1"
+)
+
+// ConvertTo converts the WorkflowRuntime to the hub version v1alpha1,
+// the Instances go back to "spec.status" and the spec is always set
+func (src *WorkflowRuntime) ConvertTo(dstRaw conversion.Hub) error {
+	dst := dstRaw.(*v1alpha1.WorkflowRuntime)
+	dst.ObjectMeta = src.ObjectMeta
+	dst.Spec = &v1alpha1.WorkflowRuntimeSpec{}
+	if err := convertJSON(&src.Spec, dst.Spec); err != nil {
+		return err
+	}
+	return convertJSON(&src.Status, &dst.Spec.Status)
+}
+
+// ConvertFrom converts the WorkflowRuntime from the hub version v1alpha1,
+// "spec.status" becomes the status, a WorkflowRuntime without spec gets an empty one
+func (dst *WorkflowRuntime) ConvertFrom(srcRaw conversion.Hub) error {
+	src := srcRaw.(*v1alpha1.WorkflowRuntime)
+	dst.ObjectMeta = src.ObjectMeta
+	dst.Spec = WorkflowRuntimeSpec{}
+	dst.Status = WorkflowRuntimeStatus{}
+	if src.Spec == nil {
+		return nil
+	}
+	if err := convertJSON(src.Spec, &dst.Spec); err != nil {
+		return err
+	}
+	return convertJSON(&src.Spec.Status, &dst.Status)
+}
@@ -0,0 +1,135 @@
+/*
+
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package v1alpha2
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// WorkflowRuntimeSpec defines the desired state of WorkflowRuntime
+type WorkflowRuntimeSpec struct {
+	// Replicas defines the replication of the workflow runtime
+	// Specificly, it determines the replication of Pods in its Deployment
+	// +optional
+	Replicas *int32 `json:"replicas,omitempty"`
+
+	// Deadline is the upper bound of the time a whole workflow invocation may take
+	// It is copied from the Workflow by the operator
+	// +optional
+	Deadline *metav1.Duration `json:"deadline,omitempty"`
+
+	// FlowTimeouts records the timeout of each Flow which has claimed one
+	// The key is the name of the Flow
+	// It is copied from the Workflow by the operator
+	// +optional
+	FlowTimeouts map[string]metav1.Duration `json:"flowTimeouts,omitempty"`
+
+	// Revision is the revision of the Workflow the runtime serves
+	// It is set by the operator
+	// +optional
+	Revision string `json:"revision,omitempty"`
+
+	// Flows is the snapshot of the Flows of the Revision, the schedulers run the Flows here
+	// so that the runtimes of two revisions of a Workflow can serve side by side during a rollout.
+	// It is copied from the Workflow by the operator
+	// +optional
+	Flows []Flow `json:"flows,omitempty"`
+
+	// Env is the environment variables of the Workflow, they are set on the scheduler container
+	// and inherited by the function processes it starts
+	// It is copied from the Workflow by the operator
+	// +optional
+	Env map[string]string `json:"env,omitempty"`
+
+	// StateStore is the name of the StateStore in the same namespace the schedulers coordinate through,
+	// its connection is passed to the schedulers, the default Redis is used if no value is specified
+	// It is copied from the Workflow by the operator
+	// +optional
+	StateStore string `json:"stateStore,omitempty"`
+
+	// SecurityContext configures the security context of the scheduler container
+	// It is copied from the Workflow by the operator
+	// +optional
+	SecurityContext *SchedulerSecurity `json:"securityContext,omitempty"`
+}
+
+// WorkflowRuntimeStatus defines the observed state of WorkflowRuntime
+// It's nested in the spec as "spec.status" in v1alpha1
+type WorkflowRuntimeStatus struct {
+	// Instances is a Pod List that WorkflowRuntime Manages
+	// +optional
+	Instances Instances `json:"instances,omitempty"`
+}
+
+// Instances is a Pod List that WorkflowRuntime manages
+// When the Deployment created or updated, Instances should be updated
+// The key is the name of the Pod, for example "sample-c65c4f67-skbml"
+type Instances map[string]Instance
+
+// Instance records some runtime info of a Pod
+// Specificly, it contains info about Function in the Pod and Pod metadata
+type Instance struct {
+	// Status describes metadata a Pod has
+	// +optional
+	Status *InstanceStatus `json:"status,omitempty"`
+	// ProcessRuntimes is a list of ProcessRuntime
+	// +optional
+	ProcessRuntimes ProcessRuntimes `json:"processRuntimes,omitempty"`
+}
+
+// InstanceStatus describes metadata a Pod has
+type InstanceStatus struct {
+	// IP address of the host to which the pod is assigned. Empty if not yet scheduled.
+	HostIP *string `json:"hostIP,omitempty"`
+	// IP address allocated to the pod. Routable at least within the cluster. Empty if not yet allocated.
+	PodIP *string `json:"podIP,omitempty"`
+}
+
+// ProcessRuntimes is a list of ProcessRuntime
+// The key is the name of the Function which is running in the Pod
+type ProcessRuntimes map[string]ProcessRuntime
+
+// ProcessRuntime records the process runtime info
+type ProcessRuntime struct {
+	// Number is the number of the processes running the same Function
+	Number int `json:"number"`
+}
+
+// +kubebuilder:object:root=true
+
+// WorkflowRuntime is the Schema for the workflowruntimes API
+type WorkflowRuntime struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	// +optional
+	Spec WorkflowRuntimeSpec `json:"spec,omitempty"`
+	// +optional
+	Status WorkflowRuntimeStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// WorkflowRuntimeList contains a list of WorkflowRuntime
+type WorkflowRuntimeList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []WorkflowRuntime `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&WorkflowRuntime{}, &WorkflowRuntimeList{})
+}
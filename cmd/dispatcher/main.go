@@ -0,0 +1,77 @@
+/*
+
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// dispatcher consumes the messages on a topic of a message queue and invokes a Workflow with them
+// It's deployed by the operator for each EventTrigger.
+package main
+
+import (
+	"flag"
+	"os"
+	"strings"
+
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+
+	"github.com/tass-io/tass-operator/pkg/dispatcher"
+	"github.com/tass-io/tass-operator/pkg/execution"
+)
+
+func main() {
+	var config dispatcher.Config
+	var servers string
+	d := &dispatcher.Dispatcher{}
+	flag.StringVar(&config.Type, "source", "", "The type of the message queue, e.g. nats")
+	flag.StringVar(&servers, "servers", "", "The comma separated addresses of the message queue")
+	flag.StringVar(&config.Topic, "topic", "", "The topic the messages are consumed from")
+	flag.StringVar(&config.Group, "group", "", "The consumer group the dispatcher joins")
+	flag.StringVar(&d.Namespace, "namespace", os.Getenv("POD_NAMESPACE"), "The namespace of the Workflow")
+	flag.StringVar(&d.Workflow, "workflow", "", "The name of the Workflow")
+	flag.StringVar(&d.Flow, "flow", "", "The Flow the invocations start from")
+	flag.StringVar(&d.DeadLetter, "dead-letter", "", "The topic the messages failing all attempts go")
+	flag.IntVar(&d.MaxAttempts, "max-attempts", dispatcher.DefaultMaxAttempts,
+		"The number of times the Workflow is invoked with a message")
+	flag.DurationVar(&d.Backoff, "backoff", dispatcher.DefaultBackoff,
+		"The time waited before the second attempt, it doubles after each attempt")
+	flag.Parse()
+
+	ctrl.SetLogger(zap.New(zap.UseDevMode(true)))
+	log := ctrl.Log.WithName("dispatcher")
+	if servers != "" {
+		config.Servers = strings.Split(servers, ",")
+	}
+	d.Topic = config.Topic
+
+	source, err := dispatcher.New(config)
+	if err != nil {
+		log.Error(err, "unable to create the source")
+		os.Exit(1)
+	}
+	d.Source = source
+	d.Invoker = execution.NewHTTPInvoker()
+	d.Log = log
+
+	ctx := ctrl.SetupSignalHandler()
+	log.Info("dispatching", "source", config.Type, "topic", config.Topic, "workflow", d.Workflow)
+	err = d.Run(ctx)
+	if closeErr := source.Close(); closeErr != nil {
+		log.Error(closeErr, "unable to close the source")
+	}
+	if err != nil {
+		log.Error(err, "dispatcher exited")
+		os.Exit(1)
+	}
+}
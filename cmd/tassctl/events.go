@@ -0,0 +1,97 @@
+/*
+
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+	"text/tabwriter"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/watch"
+)
+
+// runEvents prints the Kubernetes events of a Workflow
+// The WorkflowRuntime, the Deployment and the Service of a Workflow share its name,
+// so their events are shown as well.
+func runEvents(args []string) error {
+	fs := flag.NewFlagSet("events", flag.ExitOnError)
+	namespace := fs.String("n", "default", "The namespace of the Workflow")
+	follow := fs.Bool("f", false, "Keep watching the new events")
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: tassctl events [flags] <workflow>\n\nFlags:\n")
+		fs.PrintDefaults()
+	}
+	_ = fs.Parse(args)
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return fmt.Errorf("one workflow should be specified")
+	}
+	_, clientset, err := newClients()
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	selector := fields.OneTermEqualSelector("involvedObject.name", fs.Arg(0)).String()
+	events, err := clientset.CoreV1().Events(*namespace).List(ctx, metav1.ListOptions{FieldSelector: selector})
+	if err != nil {
+		return err
+	}
+	sort.Slice(events.Items, func(i, j int) bool {
+		return events.Items[i].LastTimestamp.Before(&events.Items[j].LastTimestamp)
+	})
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "LAST SEEN\tTYPE\tREASON\tOBJECT\tMESSAGE")
+	for i := range events.Items {
+		printEvent(w, &events.Items[i])
+	}
+	_ = w.Flush()
+	if !*follow {
+		return nil
+	}
+
+	watcher, err := clientset.CoreV1().Events(*namespace).Watch(ctx, metav1.ListOptions{
+		FieldSelector:   selector,
+		ResourceVersion: events.ResourceVersion,
+	})
+	if err != nil {
+		return err
+	}
+	defer watcher.Stop()
+	for e := range watcher.ResultChan() {
+		if e.Type != watch.Added && e.Type != watch.Modified {
+			continue
+		}
+		if event, ok := e.Object.(*corev1.Event); ok {
+			printEvent(w, event)
+			_ = w.Flush()
+		}
+	}
+	return nil
+}
+
+func printEvent(w *tabwriter.Writer, event *corev1.Event) {
+	fmt.Fprintf(w, "%s\t%s\t%s\t%s/%s\t%s\n",
+		event.LastTimestamp.Format("2006-01-02T15:04:05Z07:00"), event.Type, event.Reason,
+		event.InvolvedObject.Kind, event.InvolvedObject.Name, event.Message)
+}
@@ -0,0 +1,70 @@
+/*
+
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"flag"
+	"fmt"
+
+	serverlessv1alpha1 "github.com/tass-io/tass-operator/api/v1alpha1"
+	"github.com/tass-io/tass-operator/pkg/workflow"
+)
+
+// runGraph renders the graphs of the Workflows in the YAML files
+func runGraph(args []string) error {
+	fs := flag.NewFlagSet("graph", flag.ExitOnError)
+	format := fs.String("format", "dot", "The output format, dot or mermaid")
+	name := fs.String("workflow", "", "Only render the Workflow with the name")
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: tassctl graph [flags] <file>...\n\nFlags:\n")
+		fs.PrintDefaults()
+	}
+	_ = fs.Parse(args)
+	if fs.NArg() == 0 {
+		fs.Usage()
+		return fmt.Errorf("no file specified")
+	}
+	var render func(*serverlessv1alpha1.Workflow) string
+	switch *format {
+	case "dot":
+		render = workflow.RenderDOT
+	case "mermaid":
+		render = workflow.RenderMermaid
+	default:
+		return fmt.Errorf("format %s is not supported", *format)
+	}
+
+	var functions serverlessv1alpha1.FunctionList
+	var workflows serverlessv1alpha1.WorkflowList
+	for _, file := range fs.Args() {
+		if err := decodeFile(file, &functions, &workflows); err != nil {
+			return err
+		}
+	}
+	rendered := 0
+	for i := range workflows.Items {
+		if *name != "" && workflows.Items[i].Name != *name {
+			continue
+		}
+		fmt.Print(render(&workflows.Items[i]))
+		rendered++
+	}
+	if rendered == 0 {
+		return fmt.Errorf("no Workflow found")
+	}
+	return nil
+}
@@ -0,0 +1,41 @@
+/*
+
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import "testing"
+
+func TestRunGraph(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    []string
+		wantErr bool
+	}{
+		{name: "dot"},
+		{name: "mermaid", args: []string{"-format", "mermaid"}},
+		{name: "only the named workflow", args: []string{"-workflow", "pipeline-v2"}},
+		{name: "named workflow not found", args: []string{"-workflow", "missing"}, wantErr: true},
+		{name: "unknown format", args: []string{"-format", "svg"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			args := append(tt.args, writeFile(t, functionV1alpha1, workflowV1alpha1, workflowV1alpha2))
+			if err := runGraph(args); (err != nil) != tt.wantErr {
+				t.Errorf("runGraph() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,123 @@
+/*
+
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/duration"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	serverlessv1alpha1 "github.com/tass-io/tass-operator/api/v1alpha1"
+	"github.com/tass-io/tass-operator/pkg/workflow"
+)
+
+// runHistory lists the WorkflowRevisions of a Workflow, the current one is marked
+func runHistory(args []string) error {
+	fs := flag.NewFlagSet("history", flag.ExitOnError)
+	namespace := fs.String("n", "default", "The namespace of the Workflow")
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: tassctl history [flags] <workflow>\n\nFlags:\n")
+		fs.PrintDefaults()
+	}
+	_ = fs.Parse(args)
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return fmt.Errorf("one workflow should be specified")
+	}
+	cli, _, err := newClients()
+	if err != nil {
+		return err
+	}
+	ctx := context.Background()
+
+	var wf serverlessv1alpha1.Workflow
+	if err := cli.Get(ctx, types.NamespacedName{Namespace: *namespace, Name: fs.Arg(0)}, &wf); err != nil {
+		return err
+	}
+	revisions, err := workflow.ListRevisions(ctx, cli, &wf)
+	if err != nil {
+		return err
+	}
+	if len(revisions) == 0 {
+		fmt.Println("No revision found")
+		return nil
+	}
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "REVISION\tHASH\tFLOWS\tFUNCTIONS\tAGE\t")
+	for _, revision := range revisions {
+		current := ""
+		if revision.Spec.Revision == wf.Status.Revision {
+			current = "(current)"
+		}
+		functions := make([]string, 0, len(revision.Spec.Functions))
+		for _, function := range revision.Spec.Functions {
+			functions = append(functions, function.Name+"@"+function.ResourceVersion)
+		}
+		fmt.Fprintf(w, "%d\t%s\t%d\t%s\t%s\t%s\n", revision.Spec.Revision, revision.Spec.Hash,
+			len(revision.Spec.Snapshot.Spec), strings.Join(functions, ","),
+			duration.HumanDuration(time.Since(revision.CreationTimestamp.Time)), current)
+	}
+	return w.Flush()
+}
+
+// runRollback claims the revision the Workflow is rolled back to, the operator does the rollback
+func runRollback(args []string) error {
+	fs := flag.NewFlagSet("rollback", flag.ExitOnError)
+	namespace := fs.String("n", "default", "The namespace of the Workflow")
+	to := fs.Int64("to", 0, "The revision to roll back to, 0 means the previous revision")
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: tassctl rollback [flags] <workflow>\n\nFlags:\n")
+		fs.PrintDefaults()
+	}
+	_ = fs.Parse(args)
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return fmt.Errorf("one workflow should be specified")
+	}
+	if *to < 0 {
+		return fmt.Errorf("revision %d should not be negative", *to)
+	}
+	cli, _, err := newClients()
+	if err != nil {
+		return err
+	}
+	ctx := context.Background()
+
+	var wf serverlessv1alpha1.Workflow
+	if err := cli.Get(ctx, types.NamespacedName{Namespace: *namespace, Name: fs.Arg(0)}, &wf); err != nil {
+		return err
+	}
+	patch := client.MergeFrom(wf.DeepCopy())
+	wf.Spec.RollbackTo = to
+	if err := cli.Patch(ctx, &wf, patch); err != nil {
+		return err
+	}
+	if *to == 0 {
+		fmt.Printf("Workflow %s is rolling back to the previous revision\n", wf.Name)
+	} else {
+		fmt.Printf("Workflow %s is rolling back to revision %d\n", wf.Name, *to)
+	}
+	return nil
+}
@@ -0,0 +1,82 @@
+/*
+
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+
+	"sigs.k8s.io/yaml"
+
+	"github.com/tass-io/tass-operator/pkg/serverlessworkflow"
+)
+
+// runImport converts a CNCF Serverless Workflow definition to a Workflow and prints it in YAML
+// The constructs Tass can't represent are reported, and the conversion fails on them unless -force.
+func runImport(args []string) error {
+	fs := flag.NewFlagSet("import", flag.ExitOnError)
+	name := fs.String("name", "", "The name of the Workflow, the id of the definition is used if not specified")
+	namespace := fs.String("namespace", "", "The namespace of the Workflow")
+	force := fs.Bool("force", false, "Print the Workflow even if some constructs can't be represented")
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: tassctl import [flags] <file>\n\nFlags:\n")
+		fs.PrintDefaults()
+	}
+	_ = fs.Parse(args)
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return fmt.Errorf("one and only one file should be specified")
+	}
+
+	var data []byte
+	var err error
+	if file := fs.Arg(0); file == "-" {
+		data, err = ioutil.ReadAll(os.Stdin)
+	} else {
+		data, err = ioutil.ReadFile(file)
+	}
+	if err != nil {
+		return err
+	}
+	wf, issues, err := serverlessworkflow.Import(data)
+	for _, issue := range issues {
+		fmt.Fprintln(os.Stderr, "Unsupported:", issue)
+	}
+	if errs, ok := err.(serverlessworkflow.ValidationErrors); ok {
+		for _, verr := range errs {
+			fmt.Fprintln(os.Stderr, "Invalid:", verr)
+		}
+		return fmt.Errorf("%d checks of the Workflow failed", len(errs))
+	} else if err != nil {
+		return err
+	}
+	if len(issues) != 0 && !*force {
+		return fmt.Errorf("%d constructs can't be represented, use -force to print the Workflow anyway", len(issues))
+	}
+	if *name != "" {
+		wf.Name = *name
+	}
+	wf.Namespace = *namespace
+	out, err := yaml.Marshal(wf)
+	if err != nil {
+		return err
+	}
+	fmt.Print(string(out))
+	return nil
+}
@@ -0,0 +1,76 @@
+/*
+
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"strconv"
+
+	"github.com/tass-io/tass-operator/pkg/execution"
+)
+
+// runInvoke sends a request to the Service of a Workflow through the apiserver proxy,
+// so that it works out of the cluster, and prints the response
+func runInvoke(args []string) error {
+	fs := flag.NewFlagSet("invoke", flag.ExitOnError)
+	namespace := fs.String("n", "default", "The namespace of the Workflow")
+	flow := fs.String("flow", "", "The Flow to start from, the entrance of the Workflow if empty")
+	data := fs.String("d", "{}", "The JSON parameters of the invocation")
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: tassctl invoke [flags] <workflow>\n\nFlags:\n")
+		fs.PrintDefaults()
+	}
+	_ = fs.Parse(args)
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return fmt.Errorf("one workflow should be specified")
+	}
+	if !json.Valid([]byte(*data)) {
+		return fmt.Errorf("parameters %s is not a legal JSON", *data)
+	}
+	body, err := json.Marshal(execution.Request{
+		WorkflowName: fs.Arg(0),
+		FlowName:     *flow,
+		Parameters:   json.RawMessage(*data),
+	})
+	if err != nil {
+		return err
+	}
+
+	_, clientset, err := newClients()
+	if err != nil {
+		return err
+	}
+	// the Service has the same name as the Workflow
+	result, err := clientset.CoreV1().RESTClient().Post().
+		Namespace(*namespace).
+		Resource("services").
+		Name(fs.Arg(0)+":"+strconv.Itoa(execution.ServicePort)).
+		SubResource("proxy").
+		Suffix("v1/workflow/").
+		SetHeader("Content-Type", "application/json").
+		Body(body).
+		DoRaw(context.Background())
+	if err != nil {
+		return fmt.Errorf("invoke workflow %s: %v %s", fs.Arg(0), err, result)
+	}
+	fmt.Println(string(result))
+	return nil
+}
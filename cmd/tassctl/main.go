@@ -0,0 +1,92 @@
+/*
+
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// tassctl is the command line tool of Tass
+// It validates Workflows offline, shows the status of Workflows and their runtimes,
+// invokes Workflows, tails the Kubernetes events of Workflows, rolls Workflows back to their revisions
+// and imports CNCF Serverless Workflow definitions.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	_ "k8s.io/client-go/plugin/pkg/client/auth/gcp"
+
+	serverlessv1alpha1 "github.com/tass-io/tass-operator/api/v1alpha1"
+	serverlessv1alpha2 "github.com/tass-io/tass-operator/api/v1alpha2"
+)
+
+var scheme = runtime.NewScheme()
+
+func init() {
+	_ = clientgoscheme.AddToScheme(scheme)
+
+	_ = serverlessv1alpha1.AddToScheme(scheme)
+	_ = serverlessv1alpha2.AddToScheme(scheme)
+}
+
+// command is a subcommand of tassctl
+type command struct {
+	name  string
+	usage string
+	run   func(args []string) error
+}
+
+var commands = []command{
+	{name: "validate", usage: "Validate Workflow YAML files offline", run: runValidate},
+	{name: "import", usage: "Convert a CNCF Serverless Workflow definition to a Workflow", run: runImport},
+	{name: "graph", usage: "Render Workflow graphs in DOT or Mermaid", run: runGraph},
+	{name: "status", usage: "Show the status of a Workflow and its runtime", run: runStatus},
+	{name: "invoke", usage: "Invoke a Workflow through its Service", run: runInvoke},
+	{name: "events", usage: "Show the Kubernetes events of a Workflow", run: runEvents},
+	{name: "history", usage: "List the revisions of a Workflow", run: runHistory},
+	{name: "rollback", usage: "Roll a Workflow back to a previous revision", run: runRollback},
+}
+
+func usage() {
+	fmt.Fprintf(flag.CommandLine.Output(), "Usage: tassctl [global flags] <command> [flags]\n\nCommands:\n")
+	for _, c := range commands {
+		fmt.Fprintf(flag.CommandLine.Output(), "  %-10s %s\n", c.name, c.usage)
+	}
+	fmt.Fprintf(flag.CommandLine.Output(), "\nGlobal flags:\n")
+	flag.PrintDefaults()
+}
+
+func main() {
+	flag.Usage = usage
+	flag.Parse()
+	if flag.NArg() == 0 {
+		usage()
+		os.Exit(2)
+	}
+	for _, c := range commands {
+		if c.name != flag.Arg(0) {
+			continue
+		}
+		if err := c.run(flag.Args()[1:]); err != nil {
+			fmt.Fprintln(os.Stderr, "Error:", err)
+			os.Exit(1)
+		}
+		return
+	}
+	fmt.Fprintf(os.Stderr, "unknown command %q\n\n", flag.Arg(0))
+	usage()
+	os.Exit(2)
+}
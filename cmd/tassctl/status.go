@@ -0,0 +1,117 @@
+/*
+
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+	"text/tabwriter"
+
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	serverlessv1alpha1 "github.com/tass-io/tass-operator/api/v1alpha1"
+)
+
+// runStatus shows a Workflow, the instances of its WorkflowRuntime
+// and the Function processes running in each instance
+func runStatus(args []string) error {
+	fs := flag.NewFlagSet("status", flag.ExitOnError)
+	namespace := fs.String("n", "default", "The namespace of the Workflow")
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: tassctl status [flags] <workflow>\n\nFlags:\n")
+		fs.PrintDefaults()
+	}
+	_ = fs.Parse(args)
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return fmt.Errorf("one workflow should be specified")
+	}
+	cli, _, err := newClients()
+	if err != nil {
+		return err
+	}
+	ctx := context.Background()
+	namespacedName := types.NamespacedName{Namespace: *namespace, Name: fs.Arg(0)}
+
+	var wf serverlessv1alpha1.Workflow
+	if err := cli.Get(ctx, namespacedName, &wf); err != nil {
+		return err
+	}
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintf(w, "Workflow:\t%s\n", namespacedName)
+	fmt.Fprintf(w, "Flows:\t%d\n", len(wf.Spec.Spec))
+	if wf.Spec.Deadline != nil {
+		fmt.Fprintf(w, "Deadline:\t%s\n", wf.Spec.Deadline.Duration)
+	}
+	_ = w.Flush()
+
+	var wfrt serverlessv1alpha1.WorkflowRuntime
+	if err := cli.Get(ctx, namespacedName, &wfrt); client.IgnoreNotFound(err) != nil {
+		return err
+	} else if err != nil || wfrt.Spec == nil {
+		fmt.Println("\nWorkflowRuntime not found")
+		return nil
+	}
+	printInstances(wfrt.Spec.Status.Instances)
+	return nil
+}
+
+// printInstances prints the instance table and the process table of a WorkflowRuntime
+func printInstances(instances serverlessv1alpha1.Instances) {
+	names := make([]string, 0, len(instances))
+	for name := range instances {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	fmt.Println("\nInstances:")
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "POD\tPOD IP\tHOST IP\tFUNCTIONS")
+	for _, name := range names {
+		instance := instances[name]
+		podIP, hostIP := "<none>", "<none>"
+		if instance.Status != nil {
+			if instance.Status.PodIP != nil {
+				podIP = *instance.Status.PodIP
+			}
+			if instance.Status.HostIP != nil {
+				hostIP = *instance.Status.HostIP
+			}
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%d\n", name, podIP, hostIP, len(instance.ProcessRuntimes))
+	}
+	_ = w.Flush()
+
+	fmt.Println("\nProcesses:")
+	w = tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "POD\tFUNCTION\tPROCESSES")
+	for _, name := range names {
+		functions := make([]string, 0, len(instances[name].ProcessRuntimes))
+		for function := range instances[name].ProcessRuntimes {
+			functions = append(functions, function)
+		}
+		sort.Strings(functions)
+		for _, function := range functions {
+			fmt.Fprintf(w, "%s\t%s\t%d\n", name, function, instances[name].ProcessRuntimes[function].Number)
+		}
+	}
+	_ = w.Flush()
+}
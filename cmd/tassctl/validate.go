@@ -0,0 +1,151 @@
+/*
+
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+
+	serverlessv1alpha1 "github.com/tass-io/tass-operator/api/v1alpha1"
+	serverlessv1alpha2 "github.com/tass-io/tass-operator/api/v1alpha2"
+	"github.com/tass-io/tass-operator/pkg/workflow"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/serializer"
+	utilyaml "k8s.io/apimachinery/pkg/util/yaml"
+	"sigs.k8s.io/yaml"
+)
+
+// runValidate validates the Workflows in the YAML files with the validators the operator uses
+// The Functions and Workflows referenced by the Flows are only checked with -refs,
+// against the Functions and Workflows defined in the same files.
+func runValidate(args []string) error {
+	fs := flag.NewFlagSet("validate", flag.ExitOnError)
+	refs := fs.Bool("refs", false, "Check the Functions and Workflows the Flows reference, "+
+		"they should be defined in the given files")
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: tassctl validate [flags] <file>...\n\nFlags:\n")
+		fs.PrintDefaults()
+	}
+	_ = fs.Parse(args)
+	if fs.NArg() == 0 {
+		fs.Usage()
+		return fmt.Errorf("no file specified")
+	}
+
+	var functions serverlessv1alpha1.FunctionList
+	var workflows serverlessv1alpha1.WorkflowList
+	for _, file := range fs.Args() {
+		if err := decodeFile(file, &functions, &workflows); err != nil {
+			return err
+		}
+	}
+	if len(workflows.Items) == 0 {
+		return fmt.Errorf("no Workflow found")
+	}
+
+	failed := 0
+	for i := range workflows.Items {
+		wf := &workflows.Items[i]
+		var errs []*workflow.ValidationError
+		if *refs {
+			errs = workflow.Validate(wf, &functions, &workflows)
+		} else {
+			errs = workflow.Validate(wf, nil, nil)
+		}
+		valid := len(errs) == 0
+		for _, err := range errs {
+			fmt.Printf("Workflow %s is invalid: %v\n", wf.Name, err)
+		}
+		if valid {
+			fmt.Printf("Workflow %s is valid\n", wf.Name)
+		} else {
+			failed++
+		}
+	}
+	if failed != 0 {
+		return fmt.Errorf("%d of %d Workflows are invalid", failed, len(workflows.Items))
+	}
+	return nil
+}
+
+// decodeFile decodes the Functions and Workflows in a multi-document YAML file
+// The v1alpha2 objects are converted to v1alpha1, an unknown version of them is an error.
+// Other kinds of objects are ignored.
+func decodeFile(file string, functions *serverlessv1alpha1.FunctionList,
+	workflows *serverlessv1alpha1.WorkflowList) error {
+	var data []byte
+	var err error
+	if file == "-" {
+		data, err = ioutil.ReadAll(os.Stdin)
+	} else {
+		data, err = ioutil.ReadFile(file)
+	}
+	if err != nil {
+		return err
+	}
+
+	decoder := serializer.NewCodecFactory(scheme).UniversalDeserializer()
+	reader := utilyaml.NewYAMLReader(bufio.NewReader(bytes.NewReader(data)))
+	for {
+		doc, err := reader.Read()
+		if err == io.EOF {
+			return nil
+		} else if err != nil {
+			return fmt.Errorf("%s: %v", file, err)
+		}
+		if len(bytes.TrimSpace(doc)) == 0 {
+			continue
+		}
+		obj, _, err := decoder.Decode(doc, nil, nil)
+		if runtime.IsNotRegisteredError(err) || runtime.IsMissingKind(err) {
+			var typeMeta metav1.TypeMeta
+			if yaml.Unmarshal(doc, &typeMeta) == nil &&
+				typeMeta.GroupVersionKind().Group == serverlessv1alpha1.GroupVersion.Group {
+				return fmt.Errorf("%s: %s is not a version tassctl supports", file, typeMeta.APIVersion)
+			}
+			// not a kind tassctl knows, skip it
+			continue
+		} else if err != nil {
+			return fmt.Errorf("%s: %v", file, err)
+		}
+		switch o := obj.(type) {
+		case *serverlessv1alpha1.Workflow:
+			workflows.Items = append(workflows.Items, *o)
+		case *serverlessv1alpha1.Function:
+			functions.Items = append(functions.Items, *o)
+		case *serverlessv1alpha2.Workflow:
+			// the validators work on the storage version
+			var wf serverlessv1alpha1.Workflow
+			if err := o.ConvertTo(&wf); err != nil {
+				return fmt.Errorf("%s: %v", file, err)
+			}
+			workflows.Items = append(workflows.Items, wf)
+		case *serverlessv1alpha2.Function:
+			var fn serverlessv1alpha1.Function
+			if err := o.ConvertTo(&fn); err != nil {
+				return fmt.Errorf("%s: %v", file, err)
+			}
+			functions.Items = append(functions.Items, fn)
+		}
+	}
+}
@@ -0,0 +1,234 @@
+/*
+
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	serverlessv1alpha1 "github.com/tass-io/tass-operator/api/v1alpha1"
+)
+
+const functionV1alpha1 = `
+apiVersion: serverless.tass.io/v1alpha1
+kind: Function
+metadata:
+  name: function1
+`
+
+const workflowV1alpha1 = `
+apiVersion: serverless.tass.io/v1alpha1
+kind: Workflow
+metadata:
+  name: pipeline
+spec:
+  spec:
+  - name: start
+    function: function1
+    statement: direct
+    outputs: [end]
+  - name: end
+    function: function2
+    statement: direct
+`
+
+const workflowV1alpha2 = `
+apiVersion: serverless.tass.io/v1alpha2
+kind: Workflow
+metadata:
+  name: pipeline-v2
+spec:
+  flows:
+  - name: start
+    function: function1
+    statement: direct
+    outputs: [end]
+  - name: end
+    function: function1
+    statement: direct
+`
+
+const invalidWorkflow = `
+apiVersion: serverless.tass.io/v1alpha1
+kind: Workflow
+metadata:
+  name: orphan
+spec:
+  spec:
+  - name: start
+    function: function1
+    statement: direct
+  - name: end
+    function: function1
+    statement: direct
+`
+
+const configMap = `
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: config
+`
+
+const unknownKind = `
+apiVersion: example.com/v1
+kind: Widget
+metadata:
+  name: widget
+`
+
+const unknownVersion = `
+apiVersion: serverless.tass.io/v1beta1
+kind: Workflow
+metadata:
+  name: future
+`
+
+// writeFile writes the YAML documents to a file in a temporary directory
+func writeFile(t *testing.T, docs ...string) string {
+	t.Helper()
+	var data string
+	for _, doc := range docs {
+		data += "---" + doc
+	}
+	file := filepath.Join(t.TempDir(), "manifests.yaml")
+	if err := ioutil.WriteFile(file, []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return file
+}
+
+func TestDecodeFile(t *testing.T) {
+	tests := []struct {
+		name          string
+		docs          []string
+		wantFunctions []string
+		wantWorkflows []string
+		wantErr       bool
+	}{
+		{
+			name:          "v1alpha1",
+			docs:          []string{functionV1alpha1, workflowV1alpha1},
+			wantFunctions: []string{"function1"},
+			wantWorkflows: []string{"pipeline"},
+		},
+		{
+			name:          "v1alpha2 converted",
+			docs:          []string{workflowV1alpha2},
+			wantWorkflows: []string{"pipeline-v2"},
+		},
+		{
+			name:          "other kinds skipped",
+			docs:          []string{configMap, unknownKind, workflowV1alpha1},
+			wantWorkflows: []string{"pipeline"},
+		},
+		{
+			name:    "unknown version",
+			docs:    []string{workflowV1alpha1, unknownVersion},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var functions serverlessv1alpha1.FunctionList
+			var workflows serverlessv1alpha1.WorkflowList
+			err := decodeFile(writeFile(t, tt.docs...), &functions, &workflows)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("decodeFile() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if len(functions.Items) != len(tt.wantFunctions) {
+				t.Fatalf("decodeFile() functions = %v, want %v", functions.Items, tt.wantFunctions)
+			}
+			for i, name := range tt.wantFunctions {
+				if functions.Items[i].Name != name {
+					t.Errorf("decodeFile() function %d = %s, want %s", i, functions.Items[i].Name, name)
+				}
+			}
+			if len(workflows.Items) != len(tt.wantWorkflows) {
+				t.Fatalf("decodeFile() workflows = %v, want %v", workflows.Items, tt.wantWorkflows)
+			}
+			for i, name := range tt.wantWorkflows {
+				if workflows.Items[i].Name != name {
+					t.Errorf("decodeFile() workflow %d = %s, want %s", i, workflows.Items[i].Name, name)
+				}
+			}
+		})
+	}
+}
+
+func TestDecodeFileV1alpha2(t *testing.T) {
+	var functions serverlessv1alpha1.FunctionList
+	var workflows serverlessv1alpha1.WorkflowList
+	if err := decodeFile(writeFile(t, workflowV1alpha2), &functions, &workflows); err != nil {
+		t.Fatal(err)
+	}
+	flows := workflows.Items[0].Spec.Spec
+	if len(flows) != 2 || flows[0].Name != "start" || flows[1].Name != "end" {
+		t.Errorf("decodeFile() flows = %v, want the flows of v1alpha2 in spec.spec", flows)
+	}
+}
+
+func TestRunValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    []string
+		docs    []string
+		wantErr bool
+	}{
+		{
+			name: "valid",
+			docs: []string{workflowV1alpha1},
+		},
+		{
+			name: "valid v1alpha2",
+			docs: []string{workflowV1alpha2},
+		},
+		{
+			name:    "invalid flows",
+			docs:    []string{workflowV1alpha1, invalidWorkflow},
+			wantErr: true,
+		},
+		{
+			name:    "no workflow",
+			docs:    []string{functionV1alpha1},
+			wantErr: true,
+		},
+		{
+			name: "references defined",
+			args: []string{"-refs"},
+			docs: []string{functionV1alpha1, workflowV1alpha2},
+		},
+		{
+			name:    "references undefined",
+			args:    []string{"-refs"},
+			docs:    []string{functionV1alpha1, workflowV1alpha1},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			args := append(tt.args, writeFile(t, tt.docs...))
+			if err := runValidate(args); (err != nil) != tt.wantErr {
+				t.Errorf("runValidate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,234 @@
+/*
+
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/clock"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	serverlessv1alpha1 "github.com/tass-io/tass-operator/api/v1alpha1"
+	"github.com/tass-io/tass-operator/pkg/cron"
+	"github.com/tass-io/tass-operator/pkg/execution"
+)
+
+const (
+	defaultCronSucceededHistoryLimit = 3
+	defaultCronFailedHistoryLimit    = 1
+	// maxMissedTicks bounds the search of the missed ticks after the operator has been down for long
+	maxMissedTicks = 1000
+)
+
+// CronTriggerReconciler reconciles a CronTrigger object
+type CronTriggerReconciler struct {
+	client.Client
+	Log    logr.Logger
+	Scheme *runtime.Scheme
+	// Clock is the clock the ticks are computed with, it's replaced by a fake one in tests
+	Clock clock.Clock
+}
+
+// +kubebuilder:rbac:groups=serverless.tass.io,resources=crontriggers,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=serverless.tass.io,resources=crontriggers/status,verbs=get;update;patch
+
+func (r *CronTriggerReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := r.Log.WithValues("crontrigger", req.NamespacedName)
+
+	var original serverlessv1alpha1.CronTrigger
+	if err := r.Get(ctx, req.NamespacedName, &original); err != nil {
+		if client.IgnoreNotFound(err) == nil {
+			return ctrl.Result{}, nil
+		}
+		log.Error(err, "unable to fetch CronTrigger")
+		return ctrl.Result{}, err
+	}
+	instance := original.DeepCopy()
+
+	var executions serverlessv1alpha1.WorkflowExecutionList
+	if err := r.List(ctx, &executions, client.InNamespace(req.Namespace),
+		client.MatchingLabels{execution.CronTriggerLabel: req.Name}); err != nil {
+		log.Error(err, "unable to list WorkflowExecutions")
+		return ctrl.Result{}, err
+	}
+	var active []*serverlessv1alpha1.WorkflowExecution
+	instance.Status.Active = nil
+	for i := range executions.Items {
+		e := &executions.Items[i]
+		if !execution.Finished(e) {
+			active = append(active, e)
+			instance.Status.Active = append(instance.Status.Active, e.Name)
+			continue
+		}
+		last := instance.Status.LastSuccessfulTime
+		if e.Status.Phase == serverlessv1alpha1.ExecutionSucceeded && e.Status.CompletionTime != nil &&
+			(last == nil || last.Before(e.Status.CompletionTime)) {
+			instance.Status.LastSuccessfulTime = e.Status.CompletionTime.DeepCopy()
+		}
+	}
+	if err := r.pruneHistory(ctx, instance, executions.Items); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	loc, err := time.LoadLocation(instance.Spec.TimeZone)
+	if err != nil {
+		instance.Status.Message = "unknown time zone " + instance.Spec.TimeZone
+		return ctrl.Result{}, r.updateStatus(ctx, &original, instance)
+	}
+	schedule, err := cron.Parse(instance.Spec.Schedule)
+	if err != nil {
+		instance.Status.Message = err.Error()
+		return ctrl.Result{}, r.updateStatus(ctx, &original, instance)
+	}
+	if instance.Spec.Suspend {
+		instance.Status.NextScheduleTime = nil
+		instance.Status.Message = "suspended"
+		return ctrl.Result{}, r.updateStatus(ctx, &original, instance)
+	}
+
+	now := r.Clock.Now().In(loc)
+	since := instance.CreationTimestamp.Time
+	if instance.Status.LastScheduleTime != nil {
+		since = instance.Status.LastScheduleTime.Time
+	}
+	tick := lastTick(schedule, since.In(loc), now)
+	result := ctrl.Result{}
+	instance.Status.NextScheduleTime = nil
+	if next := schedule.Next(now); !next.IsZero() {
+		instance.Status.NextScheduleTime = &metav1.Time{Time: next}
+		result.RequeueAfter = next.Sub(now)
+	}
+	if tick.IsZero() {
+		return result, r.updateStatus(ctx, &original, instance)
+	}
+	instance.Status.LastScheduleTime = &metav1.Time{Time: tick}
+	instance.Status.Message = ""
+
+	switch instance.Spec.ConcurrencyPolicy {
+	case serverlessv1alpha1.ForbidConcurrent:
+		if len(active) != 0 {
+			instance.Status.Message = "the tick at " + tick.Format(time.RFC3339) +
+				" is skipped since the previous invocation is still running"
+			log.Info("tick skipped", "tick", tick, "active", instance.Status.Active)
+			return result, r.updateStatus(ctx, &original, instance)
+		}
+	case serverlessv1alpha1.ReplaceConcurrent:
+		for _, e := range active {
+			// deleting the WorkflowExecution cancels the invocation
+			if err := r.Delete(ctx, e); client.IgnoreNotFound(err) != nil {
+				log.Error(err, "unable to delete WorkflowExecution", "workflowexecution", e.Name)
+				return ctrl.Result{}, err
+			}
+			log.Info("WorkflowExecution replaced", "workflowexecution", e.Name)
+		}
+		instance.Status.Active = nil
+	}
+
+	e := &serverlessv1alpha1.WorkflowExecution{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: instance.Namespace,
+			// the name is derived from the tick, so that a tick is never run twice
+			Name: instance.Name + "-" + strconv.FormatInt(tick.Unix()/60, 10),
+			Labels: map[string]string{
+				execution.CronTriggerLabel: instance.Name,
+				execution.WorkflowLabel:    instance.Spec.Workflow,
+			},
+		},
+		Spec: serverlessv1alpha1.WorkflowExecutionSpec{
+			Workflow: instance.Spec.Workflow,
+			Input:    instance.Spec.Input,
+		},
+	}
+	if err := ctrl.SetControllerReference(instance, e, r.Scheme); err != nil {
+		return ctrl.Result{}, err
+	}
+	if err := r.Create(ctx, e); err != nil && !errors.IsAlreadyExists(err) {
+		log.Error(err, "unable to create WorkflowExecution")
+		return ctrl.Result{}, err
+	}
+	log.Info("WorkflowExecution created", "workflowexecution", e.Name, "tick", tick)
+	instance.Status.Active = append(instance.Status.Active, e.Name)
+	return result, r.updateStatus(ctx, &original, instance)
+}
+
+// lastTick returns the latest tick of the schedule in (since, now], or a zero time if there is none
+// Only the latest missed tick runs, the ticks missed before are dropped.
+func lastTick(schedule *cron.Schedule, since, now time.Time) time.Time {
+	var tick time.Time
+	n := 0
+	for t := schedule.Next(since); !t.IsZero() && !t.After(now); t = schedule.Next(t) {
+		tick = t
+		n++
+		if n > maxMissedTicks {
+			// too many ticks missed, only search the last hour
+			return lastTick(schedule, now.Add(-time.Hour), now)
+		}
+	}
+	return tick
+}
+
+// pruneHistory deletes the finished WorkflowExecutions beyond the history limits of the CronTrigger
+func (r *CronTriggerReconciler) pruneHistory(ctx context.Context, instance *serverlessv1alpha1.CronTrigger,
+	executions []serverlessv1alpha1.WorkflowExecution) error {
+	history := &serverlessv1alpha1.ExecutionHistory{
+		SucceededLimit: instance.Spec.SucceededHistoryLimit,
+		FailedLimit:    instance.Spec.FailedHistoryLimit,
+	}
+	if history.SucceededLimit == nil {
+		limit := int32(defaultCronSucceededHistoryLimit)
+		history.SucceededLimit = &limit
+	}
+	if history.FailedLimit == nil {
+		limit := int32(defaultCronFailedHistoryLimit)
+		history.FailedLimit = &limit
+	}
+	for _, e := range execution.Overflow(executions, history) {
+		if err := r.Delete(ctx, e); client.IgnoreNotFound(err) != nil {
+			r.Log.Error(err, "unable to delete WorkflowExecution", "workflowexecution", e.Name)
+			return err
+		}
+	}
+	return nil
+}
+
+// updateStatus updates the status of the CronTrigger if it has been changed
+func (r *CronTriggerReconciler) updateStatus(ctx context.Context, original,
+	instance *serverlessv1alpha1.CronTrigger) error {
+	if equality.Semantic.DeepEqual(original.Status, instance.Status) {
+		return nil
+	}
+	if err := r.Status().Update(ctx, instance); err != nil {
+		r.Log.Error(err, "unable to update status", "crontrigger", instance.Name)
+		return err
+	}
+	return nil
+}
+
+func (r *CronTriggerReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&serverlessv1alpha1.CronTrigger{}).
+		Owns(&serverlessv1alpha1.WorkflowExecution{}).
+		Complete(r)
+}
@@ -0,0 +1,174 @@
+/*
+
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/clock"
+	"k8s.io/client-go/kubernetes/scheme"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	serverlessv1alpha1 "github.com/tass-io/tass-operator/api/v1alpha1"
+	"github.com/tass-io/tass-operator/pkg/execution"
+)
+
+var _ = Describe("CronTrigger controller", func() {
+	var (
+		ctx        context.Context
+		fakeClock  *clock.FakeClock
+		reconciler *CronTriggerReconciler
+	)
+
+	newTrigger := func(name string, policy serverlessv1alpha1.ConcurrencyPolicy) types.NamespacedName {
+		trigger := &serverlessv1alpha1.CronTrigger{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: name},
+			Spec: serverlessv1alpha1.CronTriggerSpec{
+				Workflow:          "workflow-sample",
+				Schedule:          "*/5 * * * *",
+				Input:             `{"report": "daily"}`,
+				ConcurrencyPolicy: policy,
+			},
+		}
+		Expect(k8sClient.Create(ctx, trigger)).To(Succeed())
+		// start from the creation of the CronTrigger, the apiserver truncates it to seconds
+		fakeClock.SetTime(trigger.CreationTimestamp.Time)
+		return types.NamespacedName{Namespace: trigger.Namespace, Name: trigger.Name}
+	}
+
+	executionsOf := func(name string) []serverlessv1alpha1.WorkflowExecution {
+		var executions serverlessv1alpha1.WorkflowExecutionList
+		Expect(k8sClient.List(ctx, &executions, client.InNamespace("default"),
+			client.MatchingLabels{execution.CronTriggerLabel: name})).To(Succeed())
+		return executions.Items
+	}
+
+	reconcile := func(key types.NamespacedName) ctrl.Result {
+		result, err := reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: key})
+		Expect(err).NotTo(HaveOccurred())
+		return result
+	}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		fakeClock = clock.NewFakeClock(time.Now())
+		reconciler = &CronTriggerReconciler{
+			Client: k8sClient,
+			Log:    logf.Log.WithName("controllers").WithName("CronTrigger"),
+			Scheme: scheme.Scheme,
+			Clock:  fakeClock,
+		}
+	})
+
+	It("creates a WorkflowExecution at each tick", func() {
+		key := newTrigger("cron-allow", serverlessv1alpha1.AllowConcurrent)
+
+		result := reconcile(key)
+		Expect(executionsOf(key.Name)).To(BeEmpty())
+		Expect(result.RequeueAfter).To(BeNumerically(">", 0))
+		Expect(result.RequeueAfter).To(BeNumerically("<=", 5*time.Minute))
+
+		fakeClock.Step(result.RequeueAfter)
+		reconcile(key)
+		executions := executionsOf(key.Name)
+		Expect(executions).To(HaveLen(1))
+		Expect(executions[0].Spec.Workflow).To(Equal("workflow-sample"))
+		Expect(executions[0].Spec.Input).To(Equal(`{"report": "daily"}`))
+		Expect(metav1.GetControllerOf(&executions[0]).Name).To(Equal(key.Name))
+
+		var trigger serverlessv1alpha1.CronTrigger
+		Expect(k8sClient.Get(ctx, key, &trigger)).To(Succeed())
+		Expect(trigger.Status.LastScheduleTime).NotTo(BeNil())
+		Expect(trigger.Status.Active).To(ConsistOf(executions[0].Name))
+
+		// the same tick never runs twice
+		reconcile(key)
+		Expect(executionsOf(key.Name)).To(HaveLen(1))
+
+		// the invocations run at the same time with the Allow policy
+		fakeClock.Step(5 * time.Minute)
+		reconcile(key)
+		Expect(executionsOf(key.Name)).To(HaveLen(2))
+	})
+
+	It("skips the tick when the previous invocation is running with the Forbid policy", func() {
+		key := newTrigger("cron-forbid", serverlessv1alpha1.ForbidConcurrent)
+
+		fakeClock.Step(5 * time.Minute)
+		reconcile(key)
+		Expect(executionsOf(key.Name)).To(HaveLen(1))
+
+		fakeClock.Step(5 * time.Minute)
+		reconcile(key)
+		Expect(executionsOf(key.Name)).To(HaveLen(1))
+		var trigger serverlessv1alpha1.CronTrigger
+		Expect(k8sClient.Get(ctx, key, &trigger)).To(Succeed())
+		Expect(trigger.Status.Message).To(ContainSubstring("skipped"))
+
+		// the next tick runs once the previous invocation finished
+		previous := executionsOf(key.Name)[0]
+		now := metav1.NewTime(fakeClock.Now())
+		previous.Status.Phase = serverlessv1alpha1.ExecutionSucceeded
+		previous.Status.CompletionTime = &now
+		Expect(k8sClient.Status().Update(ctx, &previous)).To(Succeed())
+		fakeClock.Step(5 * time.Minute)
+		reconcile(key)
+		Expect(executionsOf(key.Name)).To(HaveLen(2))
+	})
+
+	It("replaces the running invocation with the Replace policy", func() {
+		key := newTrigger("cron-replace", serverlessv1alpha1.ReplaceConcurrent)
+
+		fakeClock.Step(5 * time.Minute)
+		reconcile(key)
+		first := executionsOf(key.Name)
+		Expect(first).To(HaveLen(1))
+
+		fakeClock.Step(5 * time.Minute)
+		reconcile(key)
+		second := executionsOf(key.Name)
+		Expect(second).To(HaveLen(1))
+		Expect(second[0].Name).NotTo(Equal(first[0].Name))
+	})
+
+	It("keeps the history within the limits", func() {
+		key := newTrigger("cron-history", serverlessv1alpha1.AllowConcurrent)
+		for i := 0; i < 3; i++ {
+			fakeClock.Step(5 * time.Minute)
+			reconcile(key)
+		}
+		executions := executionsOf(key.Name)
+		Expect(executions).To(HaveLen(3))
+		for i := range executions {
+			now := metav1.NewTime(fakeClock.Now())
+			executions[i].Status.Phase = serverlessv1alpha1.ExecutionFailed
+			executions[i].Status.CompletionTime = &now
+			Expect(k8sClient.Status().Update(ctx, &executions[i])).To(Succeed())
+		}
+
+		// only one failed WorkflowExecution is kept by default
+		reconcile(key)
+		Expect(executionsOf(key.Name)).To(HaveLen(1))
+	})
+})
@@ -0,0 +1,81 @@
+/*
+
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+
+	"github.com/go-logr/logr"
+	appsv1 "k8s.io/api/apps/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	serverlessv1alpha1 "github.com/tass-io/tass-operator/api/v1alpha1"
+	"github.com/tass-io/tass-operator/pkg/eventtrigger"
+)
+
+// EventTriggerReconciler reconciles a EventTrigger object
+type EventTriggerReconciler struct {
+	client.Client
+	Log    logr.Logger
+	Scheme *runtime.Scheme
+	// DispatcherImage is the image of the dispatcher Deployments
+	DispatcherImage string
+}
+
+// +kubebuilder:rbac:groups=serverless.tass.io,resources=eventtriggers,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=serverless.tass.io,resources=eventtriggers/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=apps,resources=deployments,verbs=get;list;watch;create;update;patch;delete
+
+func (r *EventTriggerReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := r.Log.WithValues("eventtrigger", req.NamespacedName)
+
+	var original serverlessv1alpha1.EventTrigger
+	if err := r.Get(ctx, req.NamespacedName, &original); err != nil {
+		if client.IgnoreNotFound(err) == nil {
+			return ctrl.Result{}, nil
+		}
+		log.Error(err, "unable to fetch EventTrigger")
+		return ctrl.Result{}, err
+	}
+
+	// An EventTrigger has its dispatcher Deployment which consumes the messages and invokes the Workflow
+	instance := original.DeepCopy()
+	etr, err := eventtrigger.NewReconciler(r.Client, log, r.Scheme, instance, r.DispatcherImage)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if err := etr.Reconcile(); err != nil {
+		return ctrl.Result{}, err
+	}
+	if !equality.Semantic.DeepEqual(original.Status, instance.Status) {
+		if err := r.Status().Update(ctx, instance); err != nil {
+			log.Error(err, "unable to update status")
+			return ctrl.Result{}, err
+		}
+	}
+	return ctrl.Result{}, nil
+}
+
+func (r *EventTriggerReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&serverlessv1alpha1.EventTrigger{}).
+		Owns(&appsv1.Deployment{}).
+		Complete(r)
+}
@@ -22,7 +22,9 @@ import (
 
 	"github.com/go-logr/logr"
 
+	"k8s.io/apimachinery/pkg/api/resource"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
@@ -33,15 +35,16 @@ import (
 // FunctionReconciler reconciles a Function object
 type FunctionReconciler struct {
 	client.Client
-	Log    logr.Logger
-	Scheme *runtime.Scheme
+	Log      logr.Logger
+	Scheme   *runtime.Scheme
+	Recorder record.EventRecorder
 }
 
 // +kubebuilder:rbac:groups=serverless.tass.io,resources=functions,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=serverless.tass.io,resources=functions/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups="",resources=events,verbs=create;patch
 
-func (r *FunctionReconciler) Reconcile(req ctrl.Request) (ctrl.Result, error) {
-	ctx := context.Background()
+func (r *FunctionReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
 	log := r.Log.WithValues("function", req.NamespacedName)
 
 	var instance serverlessv1alpha1.Function
@@ -49,6 +52,18 @@ func (r *FunctionReconciler) Reconcile(req ctrl.Request) (ctrl.Result, error) {
 		log.Error(err, "unable to fetch Function")
 		return ctrl.Result{}, client.IgnoreNotFound(err)
 	}
+
+	// the resource is a quantity string, the ones not parsed cannot be served in v1alpha2
+	for name, value := range map[string]string{
+		"cpu":    instance.Spec.Resource.ResourceCPU,
+		"memory": instance.Spec.Resource.ResourceMemory,
+	} {
+		if _, err := resource.ParseQuantity(value); err != nil {
+			log.Info("invalid Function resource", "resource", name, "value", value)
+			r.Recorder.Event(&instance, corev1.EventTypeWarning, "ValidationFailed",
+				"the "+name+" resource "+value+" is not a quantity: "+err.Error())
+		}
+	}
 	return ctrl.Result{}, nil
 }
 
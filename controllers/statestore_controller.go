@@ -0,0 +1,98 @@
+/*
+
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-logr/logr"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	serverlessv1alpha1 "github.com/tass-io/tass-operator/api/v1alpha1"
+	"github.com/tass-io/tass-operator/pkg/statestore"
+)
+
+// externalRetryInterval is how long to wait before checking the password Secret of an external store again
+const externalRetryInterval = 30 * time.Second
+
+// StateStoreReconciler reconciles a StateStore object
+type StateStoreReconciler struct {
+	client.Client
+	Log      logr.Logger
+	Scheme   *runtime.Scheme
+	Recorder record.EventRecorder
+}
+
+// +kubebuilder:rbac:groups=serverless.tass.io,resources=statestores,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=serverless.tass.io,resources=statestores/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=apps,resources=deployments,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups="",resources=services,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups="",resources=secrets,verbs=get;list;watch;create
+// +kubebuilder:rbac:groups="",resources=persistentvolumeclaims,verbs=get;list;watch;create
+// +kubebuilder:rbac:groups="",resources=events,verbs=create;patch
+
+func (r *StateStoreReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := r.Log.WithValues("statestore", req.NamespacedName)
+
+	var original serverlessv1alpha1.StateStore
+	if err := r.Get(ctx, req.NamespacedName, &original); err != nil {
+		if client.IgnoreNotFound(err) == nil {
+			return ctrl.Result{}, nil
+		}
+		log.Error(err, "unable to fetch StateStore")
+		return ctrl.Result{}, err
+	}
+
+	// A StateStore is either a Redis provisioned with its Deployment, Service, Secret and claim,
+	// or an external one whose connection details are only reported
+	instance := original.DeepCopy()
+	ssr, err := statestore.NewReconciler(ctx, r.Client, log, r.Scheme, r.Recorder, instance)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if err := ssr.Reconcile(); err != nil {
+		return ctrl.Result{}, err
+	}
+	if !equality.Semantic.DeepEqual(original.Status, instance.Status) {
+		if err := r.Status().Update(ctx, instance); err != nil {
+			log.Error(err, "unable to update status")
+			return ctrl.Result{}, err
+		}
+	}
+	// the password Secret of an external store is not owned, so it's not watched
+	if instance.Spec.External != nil && !instance.Status.Ready {
+		return ctrl.Result{RequeueAfter: externalRetryInterval}, nil
+	}
+	return ctrl.Result{}, nil
+}
+
+func (r *StateStoreReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&serverlessv1alpha1.StateStore{}).
+		Owns(&appsv1.Deployment{}).
+		Owns(&corev1.Service{}).
+		Owns(&corev1.Secret{}).
+		Owns(&corev1.PersistentVolumeClaim{}).
+		Complete(r)
+}
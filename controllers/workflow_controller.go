@@ -20,75 +20,193 @@ import (
 	"context"
 
 	"github.com/go-logr/logr"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1beta1 "k8s.io/api/networking/v1beta1"
+	"k8s.io/apimachinery/pkg/api/equality"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
 
 	serverlessv1alpha1 "github.com/tass-io/tass-operator/api/v1alpha1"
+	"github.com/tass-io/tass-operator/pkg/metrics"
+	"github.com/tass-io/tass-operator/pkg/tracing"
 	"github.com/tass-io/tass-operator/pkg/workflow"
 )
 
 // WorkflowReconciler reconciles a Workflow object
 type WorkflowReconciler struct {
 	client.Client
-	Log    logr.Logger
-	Scheme *runtime.Scheme
+	Log      logr.Logger
+	Scheme   *runtime.Scheme
+	Recorder record.EventRecorder
 }
 
 // +kubebuilder:rbac:groups=serverless.tass.io,resources=workflows,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=serverless.tass.io,resources=workflows/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=serverless.tass.io,resources=workflowrevisions,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=networking.k8s.io,resources=ingresses,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups="",resources=events,verbs=create;patch
 
-func (r *WorkflowReconciler) Reconcile(req ctrl.Request) (ctrl.Result, error) {
-	ctx := context.Background()
+func (r *WorkflowReconciler) Reconcile(ctx context.Context, req ctrl.Request) (result ctrl.Result, err error) {
 	log := r.Log.WithValues("workflow", req.NamespacedName)
 
 	var original serverlessv1alpha1.Workflow
 	if err := r.Get(ctx, req.NamespacedName, &original); err != nil {
 		log.Error(err, "unable to fetch Workflow")
+		if client.IgnoreNotFound(err) == nil {
+			metrics.DeleteWorkflow(req.Namespace, req.Name)
+		}
 		return ctrl.Result{}, client.IgnoreNotFound(err)
 	}
+	// the reconcile continues the trace of the one creating the Workflow if any
+	ctx, span := tracing.Start(tracing.Extract(ctx, &original), "Workflow.Reconcile", trace.WithAttributes(
+		attribute.String("k8s.namespace", req.Namespace), attribute.String("k8s.name", req.Name)))
+	defer func() { tracing.End(span, err) }()
 	log.Info("the Workflow Spec is", "spec", original.Spec.Spec)
 
+	if original.Spec.RollbackTo != nil {
+		// the restored Spec is reconciled after the update
+		return ctrl.Result{}, r.rollback(ctx, &original)
+	}
+
 	var functionList serverlessv1alpha1.FunctionList
 	if err := r.List(ctx, &functionList, client.InNamespace(req.Namespace)); err != nil {
 		log.Error(err, "unable to list child Functions")
 		return ctrl.Result{}, err
 	}
 
+	var workflowList serverlessv1alpha1.WorkflowList
+	if err := r.List(ctx, &workflowList, client.InNamespace(req.Namespace)); err != nil {
+		log.Error(err, "unable to list Workflows")
+		return ctrl.Result{}, err
+	}
+
 	// TODO: This kind of check should be placed in the admission webhook
 	// Put here temporarily
-	if err := workflow.ValidateFuncExist(&original, &functionList); err != nil {
-		log.Error(err, "workflow validation error")
+	// Only the Spec passing the checks is accepted, recorded as a WorkflowRevision and rolled out
+	_, validationSpan := tracing.Start(ctx, "Workflow.Validate")
+	verrs := workflow.Validate(&original, &functionList, &workflowList)
+	for _, verr := range verrs {
+		log.Error(verr, "workflow validation error")
+		r.Recorder.Event(&original, corev1.EventTypeWarning, "ValidationFailed", verr.Error())
 		// TODO: The webhook should ABORT directly
-		// Here we simply pass the check
-		// return ctrl.Result{}, nil
+		// Here the Spec is only held back from the rollout
 	}
-	if err := workflow.ValidateFlows(&original); err != nil {
-		log.Error(err, "workflow validation error")
-		// TODO: The webhook should ABORT directly
-		// Here we simply pass the check
-		// return ctrl.Result{}, nil
+	// the span only records the first failure, the events have all of them
+	var verr error
+	if len(verrs) != 0 {
+		verr = verrs[0]
+		validationSpan.SetAttributes(attribute.Int("workflow.validation.failures", len(verrs)))
 	}
+	tracing.End(validationSpan, verr)
+	accepted := len(verrs) == 0
 
-	// TODO: This is just an example status
-	if err := r.Status().Update(ctx, &original); err != nil {
-		log.Error(err, "unable to update status")
+	// the failures are counted once until the checks pass again
+	reasons := make([]string, 0, len(verrs))
+	for _, verr := range verrs {
+		reasons = append(reasons, verr.Reason)
 	}
+	metrics.SetWorkflowState(req.Namespace, req.Name, reasons)
 
 	// A Workflow has its WorkflowRuntime which run Functions in Workflow when a request comes
 	instance := original.DeepCopy()
-	wfr, err := workflow.NewReconciler(r.Client, log, r.Scheme, instance)
+	instance.Status.Graph = workflow.RenderMermaid(instance)
+	instance.Status.ValidationErrors = nil
+	for _, verr := range verrs {
+		instance.Status.ValidationErrors = append(instance.Status.ValidationErrors, verr.Error())
+	}
+	wfr, err := workflow.NewReconciler(ctx, r.Client, log, r.Scheme, r.Recorder, instance)
 	if err != nil {
 		return ctrl.Result{}, err
 	}
-	if err := wfr.Reconcile(); err != nil {
-		return ctrl.Result{}, err
+	if accepted {
+		// the Spec is recorded before it's rolled out, so that the revision serving can be rolled back to
+		if err := wfr.ReconcileHistory(&functionList); err != nil {
+			return ctrl.Result{}, err
+		}
+		if err := wfr.Reconcile(); err != nil {
+			return ctrl.Result{}, err
+		}
+	} else {
+		log.Info("the Spec is not accepted, the last accepted revision keeps serving")
 	}
-	return ctrl.Result{}, nil
+
+	if !equality.Semantic.DeepEqual(original.Status, instance.Status) {
+		if err := r.Status().Update(ctx, instance); err != nil {
+			log.Error(err, "unable to update status")
+			return ctrl.Result{}, err
+		}
+	}
+	// a progressing rollout is checked periodically
+	return ctrl.Result{RequeueAfter: wfr.RequeueAfter()}, nil
+}
+
+// rollback restores the WorkflowRevision the Workflow claims in RollbackTo and reports the result in the status
+func (r *WorkflowReconciler) rollback(ctx context.Context, wf *serverlessv1alpha1.Workflow) error {
+	log := r.Log.WithValues("workflow", types.NamespacedName{Namespace: wf.Namespace, Name: wf.Name})
+	revisions, err := workflow.ListRevisions(ctx, r.Client, wf)
+	if err != nil {
+		log.Error(err, "unable to list WorkflowRevisions")
+		return err
+	}
+	message, rollbackErr := workflow.Rollback(wf, revisions)
+	if err := r.Update(ctx, wf); err != nil {
+		log.Error(err, "unable to roll back")
+		r.Recorder.Event(wf, corev1.EventTypeWarning, "RollbackFailed", err.Error())
+		return err
+	}
+	if rollbackErr != nil {
+		// the revision will not show up by retrying, RollbackTo has been cleared
+		message = "rollback failed: " + rollbackErr.Error()
+		log.Error(rollbackErr, "rollback failed")
+		r.Recorder.Event(wf, corev1.EventTypeWarning, "RollbackFailed", message)
+	} else {
+		log.Info(message)
+		r.Recorder.Event(wf, corev1.EventTypeNormal, "RolledBack", message)
+	}
+	wf.Status.Message = message
+	if err := r.Status().Update(ctx, wf); err != nil {
+		log.Error(err, "unable to update status")
+		return err
+	}
+	return nil
 }
 
 func (r *WorkflowReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	return ctrl.NewControllerManagedBy(mgr).
 		For(&serverlessv1alpha1.Workflow{}).
+		Owns(&networkingv1beta1.Ingress{}).
+		Owns(&serverlessv1alpha1.WorkflowRevision{}).
+		// the WorkflowRuntime of a child Workflow is created by its own reconcile,
+		// the callers only validate their references again when it changes
+		Watches(
+			&source.Kind{Type: &serverlessv1alpha1.Workflow{}},
+			handler.EnqueueRequestsFromMapFunc(r.findObjsForChildWorkflow),
+		).
 		Complete(r)
 }
+
+// findObjsForChildWorkflow finds the Workflows calling a Workflow directly or through nested Workflows,
+// so that the cycles and the missing references are detected once it's created or changed
+func (r *WorkflowReconciler) findObjsForChildWorkflow(workflowMap client.Object) []reconcile.Request {
+	ns := workflowMap.GetNamespace()
+	var workflows serverlessv1alpha1.WorkflowList
+	if err := r.List(context.Background(), &workflows, client.InNamespace(ns)); err != nil {
+		r.Log.Error(err, "unable to list the Workflows calling the Workflow", "workflow", workflowMap.GetName())
+		return []reconcile.Request{}
+	}
+	requests := []reconcile.Request{}
+	for _, name := range workflow.Referrers(workflowMap.GetName(), &workflows) {
+		requests = append(requests, reconcile.Request{
+			NamespacedName: types.NamespacedName{Namespace: ns, Name: name},
+		})
+	}
+	return requests
+}
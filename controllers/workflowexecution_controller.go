@@ -0,0 +1,237 @@
+/*
+
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/go-logr/logr"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/retry"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	serverlessv1alpha1 "github.com/tass-io/tass-operator/api/v1alpha1"
+	"github.com/tass-io/tass-operator/pkg/execution"
+)
+
+// WorkflowExecutionReconciler reconciles a WorkflowExecution object
+type WorkflowExecutionReconciler struct {
+	client.Client
+	Log     logr.Logger
+	Scheme  *runtime.Scheme
+	Invoker execution.Invoker
+
+	// cancels holds the cancel functions of the running invocations by the NamespacedName,
+	// an invocation is cancelled when its WorkflowExecution is deleted
+	cancels sync.Map
+}
+
+// +kubebuilder:rbac:groups=serverless.tass.io,resources=workflowexecutions,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=serverless.tass.io,resources=workflowexecutions/status,verbs=get;update;patch
+
+func (r *WorkflowExecutionReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := r.Log.WithValues("workflowexecution", req.NamespacedName)
+
+	var original serverlessv1alpha1.WorkflowExecution
+	if err := r.Get(ctx, req.NamespacedName, &original); err != nil {
+		if client.IgnoreNotFound(err) == nil {
+			if cancel, ok := r.cancels.Load(req.NamespacedName); ok {
+				log.Info("WorkflowExecution deleted, cancel the invocation")
+				cancel.(context.CancelFunc)()
+			}
+			return ctrl.Result{}, nil
+		}
+		log.Error(err, "unable to fetch WorkflowExecution")
+		return ctrl.Result{}, err
+	}
+	instance := original.DeepCopy()
+
+	var wf serverlessv1alpha1.Workflow
+	if err := r.Get(ctx, types.NamespacedName{
+		Namespace: req.Namespace,
+		Name:      instance.Spec.Workflow,
+	}, &wf); err != nil {
+		if client.IgnoreNotFound(err) != nil {
+			log.Error(err, "unable to fetch Workflow")
+			return ctrl.Result{}, err
+		}
+		if instance.Spec.Reported || instance.Status.Phase != "" {
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, r.fail(ctx, instance, "workflow "+instance.Spec.Workflow+" not found")
+	}
+
+	// the WorkflowExecutions are labelled and owned by the Workflow,
+	// so that they are listed by the Workflow and removed along with it
+	if instance.Labels[execution.WorkflowLabel] != wf.Name || metav1.GetControllerOf(instance) == nil {
+		if instance.Labels == nil {
+			instance.Labels = map[string]string{}
+		}
+		instance.Labels[execution.WorkflowLabel] = wf.Name
+		if err := ctrl.SetControllerReference(&wf, instance, r.Scheme); err != nil {
+			return ctrl.Result{}, err
+		}
+		if err := r.Update(ctx, instance); err != nil {
+			log.Error(err, "unable to update WorkflowExecution")
+			return ctrl.Result{}, err
+		}
+		// the update triggers another reconciliation
+		return ctrl.Result{}, nil
+	}
+
+	switch {
+	case !instance.Spec.Reported && instance.Status.Phase == "":
+		return r.trigger(ctx, instance, &wf)
+	case !instance.Spec.Reported && instance.Status.Phase == serverlessv1alpha1.ExecutionRunning:
+		// the result may never come back if the operator restarted during the invocation
+		left := instance.Status.StartTime.Add(execution.Timeout(&wf)).Sub(time.Now())
+		if left > 0 {
+			return ctrl.Result{RequeueAfter: left}, nil
+		}
+		return ctrl.Result{}, r.fail(ctx, instance, "the result has not come back in time")
+	case execution.Finished(instance):
+		return r.retain(ctx, instance, &wf)
+	}
+	return ctrl.Result{}, nil
+}
+
+// trigger marks the WorkflowExecution running and invokes the Workflow in the background
+// The status update fails on conflicts, so that a WorkflowExecution is triggered only once.
+func (r *WorkflowExecutionReconciler) trigger(ctx context.Context, instance *serverlessv1alpha1.WorkflowExecution,
+	wf *serverlessv1alpha1.Workflow) (ctrl.Result, error) {
+	log := r.Log.WithValues("workflowexecution", types.NamespacedName{
+		Namespace: instance.Namespace,
+		Name:      instance.Name,
+	})
+	input := instance.Spec.Input
+	if input == "" {
+		input = "{}"
+	}
+	if !json.Valid([]byte(input)) {
+		return ctrl.Result{}, r.fail(ctx, instance, "input is not a legal JSON")
+	}
+
+	now := metav1.Now()
+	instance.Status.Phase = serverlessv1alpha1.ExecutionRunning
+	instance.Status.StartTime = &now
+	if err := r.Status().Update(ctx, instance); err != nil {
+		log.Error(err, "unable to update status")
+		return ctrl.Result{}, err
+	}
+	log.Info("WorkflowExecution triggered", "workflow", wf.Name)
+
+	timeout := execution.Timeout(wf)
+	key := types.NamespacedName{Namespace: instance.Namespace, Name: instance.Name}
+	req := execution.Request{
+		WorkflowName: wf.Name,
+		FlowName:     instance.Spec.Flow,
+		Parameters:   json.RawMessage(input),
+	}
+	invokeCtx, cancel := context.WithTimeout(context.Background(), timeout)
+	r.cancels.Store(key, cancel)
+	go func() {
+		defer func() {
+			r.cancels.Delete(key)
+			cancel()
+		}()
+		output, err := r.Invoker.Invoke(invokeCtx, key.Namespace, req)
+		if err := r.complete(key, output, err); err != nil {
+			log.Error(err, "unable to record the result")
+		}
+	}()
+	return ctrl.Result{RequeueAfter: timeout}, nil
+}
+
+// complete records the result of the invocation
+func (r *WorkflowExecutionReconciler) complete(key types.NamespacedName, output string, invokeErr error) error {
+	ctx := context.Background()
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		var instance serverlessv1alpha1.WorkflowExecution
+		if err := r.Get(ctx, key, &instance); err != nil {
+			return client.IgnoreNotFound(err)
+		}
+		if execution.Finished(&instance) {
+			return nil
+		}
+		now := metav1.Now()
+		instance.Status.CompletionTime = &now
+		if invokeErr != nil {
+			instance.Status.Phase = serverlessv1alpha1.ExecutionFailed
+			instance.Status.Error = &serverlessv1alpha1.ExecutionError{Message: invokeErr.Error()}
+		} else {
+			instance.Status.Phase = serverlessv1alpha1.ExecutionSucceeded
+			instance.Status.Output = output
+		}
+		return r.Status().Update(ctx, &instance)
+	})
+}
+
+// fail marks the WorkflowExecution failed with the message
+func (r *WorkflowExecutionReconciler) fail(ctx context.Context, instance *serverlessv1alpha1.WorkflowExecution,
+	message string) error {
+	now := metav1.Now()
+	if instance.Status.StartTime == nil {
+		instance.Status.StartTime = &now
+	}
+	instance.Status.CompletionTime = &now
+	instance.Status.Phase = serverlessv1alpha1.ExecutionFailed
+	instance.Status.Error = &serverlessv1alpha1.ExecutionError{Message: message}
+	if err := r.Status().Update(ctx, instance); err != nil {
+		r.Log.Error(err, "unable to update status", "workflowexecution", instance.Name)
+		return err
+	}
+	return nil
+}
+
+// retain removes the finished WorkflowExecution when it outlives the TTL of the Workflow,
+// and the oldest finished WorkflowExecutions of the Workflow beyond the history limits
+func (r *WorkflowExecutionReconciler) retain(ctx context.Context, instance *serverlessv1alpha1.WorkflowExecution,
+	wf *serverlessv1alpha1.Workflow) (ctrl.Result, error) {
+	log := r.Log.WithValues("workflow", types.NamespacedName{Namespace: wf.Namespace, Name: wf.Name})
+	expired, left := execution.Expire(instance, wf.Spec.History, time.Now())
+	if expired {
+		log.Info("WorkflowExecution expired", "workflowexecution", instance.Name)
+		return ctrl.Result{}, client.IgnoreNotFound(r.Delete(ctx, instance))
+	}
+
+	var executions serverlessv1alpha1.WorkflowExecutionList
+	if err := r.List(ctx, &executions, client.InNamespace(wf.Namespace),
+		client.MatchingLabels{execution.WorkflowLabel: wf.Name}); err != nil {
+		log.Error(err, "unable to list WorkflowExecutions")
+		return ctrl.Result{}, err
+	}
+	for _, e := range execution.Overflow(executions.Items, wf.Spec.History) {
+		log.Info("WorkflowExecution beyond the history limits", "workflowexecution", e.Name)
+		if err := r.Delete(ctx, e); client.IgnoreNotFound(err) != nil {
+			log.Error(err, "unable to delete WorkflowExecution", "workflowexecution", e.Name)
+			return ctrl.Result{}, err
+		}
+	}
+	return ctrl.Result{RequeueAfter: left}, nil
+}
+
+func (r *WorkflowExecutionReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&serverlessv1alpha1.WorkflowExecution{}).
+		Complete(r)
+}
@@ -20,14 +20,20 @@ import (
 	"context"
 
 	"github.com/go-logr/logr"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	serverlessv1alpha1 "github.com/tass-io/tass-operator/api/v1alpha1"
 	"github.com/tass-io/tass-operator/pkg/endpointslice"
+	"github.com/tass-io/tass-operator/pkg/metrics"
+	"github.com/tass-io/tass-operator/pkg/tracing"
 	"github.com/tass-io/tass-operator/pkg/workflowruntime"
 	discoveryv1beta1 "k8s.io/api/discovery/v1beta1"
+	rbacv1 "k8s.io/api/rbac/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
@@ -37,24 +43,32 @@ import (
 // WorkflowRuntimeReconciler reconciles a WorkflowRuntime object
 type WorkflowRuntimeReconciler struct {
 	client.Client
-	Log    logr.Logger
-	Scheme *runtime.Scheme
+	Log      logr.Logger
+	Scheme   *runtime.Scheme
+	Recorder record.EventRecorder
 }
 
 // nolint
 // +kubebuilder:rbac:groups=serverless.tass.io,resources=workflowruntimes,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=serverless.tass.io,resources=workflowruntimes/status,verbs=get;update;patch
 // +kubebuilder:rbac:groups=discovery.k8s.io,resources=endpointslices,verbs=get;list;watch;create;update;patch;delete
-
-func (r *WorkflowRuntimeReconciler) Reconcile(req ctrl.Request) (ctrl.Result, error) {
-	ctx := context.Background()
+// +kubebuilder:rbac:groups=serverless.tass.io,resources=statestores,verbs=get;list;watch
+// +kubebuilder:rbac:groups=apps,resources=deployments,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups="",resources=services;serviceaccounts,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=rbac.authorization.k8s.io,resources=roles;rolebindings,verbs=get;list;watch;create;update;patch;delete
+// The operator can only grant the permissions it has, the schedulers read the Pods, Workflows and Functions
+// +kubebuilder:rbac:groups="",resources=pods,verbs=get;list;watch
+// +kubebuilder:rbac:groups=serverless.tass.io,resources=functions,verbs=get;list;watch
+// +kubebuilder:rbac:groups="",resources=events,verbs=create;patch
+
+func (r *WorkflowRuntimeReconciler) Reconcile(ctx context.Context, req ctrl.Request) (result ctrl.Result, err error) {
 	log := r.Log.WithValues("workflowruntime", req.NamespacedName)
 
 	var eps discoveryv1beta1.EndpointSlice
 	if err := r.Get(ctx, req.NamespacedName, &eps); err == nil {
 		log.Info("fetch endpointslice successfully")
 		neweps := eps.DeepCopy()
-		epsr, _ := endpointslice.NewReconciler(r.Client, log, r.Scheme, neweps)
+		epsr, _ := endpointslice.NewReconciler(ctx, r.Client, log, r.Scheme, r.Recorder, neweps)
 		if err := epsr.Reconcile(); err != nil {
 			return ctrl.Result{}, err
 		}
@@ -64,12 +78,19 @@ func (r *WorkflowRuntimeReconciler) Reconcile(req ctrl.Request) (ctrl.Result, er
 	var original serverlessv1alpha1.WorkflowRuntime
 	if err := r.Get(ctx, req.NamespacedName, &original); err != nil {
 		if client.IgnoreNotFound(err) == nil {
+			metrics.DeleteWorkflowRuntime(req.Namespace, req.Name)
 			return ctrl.Result{}, nil
 		}
 		log.Error(err, "unable to fetch WorkflowRuntime")
 		return ctrl.Result{}, err
 	}
 
+	metrics.SetWorkflowRuntime(&original)
+	// the reconcile continues the trace of the Workflow reconcile creating the WorkflowRuntime
+	ctx, span := tracing.Start(tracing.Extract(ctx, &original), "WorkflowRuntime.Reconcile", trace.WithAttributes(
+		attribute.String("k8s.namespace", req.Namespace), attribute.String("k8s.name", req.Name)))
+	defer func() { tracing.End(span, err) }()
+
 	// A WorkflowRuntime has its Service and Deployment which
 	// run local scheduler and Flows in Workflow when a request comes
 	labels := map[string]string{
@@ -77,7 +98,7 @@ func (r *WorkflowRuntimeReconciler) Reconcile(req ctrl.Request) (ctrl.Result, er
 		"name": req.NamespacedName.Name,
 	}
 	instance := original.DeepCopy()
-	wfrtr, err := workflowruntime.NewReconciler(r.Client, log, r.Scheme, instance, labels)
+	wfrtr, err := workflowruntime.NewReconciler(ctx, r.Client, log, r.Scheme, r.Recorder, instance, labels)
 	if err != nil {
 		return ctrl.Result{}, err
 	}
@@ -91,23 +112,49 @@ func (r *WorkflowRuntimeReconciler) Reconcile(req ctrl.Request) (ctrl.Result, er
 func (r *WorkflowRuntimeReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	return ctrl.NewControllerManagedBy(mgr).
 		For(&serverlessv1alpha1.WorkflowRuntime{}).
+		// the Roles and RoleBindings changed by hand are restored
+		Owns(&rbacv1.Role{}).
+		Owns(&rbacv1.RoleBinding{}).
 		Watches(
 			&source.Kind{Type: &discoveryv1beta1.EndpointSlice{}},
-			&handler.EnqueueRequestsFromMapFunc{
-				ToRequests: handler.ToRequestsFunc(r.findObjsForEndpointSlice),
-			},
+			handler.EnqueueRequestsFromMapFunc(r.findObjsForEndpointSlice),
+		).
+		Watches(
+			&source.Kind{Type: &serverlessv1alpha1.StateStore{}},
+			handler.EnqueueRequestsFromMapFunc(r.findObjsForStateStore),
 		).
 		Complete(r)
 }
 
+// findObjsForStateStore returns the WorkflowRuntimes referencing the StateStore,
+// so that their schedulers are deployed once it's ready and follow the changes of its connection details
+func (r *WorkflowRuntimeReconciler) findObjsForStateStore(stateStoreMap client.Object) []reconcile.Request {
+	ns := stateStoreMap.GetNamespace()
+	var wfrts serverlessv1alpha1.WorkflowRuntimeList
+	if err := r.List(context.Background(), &wfrts, client.InNamespace(ns)); err != nil {
+		r.Log.Error(err, "unable to list the WorkflowRuntimes of the StateStore",
+			"statestore", stateStoreMap.GetName())
+		return []reconcile.Request{}
+	}
+	requests := []reconcile.Request{}
+	for _, wfrt := range wfrts.Items {
+		if wfrt.Spec != nil && wfrt.Spec.StateStore == stateStoreMap.GetName() {
+			requests = append(requests, reconcile.Request{
+				NamespacedName: types.NamespacedName{Namespace: ns, Name: wfrt.Name},
+			})
+		}
+	}
+	return requests
+}
+
 // findObjsForEndpointSlice is used to find an endpointslice for workflowruntime.
-// This func is the implementation of `ToRequestsFunc func(MapObject) []reconcile.Request`.
+// This func is the implementation of `MapFunc func(client.Object) []reconcile.Request`.
 // When the controller manager starts, it iterates all endpointslices and chooses suitable resluts
 // For the chosen object, they will be replaced in `reconcile.Request` slice,
 // and then this request will be reconciled by `WorkflowRuntimeReconciler.Reconcile` func.
-func (r *WorkflowRuntimeReconciler) findObjsForEndpointSlice(endpointsliceMap handler.MapObject) []reconcile.Request {
-	ns := endpointsliceMap.Meta.GetNamespace()
-	endpointsliceLabels := endpointsliceMap.Meta.GetLabels()
+func (r *WorkflowRuntimeReconciler) findObjsForEndpointSlice(endpointsliceMap client.Object) []reconcile.Request {
+	ns := endpointsliceMap.GetNamespace()
+	endpointsliceLabels := endpointsliceMap.GetLabels()
 	// this name is the name of the Service name and is the same as Workflow name
 	// so we can get WorkflowRuntime by namespace & name
 	name, ok := endpointsliceLabels["kubernetes.io/service-name"]
@@ -127,7 +174,7 @@ func (r *WorkflowRuntimeReconciler) findObjsForEndpointSlice(endpointsliceMap ha
 		//
 		// {
 		// 	NamespacedName: types.NamespacedName{
-		// 		Name:      endpointsliceMap.Meta.GetName() + "-endpointslice",
+		// 		Name:      endpointsliceMap.GetName() + "-endpointslice",
 		// 		Namespace: ns,
 		// 	},
 		// }
@@ -136,7 +183,7 @@ func (r *WorkflowRuntimeReconciler) findObjsForEndpointSlice(endpointsliceMap ha
 		return []reconcile.Request{
 			{
 				NamespacedName: types.NamespacedName{
-					Name:      endpointsliceMap.Meta.GetName(),
+					Name:      endpointsliceMap.GetName(),
 					Namespace: ns,
 				},
 			},
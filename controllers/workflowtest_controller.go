@@ -0,0 +1,117 @@
+/*
+
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+
+	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	serverlessv1alpha1 "github.com/tass-io/tass-operator/api/v1alpha1"
+	"github.com/tass-io/tass-operator/pkg/workflow"
+)
+
+// WorkflowTestReconciler reconciles a WorkflowTest object
+type WorkflowTestReconciler struct {
+	client.Client
+	Log    logr.Logger
+	Scheme *runtime.Scheme
+}
+
+// +kubebuilder:rbac:groups=serverless.tass.io,resources=workflowtests,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=serverless.tass.io,resources=workflowtests/status,verbs=get;update;patch
+
+func (r *WorkflowTestReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := r.Log.WithValues("workflowtest", req.NamespacedName)
+
+	var original serverlessv1alpha1.WorkflowTest
+	if err := r.Get(ctx, req.NamespacedName, &original); err != nil {
+		if client.IgnoreNotFound(err) == nil {
+			return ctrl.Result{}, nil
+		}
+		log.Error(err, "unable to fetch WorkflowTest")
+		return ctrl.Result{}, err
+	}
+	instance := original.DeepCopy()
+
+	var wf serverlessv1alpha1.Workflow
+	if err := r.Get(ctx, types.NamespacedName{
+		Namespace: req.Namespace,
+		Name:      instance.Spec.Workflow,
+	}, &wf); err != nil {
+		if client.IgnoreNotFound(err) != nil {
+			log.Error(err, "unable to fetch Workflow")
+			return ctrl.Result{}, err
+		}
+		// the WorkflowTest is reconciled again when the Workflow is created
+		instance.Status = serverlessv1alpha1.WorkflowTestStatus{
+			Phase:   serverlessv1alpha1.TestFailed,
+			Message: "workflow " + instance.Spec.Workflow + " not found",
+		}
+	} else {
+		instance.Status = workflow.Simulate(&wf, instance.Spec.Input, instance.Spec.Mocks)
+	}
+	instance.Status.ObservedGeneration = instance.Generation
+
+	if err := r.Status().Update(ctx, instance); err != nil {
+		log.Error(err, "unable to update status")
+		return ctrl.Result{}, err
+	}
+	log.Info("WorkflowTest finished", "phase", instance.Status.Phase)
+	return ctrl.Result{}, nil
+}
+
+func (r *WorkflowTestReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&serverlessv1alpha1.WorkflowTest{}).
+		Watches(
+			&source.Kind{Type: &serverlessv1alpha1.Workflow{}},
+			handler.EnqueueRequestsFromMapFunc(r.findObjsForWorkflow),
+		).
+		Complete(r)
+}
+
+// findObjsForWorkflow finds the WorkflowTests of a Workflow,
+// so that the dry runs are computed again when the Workflow changes.
+func (r *WorkflowTestReconciler) findObjsForWorkflow(workflowMap client.Object) []reconcile.Request {
+	var tests serverlessv1alpha1.WorkflowTestList
+	if err := r.List(context.Background(), &tests,
+		client.InNamespace(workflowMap.GetNamespace())); err != nil {
+		r.Log.Error(err, "unable to list WorkflowTests")
+		return []reconcile.Request{}
+	}
+	requests := []reconcile.Request{}
+	for _, test := range tests.Items {
+		if test.Spec.Workflow != workflowMap.GetName() {
+			continue
+		}
+		requests = append(requests, reconcile.Request{
+			NamespacedName: types.NamespacedName{
+				Namespace: test.Namespace,
+				Name:      test.Name,
+			},
+		})
+	}
+	return requests
+}
@@ -17,17 +17,22 @@ limitations under the License.
 package main
 
 import (
+	"context"
 	"flag"
 	"os"
 
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/clock"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
 	_ "k8s.io/client-go/plugin/pkg/client/auth/gcp"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
 
 	serverlessv1alpha1 "github.com/tass-io/tass-operator/api/v1alpha1"
+	serverlessv1alpha2 "github.com/tass-io/tass-operator/api/v1alpha2"
 	"github.com/tass-io/tass-operator/controllers"
+	"github.com/tass-io/tass-operator/pkg/execution"
+	"github.com/tass-io/tass-operator/pkg/tracing"
 	// +kubebuilder:scaffold:imports
 )
 
@@ -40,20 +45,37 @@ func init() {
 	_ = clientgoscheme.AddToScheme(scheme)
 
 	_ = serverlessv1alpha1.AddToScheme(scheme)
+	_ = serverlessv1alpha2.AddToScheme(scheme)
 	// +kubebuilder:scaffold:scheme
 }
 
 func main() {
 	var metricsAddr string
 	var enableLeaderElection bool
+	var dispatcherImage string
+	var traceExporter, traceFile, traceEndpoint string
 	flag.StringVar(&metricsAddr, "metrics-addr", ":8080", "The address the metric endpoint binds to.")
 	flag.BoolVar(&enableLeaderElection, "enable-leader-election", false,
 		"Enable leader election for controller manager. "+
 			"Enabling this will ensure there is only one active controller manager.")
+	flag.StringVar(&dispatcherImage, "dispatcher-image", "registry.cn-shanghai.aliyuncs.com/tassio/dispatcher:v0.1.0",
+		"The image of the dispatchers deployed for the EventTriggers.")
+	flag.StringVar(&traceExporter, "trace-exporter", tracing.ExporterNone,
+		"The exporter of the reconcile traces, one of none, stdout, file and otlp.")
+	flag.StringVar(&traceFile, "trace-file", "", "The file the traces are appended to with the file exporter.")
+	flag.StringVar(&traceEndpoint, "trace-endpoint", "",
+		"The OpenTelemetry collector the otlp exporter sends the traces to, e.g. http://otel-collector:4317. "+
+			"If empty, OTEL_EXPORTER_OTLP_ENDPOINT is used.")
 	flag.Parse()
 
 	ctrl.SetLogger(zap.New(zap.UseDevMode(true)))
 
+	shutdownTracing, err := tracing.Setup(traceExporter, traceFile, traceEndpoint)
+	if err != nil {
+		setupLog.Error(err, "unable to set up tracing")
+		os.Exit(1)
+	}
+
 	mgr, err := ctrl.NewManager(ctrl.GetConfigOrDie(), ctrl.Options{
 		Scheme:             scheme,
 		MetricsBindAddress: metricsAddr,
@@ -67,29 +89,95 @@ func main() {
 	}
 
 	if err = (&controllers.WorkflowReconciler{
-		Client: mgr.GetClient(),
-		Log:    ctrl.Log.WithName("controllers").WithName("Workflow"),
-		Scheme: mgr.GetScheme(),
+		Client:   tracing.NewClient(mgr.GetClient()),
+		Log:      ctrl.Log.WithName("controllers").WithName("Workflow"),
+		Scheme:   mgr.GetScheme(),
+		Recorder: mgr.GetEventRecorderFor("workflow-controller"),
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "Workflow")
 		os.Exit(1)
 	}
 	if err = (&controllers.FunctionReconciler{
-		Client: mgr.GetClient(),
-		Log:    ctrl.Log.WithName("controllers").WithName("Function"),
-		Scheme: mgr.GetScheme(),
+		Client:   mgr.GetClient(),
+		Log:      ctrl.Log.WithName("controllers").WithName("Function"),
+		Scheme:   mgr.GetScheme(),
+		Recorder: mgr.GetEventRecorderFor("function-controller"),
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "Function")
 		os.Exit(1)
 	}
 	if err = (&controllers.WorkflowRuntimeReconciler{
+		Client:   tracing.NewClient(mgr.GetClient()),
+		Log:      ctrl.Log.WithName("controllers").WithName("WorkflowRuntime"),
+		Scheme:   mgr.GetScheme(),
+		Recorder: mgr.GetEventRecorderFor("workflowruntime-controller"),
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "WorkflowRuntime")
+		os.Exit(1)
+	}
+	if err = (&controllers.StateStoreReconciler{
+		Client:   mgr.GetClient(),
+		Log:      ctrl.Log.WithName("controllers").WithName("StateStore"),
+		Scheme:   mgr.GetScheme(),
+		Recorder: mgr.GetEventRecorderFor("statestore-controller"),
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "StateStore")
+		os.Exit(1)
+	}
+	if err = (&controllers.WorkflowTestReconciler{
 		Client: mgr.GetClient(),
-		Log:    ctrl.Log.WithName("controllers").WithName("WorkflowRuntime"),
+		Log:    ctrl.Log.WithName("controllers").WithName("WorkflowTest"),
 		Scheme: mgr.GetScheme(),
 	}).SetupWithManager(mgr); err != nil {
-		setupLog.Error(err, "unable to create controller", "controller", "WorkflowRuntime")
+		setupLog.Error(err, "unable to create controller", "controller", "WorkflowTest")
+		os.Exit(1)
+	}
+	if err = (&controllers.WorkflowExecutionReconciler{
+		Client:  mgr.GetClient(),
+		Log:     ctrl.Log.WithName("controllers").WithName("WorkflowExecution"),
+		Scheme:  mgr.GetScheme(),
+		Invoker: execution.NewHTTPInvoker(),
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "WorkflowExecution")
+		os.Exit(1)
+	}
+	if err = (&controllers.CronTriggerReconciler{
+		Client: mgr.GetClient(),
+		Log:    ctrl.Log.WithName("controllers").WithName("CronTrigger"),
+		Scheme: mgr.GetScheme(),
+		Clock:  clock.RealClock{},
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "CronTrigger")
 		os.Exit(1)
 	}
+	if err = (&controllers.EventTriggerReconciler{
+		Client:          mgr.GetClient(),
+		Log:             ctrl.Log.WithName("controllers").WithName("EventTrigger"),
+		Scheme:          mgr.GetScheme(),
+		DispatcherImage: dispatcherImage,
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "EventTrigger")
+		os.Exit(1)
+	}
+	// The webhooks need the serving certificates, set ENABLE_WEBHOOKS=false to run the manager locally without them
+	if os.Getenv("ENABLE_WEBHOOKS") != "false" {
+		if err = (&serverlessv1alpha1.Workflow{}).SetupWebhookWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "Workflow")
+			os.Exit(1)
+		}
+		if err = (&serverlessv1alpha2.Workflow{}).SetupWebhookWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "Workflow")
+			os.Exit(1)
+		}
+		if err = (&serverlessv1alpha1.Function{}).SetupWebhookWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "Function")
+			os.Exit(1)
+		}
+		if err = (&serverlessv1alpha1.WorkflowRuntime{}).SetupWebhookWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "WorkflowRuntime")
+			os.Exit(1)
+		}
+	}
 	// +kubebuilder:scaffold:builder
 
 	setupLog.Info("Starting manager...")
@@ -97,4 +185,7 @@ func main() {
 		setupLog.Error(err, "problem running manager")
 		os.Exit(1)
 	}
+	if err := shutdownTracing(context.Background()); err != nil {
+		setupLog.Error(err, "unable to flush the traces")
+	}
 }
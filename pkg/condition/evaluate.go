@@ -0,0 +1,259 @@
+// Package condition evaluates the Conditions of a switch Flow against a Flow result.
+// It is the canonical implementation of the semantics documented in the Workflow API,
+// so that the validation, the dry-run tools and the schedulers agree on
+// which Flows the result goes to.
+package condition
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
+
+	serverlessv1alpha1 "github.com/tass-io/tass-operator/api/v1alpha1"
+)
+
+// Group is the Condition group of a switch Flow, the key is the name of the Condition
+type Group map[string]*serverlessv1alpha1.Condition
+
+// NewGroup returns the Group of the Conditions, the names of the Conditions must be unique
+func NewGroup(conditions []*serverlessv1alpha1.Condition) (Group, error) {
+	g := Group{}
+	for _, condition := range conditions {
+		if condition == nil {
+			return nil, errors.New("condition should not be null")
+		}
+		if _, ok := g[condition.Name]; ok {
+			return nil, errors.New("condition " + condition.Name + " has defined more than once")
+		}
+		g[condition.Name] = condition
+	}
+	return g, nil
+}
+
+// Decision records a Condition evaluated when walking the Destination tree
+type Decision struct {
+	// Condition is the name of the Condition
+	Condition string
+	// Result is whether the Condition is satisfied
+	Result bool
+}
+
+// Next returns the downstream Flows the result of the Flow goes to,
+// and the Decisions made on the way in the order of evaluation.
+// For a switch Flow, it starts from the root Condition, the first element of the Conditions,
+// and follows the Destination of each Condition; otherwise all Outputs are returned.
+// The result is the decoded JSON object wrapped by the Flow runtime, see Decode.
+func Next(flow *serverlessv1alpha1.Flow, result interface{}) ([]string, []Decision, error) {
+	if flow.Statement != serverlessv1alpha1.Switch {
+		return flow.Outputs, nil, nil
+	}
+	if len(flow.Conditions) == 0 {
+		return nil, nil, errors.New("flow " + flow.Name + " has no condition")
+	}
+	g, err := NewGroup(flow.Conditions)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var flows []string
+	var decisions []Decision
+	seenFlows := map[string]bool{}
+	seenConditions := map[string]bool{}
+	var walk func(name string) error
+	walk = func(name string) error {
+		if seenConditions[name] {
+			return nil
+		}
+		seenConditions[name] = true
+		satisfied, err := g.Evaluate(name, result)
+		if err != nil {
+			return err
+		}
+		decisions = append(decisions, Decision{Condition: name, Result: satisfied})
+		next := g[name].Destination.IsFalse
+		if satisfied {
+			next = g[name].Destination.IsTrue
+		}
+		for _, f := range next.Flows {
+			if !seenFlows[f] {
+				seenFlows[f] = true
+				flows = append(flows, f)
+			}
+		}
+		for _, c := range next.Conditions {
+			if err := walk(c); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	if err := walk(flow.Conditions[0].Name); err != nil {
+		return nil, decisions, fmt.Errorf("flow %s: %v", flow.Name, err)
+	}
+	return flows, decisions, nil
+}
+
+// Evaluate returns whether the named Condition is satisfied by the result
+func (g Group) Evaluate(name string, result interface{}) (bool, error) {
+	return g.evaluate(name, result, map[string]bool{})
+}
+
+func (g Group) evaluate(name string, result interface{}, visiting map[string]bool) (bool, error) {
+	condition, ok := g[name]
+	if !ok {
+		return false, errors.New("condition " + name + " has not define")
+	}
+	if visiting[name] {
+		return false, errors.New("condition " + name + " refers to itself")
+	}
+	visiting[name] = true
+	defer delete(visiting, name)
+
+	switch {
+	case len(condition.All) != 0:
+		for _, operand := range condition.All {
+			satisfied, err := g.evaluate(operand, result, visiting)
+			if err != nil || !satisfied {
+				return false, err
+			}
+		}
+		return true, nil
+	case len(condition.Any) != 0:
+		for _, operand := range condition.Any {
+			satisfied, err := g.evaluate(operand, result, visiting)
+			if err != nil || satisfied {
+				return satisfied, err
+			}
+		}
+		return false, nil
+	case condition.Not != "":
+		satisfied, err := g.evaluate(condition.Not, result, visiting)
+		return !satisfied && err == nil, err
+	}
+	satisfied, err := Evaluate(condition, result)
+	if err != nil {
+		return false, errors.New("condition " + name + ": " + err.Error())
+	}
+	return satisfied, nil
+}
+
+// Evaluate returns whether the predicate Condition is satisfied by the result
+// Logical groups are evaluated by Group.Evaluate.
+func Evaluate(condition *serverlessv1alpha1.Condition, result interface{}) (bool, error) {
+	path, err := ParsePath(condition.Target)
+	if err != nil {
+		return false, err
+	}
+	raw, found := path.Lookup(result)
+	switch condition.Operator {
+	case serverlessv1alpha1.Exists:
+		return found, nil
+	case serverlessv1alpha1.IsNull:
+		return found && raw == nil, nil
+	}
+	if !found {
+		return false, errors.New("target " + path.String() + " not found in the result")
+	}
+	value, err := Convert(condition.Type, raw)
+	if err != nil {
+		return false, err
+	}
+
+	comparison := string(condition.Comparison)
+	if condition.Operator == serverlessv1alpha1.In {
+		var elements []interface{}
+		if IsReference(comparison) {
+			elements, err = resolveList(condition.Type, comparison, result)
+		} else {
+			elements, err = CoerceList(condition.Type, comparison)
+		}
+		if err != nil {
+			return false, err
+		}
+		for _, element := range elements {
+			if reflect.DeepEqual(value, element) {
+				return true, nil
+			}
+		}
+		return false, nil
+	}
+
+	var expected interface{}
+	if IsReference(comparison) {
+		expected, err = resolve(condition.Type, comparison, result)
+	} else {
+		expected, err = Coerce(condition.Type, comparison)
+	}
+	if err != nil {
+		return false, err
+	}
+
+	switch condition.Operator {
+	case serverlessv1alpha1.Eq:
+		return reflect.DeepEqual(value, expected), nil
+	case serverlessv1alpha1.Ne:
+		return !reflect.DeepEqual(value, expected), nil
+	case serverlessv1alpha1.Lt, serverlessv1alpha1.Le, serverlessv1alpha1.Gt, serverlessv1alpha1.Ge:
+		order, err := compare(value, expected)
+		if err != nil {
+			return false, err
+		}
+		switch condition.Operator {
+		case serverlessv1alpha1.Lt:
+			return order < 0, nil
+		case serverlessv1alpha1.Le:
+			return order <= 0, nil
+		case serverlessv1alpha1.Gt:
+			return order > 0, nil
+		}
+		return order >= 0, nil
+	case serverlessv1alpha1.Contains, serverlessv1alpha1.Matches:
+		s, ok := value.(string)
+		if !ok {
+			return false, errors.New("operator " + string(condition.Operator) + " only accepts string")
+		}
+		if condition.Operator == serverlessv1alpha1.Contains {
+			return strings.Contains(s, expected.(string)), nil
+		}
+		re, err := regexp.Compile(expected.(string))
+		if err != nil {
+			return false, err
+		}
+		return re.MatchString(s), nil
+	}
+	return false, errors.New("operator " + string(condition.Operator) + " is not supported")
+}
+
+// resolve returns the value of the property the Comparison refers to
+func resolve(t serverlessv1alpha1.ConditionType, comparison string, result interface{}) (interface{}, error) {
+	path, _ := ParsePath(comparison)
+	raw, found := path.Lookup(result)
+	if !found {
+		return nil, errors.New("comparison " + comparison + " not found in the result")
+	}
+	return Convert(t, raw)
+}
+
+// resolveList returns the values of the array property the Comparison refers to
+func resolveList(t serverlessv1alpha1.ConditionType, comparison string, result interface{}) ([]interface{}, error) {
+	path, _ := ParsePath(comparison)
+	raw, found := path.Lookup(result)
+	if !found {
+		return nil, errors.New("comparison " + comparison + " not found in the result")
+	}
+	elements, ok := raw.([]interface{})
+	if !ok {
+		return nil, errors.New("comparison " + comparison + " is not an array")
+	}
+	values := make([]interface{}, 0, len(elements))
+	for _, element := range elements {
+		value, err := Convert(t, element)
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, value)
+	}
+	return values, nil
+}
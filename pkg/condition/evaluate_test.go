@@ -0,0 +1,159 @@
+package condition
+
+import (
+	"reflect"
+	"testing"
+
+	serverlessv1alpha1 "github.com/tass-io/tass-operator/api/v1alpha1"
+)
+
+func TestEvaluate(t *testing.T) {
+	result, err := Decode([]byte(
+		`{"$":{"name":"tass","amount":99.9,"count":3,"limit":5,"vip":true,"tags":["a","b"],"owner":null}}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	cases := []struct {
+		name      string
+		condition serverlessv1alpha1.Condition
+		want      bool
+		wantErr   bool
+	}{
+		{name: "string eq", want: true,
+			condition: serverlessv1alpha1.Condition{Type: "string", Operator: "eq", Target: "$.name", Comparison: "tass"}},
+		{name: "string ne", want: false,
+			condition: serverlessv1alpha1.Condition{Type: "string", Operator: "ne", Target: "$.name", Comparison: "tass"}},
+		{name: "int lt", want: true,
+			condition: serverlessv1alpha1.Condition{Type: "int", Operator: "lt", Target: "$.count", Comparison: "5"}},
+		{name: "int le reference", want: true,
+			condition: serverlessv1alpha1.Condition{Type: "int", Operator: "le", Target: "$.count", Comparison: "$.limit"}},
+		{name: "float ge", want: true,
+			condition: serverlessv1alpha1.Condition{Type: "float", Operator: "ge", Target: "$.amount", Comparison: "99.9"}},
+		{name: "float gt", want: false,
+			condition: serverlessv1alpha1.Condition{Type: "float", Operator: "gt", Target: "$.amount", Comparison: "100"}},
+		{name: "bool eq", want: true,
+			condition: serverlessv1alpha1.Condition{Type: "bool", Operator: "eq", Target: "$.vip", Comparison: "true"}},
+		{name: "in literal", want: true,
+			condition: serverlessv1alpha1.Condition{Type: "string", Operator: "in", Target: "$.name",
+				Comparison: `["faas","tass"]`}},
+		{name: "in reference", want: false,
+			condition: serverlessv1alpha1.Condition{Type: "string", Operator: "in", Target: "$.name", Comparison: "$.tags"}},
+		{name: "contains", want: true,
+			condition: serverlessv1alpha1.Condition{Type: "string", Operator: "contains", Target: "$.name", Comparison: "as"}},
+		{name: "matches", want: true,
+			condition: serverlessv1alpha1.Condition{Type: "string", Operator: "matches", Target: "$.name",
+				Comparison: "^t.*s$"}},
+		{name: "exists", want: true,
+			condition: serverlessv1alpha1.Condition{Type: "string", Operator: "exists", Target: "$.owner"}},
+		{name: "not exists", want: false,
+			condition: serverlessv1alpha1.Condition{Type: "string", Operator: "exists", Target: "$.missing"}},
+		{name: "isNull", want: true,
+			condition: serverlessv1alpha1.Condition{Type: "string", Operator: "isNull", Target: "$.owner"}},
+		{name: "missing target", wantErr: true,
+			condition: serverlessv1alpha1.Condition{Type: "string", Operator: "eq", Target: "$.missing", Comparison: "x"}},
+		{name: "type mismatch", wantErr: true,
+			condition: serverlessv1alpha1.Condition{Type: "int", Operator: "eq", Target: "$.name", Comparison: "1"}},
+		{name: "illegal comparison", wantErr: true,
+			condition: serverlessv1alpha1.Condition{Type: "int", Operator: "eq", Target: "$.count", Comparison: "three"}},
+	}
+	for _, c := range cases {
+		got, err := Evaluate(&c.condition, result)
+		if (err != nil) != c.wantErr {
+			t.Errorf("%s: error = %v, wantErr %v", c.name, err, c.wantErr)
+			continue
+		}
+		if got != c.want {
+			t.Errorf("%s: got %v, want %v", c.name, got, c.want)
+		}
+	}
+}
+
+func TestNext(t *testing.T) {
+	flow := &serverlessv1alpha1.Flow{
+		Name:      "check",
+		Statement: serverlessv1alpha1.Switch,
+		Outputs:   []string{"vip", "normal", "audit"},
+		Conditions: []*serverlessv1alpha1.Condition{
+			{
+				Name: "root",
+				All:  []string{"is-vip", "big-order"},
+				Destination: serverlessv1alpha1.Destination{
+					IsTrue:  serverlessv1alpha1.Next{Flows: []string{"vip"}},
+					IsFalse: serverlessv1alpha1.Next{Flows: []string{"normal"}, Conditions: []string{"suspicious"}},
+				},
+			},
+			{Name: "is-vip", Type: "bool", Operator: "eq", Target: "$.vip", Comparison: "true"},
+			{Name: "big-order", Type: "float", Operator: "ge", Target: "$.amount", Comparison: "100"},
+			{
+				Name: "suspicious",
+				Not:  "is-vip",
+				Destination: serverlessv1alpha1.Destination{
+					IsTrue: serverlessv1alpha1.Next{Flows: []string{"audit"}},
+				},
+			},
+		},
+	}
+	cases := []struct {
+		name          string
+		result        string
+		wantFlows     []string
+		wantDecisions []Decision
+		wantErr       bool
+	}{
+		{
+			name:          "vip big order",
+			result:        `{"$":{"vip":true,"amount":200}}`,
+			wantFlows:     []string{"vip"},
+			wantDecisions: []Decision{{Condition: "root", Result: true}},
+		},
+		{
+			name:      "vip small order",
+			result:    `{"$":{"vip":true,"amount":50}}`,
+			wantFlows: []string{"normal"},
+			wantDecisions: []Decision{
+				{Condition: "root", Result: false},
+				{Condition: "suspicious", Result: false},
+			},
+		},
+		{
+			name:      "normal user",
+			result:    `{"$":{"vip":false,"amount":50}}`,
+			wantFlows: []string{"normal", "audit"},
+			wantDecisions: []Decision{
+				{Condition: "root", Result: false},
+				{Condition: "suspicious", Result: true},
+			},
+		},
+		{
+			name:    "missing property",
+			result:  `{"$":{"amount":50}}`,
+			wantErr: true,
+		},
+	}
+	for _, c := range cases {
+		result, err := Decode([]byte(c.result))
+		if err != nil {
+			t.Fatal(err)
+		}
+		flows, decisions, err := Next(flow, result)
+		if (err != nil) != c.wantErr {
+			t.Errorf("%s: error = %v, wantErr %v", c.name, err, c.wantErr)
+			continue
+		}
+		if c.wantErr {
+			continue
+		}
+		if !reflect.DeepEqual(flows, c.wantFlows) {
+			t.Errorf("%s: flows = %v, want %v", c.name, flows, c.wantFlows)
+		}
+		if !reflect.DeepEqual(decisions, c.wantDecisions) {
+			t.Errorf("%s: decisions = %v, want %v", c.name, decisions, c.wantDecisions)
+		}
+	}
+
+	direct := &serverlessv1alpha1.Flow{Name: "d", Statement: serverlessv1alpha1.Direct, Outputs: []string{"a", "b"}}
+	flows, _, err := Next(direct, nil)
+	if err != nil || !reflect.DeepEqual(flows, []string{"a", "b"}) {
+		t.Errorf("direct flow: flows = %v, err = %v", flows, err)
+	}
+}
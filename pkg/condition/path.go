@@ -0,0 +1,69 @@
+package condition
+
+import (
+	"errors"
+	"strings"
+)
+
+// Path is a parsed Condition Target, it's the keys from the root of the Flow result
+// e.g. "$.info.timeout" is parsed to ["info", "timeout"], and "$" is parsed to an empty Path
+type Path []string
+
+// ParsePath parses a Target like "$.info.timeout"
+// An empty target is same as "$", which means the whole result of the user code
+func ParsePath(target string) (Path, error) {
+	if target == "" || target == "$" {
+		return Path{}, nil
+	}
+	if !strings.HasPrefix(target, "$.") {
+		return nil, errors.New("target " + target + " should start with \"$.\"")
+	}
+	keys := strings.Split(target[2:], ".")
+	for _, key := range keys {
+		if key == "" {
+			return nil, errors.New("target " + target + " has an empty key")
+		}
+	}
+	return Path(keys), nil
+}
+
+// IsReference returns true if the Comparison refers to a property of the Flow result, like "$.b"
+func IsReference(comparison string) bool {
+	if !strings.HasPrefix(comparison, "$") {
+		return false
+	}
+	_, err := ParsePath(comparison)
+	return err == nil
+}
+
+// Lookup finds the value of the Path in a decoded Flow result
+// The result is the JSON object wrapped by the Flow runtime, like {"$":{"name":"tass"}}
+// It returns false if the Path doesn't exist in the result.
+func (p Path) Lookup(result interface{}) (interface{}, bool) {
+	wrapper, ok := result.(map[string]interface{})
+	if !ok {
+		return nil, false
+	}
+	value, ok := wrapper["$"]
+	if !ok {
+		return nil, false
+	}
+	for _, key := range p {
+		object, ok := value.(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+		if value, ok = object[key]; !ok {
+			return nil, false
+		}
+	}
+	return value, true
+}
+
+// String returns the Target form of the Path
+func (p Path) String() string {
+	if len(p) == 0 {
+		return "$"
+	}
+	return "$." + strings.Join(p, ".")
+}
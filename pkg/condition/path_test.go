@@ -0,0 +1,62 @@
+package condition
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParsePath(t *testing.T) {
+	cases := []struct {
+		target  string
+		want    Path
+		wantErr bool
+	}{
+		{target: "", want: Path{}},
+		{target: "$", want: Path{}},
+		{target: "$.a", want: Path{"a"}},
+		{target: "$.info.timeout", want: Path{"info", "timeout"}},
+		{target: "a.b", wantErr: true},
+		{target: "$a", wantErr: true},
+		{target: "$.", wantErr: true},
+		{target: "$.a..b", wantErr: true},
+	}
+	for _, c := range cases {
+		got, err := ParsePath(c.target)
+		if (err != nil) != c.wantErr {
+			t.Errorf("ParsePath(%q) error = %v, wantErr %v", c.target, err, c.wantErr)
+			continue
+		}
+		if !c.wantErr && !reflect.DeepEqual(got, c.want) {
+			t.Errorf("ParsePath(%q) = %v, want %v", c.target, got, c.want)
+		}
+	}
+}
+
+func TestPathLookup(t *testing.T) {
+	result, err := Decode([]byte(`{"$":{"name":"tass","info":{"type":"fn","timeout":60,"owner":null}}}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	cases := []struct {
+		target    string
+		want      interface{}
+		wantFound bool
+	}{
+		{target: "$.name", want: "tass", wantFound: true},
+		{target: "$.info.type", want: "fn", wantFound: true},
+		{target: "$.info.owner", want: nil, wantFound: true},
+		{target: "$.info.missing", wantFound: false},
+		{target: "$.name.first", wantFound: false},
+	}
+	for _, c := range cases {
+		path, _ := ParsePath(c.target)
+		got, found := path.Lookup(result)
+		if found != c.wantFound {
+			t.Errorf("Lookup(%q) found = %v, want %v", c.target, found, c.wantFound)
+			continue
+		}
+		if found && !reflect.DeepEqual(got, c.want) {
+			t.Errorf("Lookup(%q) = %v, want %v", c.target, got, c.want)
+		}
+	}
+}
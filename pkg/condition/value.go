@@ -0,0 +1,138 @@
+package condition
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+
+	serverlessv1alpha1 "github.com/tass-io/tass-operator/api/v1alpha1"
+)
+
+// Decode decodes a Flow result
+// Numbers are kept as json.Number so that big integers don't lose precision
+func Decode(data []byte) (interface{}, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var result interface{}
+	if err := decoder.Decode(&result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// Coerce converts the literal Comparison to the Go value of the ConditionType
+// - string: string
+// - int: int64
+// - float: float64
+// - bool: bool
+func Coerce(t serverlessv1alpha1.ConditionType, comparison string) (interface{}, error) {
+	switch t {
+	case serverlessv1alpha1.String:
+		return comparison, nil
+	case serverlessv1alpha1.Int:
+		return strconv.ParseInt(comparison, 10, 64)
+	case serverlessv1alpha1.Float:
+		return strconv.ParseFloat(comparison, 64)
+	case serverlessv1alpha1.Bool:
+		return strconv.ParseBool(comparison)
+	}
+	return nil, fmt.Errorf("type %s is not supported", t)
+}
+
+// Convert converts a decoded JSON value to the Go value of the ConditionType
+// Unlike Coerce, the value must already be of the JSON type matching the ConditionType,
+// a number is accepted as an int only when it is integral.
+func Convert(t serverlessv1alpha1.ConditionType, value interface{}) (interface{}, error) {
+	switch t {
+	case serverlessv1alpha1.String:
+		if s, ok := value.(string); ok {
+			return s, nil
+		}
+	case serverlessv1alpha1.Bool:
+		if b, ok := value.(bool); ok {
+			return b, nil
+		}
+	case serverlessv1alpha1.Int:
+		switch n := value.(type) {
+		case json.Number:
+			if i, err := n.Int64(); err == nil {
+				return i, nil
+			}
+			if f, err := n.Float64(); err == nil && f == math.Trunc(f) {
+				return int64(f), nil
+			}
+		case float64:
+			if n == math.Trunc(n) {
+				return int64(n), nil
+			}
+		}
+	case serverlessv1alpha1.Float:
+		switch n := value.(type) {
+		case json.Number:
+			return n.Float64()
+		case float64:
+			return n, nil
+		}
+	default:
+		return nil, fmt.Errorf("type %s is not supported", t)
+	}
+	return nil, fmt.Errorf("value %v is not %s", value, t)
+}
+
+// CoerceList converts the Comparison of the "in" operator, a JSON array, to the Go values of the ConditionType
+func CoerceList(t serverlessv1alpha1.ConditionType, comparison string) ([]interface{}, error) {
+	decoded, err := Decode([]byte(comparison))
+	if err != nil {
+		return nil, fmt.Errorf("comparison %s is not a JSON array: %v", comparison, err)
+	}
+	elements, ok := decoded.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("comparison %s is not a JSON array", comparison)
+	}
+	values := make([]interface{}, 0, len(elements))
+	for _, element := range elements {
+		value, err := Convert(t, element)
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, value)
+	}
+	return values, nil
+}
+
+// compare returns -1, 0 or 1 when a is less than, equal to or greater than b
+// a and b must be the Go values of the same ConditionType
+func compare(a, b interface{}) (int, error) {
+	switch x := a.(type) {
+	case string:
+		y := b.(string)
+		switch {
+		case x < y:
+			return -1, nil
+		case x > y:
+			return 1, nil
+		}
+		return 0, nil
+	case int64:
+		y := b.(int64)
+		switch {
+		case x < y:
+			return -1, nil
+		case x > y:
+			return 1, nil
+		}
+		return 0, nil
+	case float64:
+		y := b.(float64)
+		switch {
+		case x < y:
+			return -1, nil
+		case x > y:
+			return 1, nil
+		}
+		return 0, nil
+	}
+	return 0, fmt.Errorf("value %v is not ordered", a)
+}
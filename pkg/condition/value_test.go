@@ -0,0 +1,89 @@
+package condition
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	serverlessv1alpha1 "github.com/tass-io/tass-operator/api/v1alpha1"
+)
+
+func TestCoerce(t *testing.T) {
+	cases := []struct {
+		t          serverlessv1alpha1.ConditionType
+		comparison string
+		want       interface{}
+		wantErr    bool
+	}{
+		{t: serverlessv1alpha1.String, comparison: "cash", want: "cash"},
+		{t: serverlessv1alpha1.Int, comparison: "5", want: int64(5)},
+		{t: serverlessv1alpha1.Int, comparison: "5.5", wantErr: true},
+		{t: serverlessv1alpha1.Float, comparison: "5.5", want: 5.5},
+		{t: serverlessv1alpha1.Bool, comparison: "true", want: true},
+		{t: serverlessv1alpha1.Bool, comparison: "yes", wantErr: true},
+		{t: "date", comparison: "2020-01-01", wantErr: true},
+	}
+	for _, c := range cases {
+		got, err := Coerce(c.t, c.comparison)
+		if (err != nil) != c.wantErr {
+			t.Errorf("Coerce(%s, %q) error = %v, wantErr %v", c.t, c.comparison, err, c.wantErr)
+			continue
+		}
+		if !c.wantErr && !reflect.DeepEqual(got, c.want) {
+			t.Errorf("Coerce(%s, %q) = %v, want %v", c.t, c.comparison, got, c.want)
+		}
+	}
+}
+
+func TestConvert(t *testing.T) {
+	cases := []struct {
+		t       serverlessv1alpha1.ConditionType
+		value   interface{}
+		want    interface{}
+		wantErr bool
+	}{
+		{t: serverlessv1alpha1.String, value: "tass", want: "tass"},
+		{t: serverlessv1alpha1.String, value: json.Number("5"), wantErr: true},
+		{t: serverlessv1alpha1.Int, value: json.Number("9007199254740993"), want: int64(9007199254740993)},
+		{t: serverlessv1alpha1.Int, value: json.Number("60.0"), want: int64(60)},
+		{t: serverlessv1alpha1.Int, value: json.Number("60.5"), wantErr: true},
+		{t: serverlessv1alpha1.Float, value: json.Number("60"), want: float64(60)},
+		{t: serverlessv1alpha1.Bool, value: false, want: false},
+		{t: serverlessv1alpha1.Bool, value: "false", wantErr: true},
+	}
+	for _, c := range cases {
+		got, err := Convert(c.t, c.value)
+		if (err != nil) != c.wantErr {
+			t.Errorf("Convert(%s, %v) error = %v, wantErr %v", c.t, c.value, err, c.wantErr)
+			continue
+		}
+		if !c.wantErr && !reflect.DeepEqual(got, c.want) {
+			t.Errorf("Convert(%s, %v) = %v, want %v", c.t, c.value, got, c.want)
+		}
+	}
+}
+
+func TestCoerceList(t *testing.T) {
+	cases := []struct {
+		t          serverlessv1alpha1.ConditionType
+		comparison string
+		want       []interface{}
+		wantErr    bool
+	}{
+		{t: serverlessv1alpha1.String, comparison: `["cash","card"]`, want: []interface{}{"cash", "card"}},
+		{t: serverlessv1alpha1.Int, comparison: `[1, 2]`, want: []interface{}{int64(1), int64(2)}},
+		{t: serverlessv1alpha1.String, comparison: `["cash", 1]`, wantErr: true},
+		{t: serverlessv1alpha1.String, comparison: `cash`, wantErr: true},
+		{t: serverlessv1alpha1.String, comparison: `{"a":"cash"}`, wantErr: true},
+	}
+	for _, c := range cases {
+		got, err := CoerceList(c.t, c.comparison)
+		if (err != nil) != c.wantErr {
+			t.Errorf("CoerceList(%s, %q) error = %v, wantErr %v", c.t, c.comparison, err, c.wantErr)
+			continue
+		}
+		if !c.wantErr && !reflect.DeepEqual(got, c.want) {
+			t.Errorf("CoerceList(%s, %q) = %v, want %v", c.t, c.comparison, got, c.want)
+		}
+	}
+}
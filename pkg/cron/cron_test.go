@@ -0,0 +1,99 @@
+package cron
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		spec    string
+		wantErr bool
+	}{
+		{spec: "* * * * *"},
+		{spec: "*/15 9-17 * * MON-FRI"},
+		{spec: "0 0 1,15 jan-jun/2 ?"},
+		{spec: "5/10 * * * 7"},
+		{spec: "@daily"},
+		{spec: "* * * *", wantErr: true},
+		{spec: "60 * * * *", wantErr: true},
+		{spec: "* 5-1 * * *", wantErr: true},
+		{spec: "*/0 * * * *", wantErr: true},
+		{spec: "* * 0 * *", wantErr: true},
+		{spec: "* * * FOO *", wantErr: true},
+	}
+	for _, tt := range tests {
+		if _, err := Parse(tt.spec); (err != nil) != tt.wantErr {
+			t.Errorf("Parse(%q) error = %v, wantErr %v", tt.spec, err, tt.wantErr)
+		}
+	}
+}
+
+func TestNext(t *testing.T) {
+	amsterdam, err := time.LoadLocation("Europe/Amsterdam")
+	if err != nil {
+		t.Skip("no time zone database")
+	}
+	tests := []struct {
+		spec string
+		from time.Time
+		want time.Time
+	}{
+		{
+			spec: "* * * * *",
+			from: time.Date(2021, 3, 1, 10, 0, 30, 0, time.UTC),
+			want: time.Date(2021, 3, 1, 10, 1, 0, 0, time.UTC),
+		},
+		{
+			spec: "*/15 9-17 * * MON-FRI",
+			from: time.Date(2021, 3, 5, 17, 45, 0, 0, time.UTC), // Friday
+			want: time.Date(2021, 3, 8, 9, 0, 0, 0, time.UTC),
+		},
+		{
+			spec: "5/10 * * * *",
+			from: time.Date(2021, 3, 1, 10, 56, 0, 0, time.UTC),
+			want: time.Date(2021, 3, 1, 11, 5, 0, 0, time.UTC),
+		},
+		{
+			// the day-of-month and day-of-week are ORed when both are restricted
+			spec: "0 0 13 * FRI",
+			from: time.Date(2021, 3, 6, 0, 0, 0, 0, time.UTC),
+			want: time.Date(2021, 3, 12, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			spec: "0 0 29 2 *",
+			from: time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC),
+			want: time.Date(2024, 2, 29, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			spec: "0 0 30 2 *",
+			from: time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC),
+			want: time.Time{},
+		},
+		{
+			spec: "@weekly",
+			from: time.Date(2021, 3, 1, 0, 0, 0, 0, time.UTC),
+			want: time.Date(2021, 3, 7, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			// 02:30 doesn't exist when the daylight saving time starts
+			spec: "30 2 * * *",
+			from: time.Date(2021, 3, 27, 12, 0, 0, 0, amsterdam),
+			want: time.Date(2021, 3, 29, 2, 30, 0, 0, amsterdam),
+		},
+		{
+			spec: "0 9 * * *",
+			from: time.Date(2021, 3, 27, 12, 0, 0, 0, amsterdam),
+			want: time.Date(2021, 3, 28, 9, 0, 0, 0, amsterdam),
+		},
+	}
+	for _, tt := range tests {
+		s, err := Parse(tt.spec)
+		if err != nil {
+			t.Fatalf("Parse(%q) error = %v", tt.spec, err)
+		}
+		if got := s.Next(tt.from); !got.Equal(tt.want) {
+			t.Errorf("Next(%q, %v) = %v, want %v", tt.spec, tt.from, got, tt.want)
+		}
+	}
+}
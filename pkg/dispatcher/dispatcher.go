@@ -0,0 +1,122 @@
+package dispatcher
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/go-logr/logr"
+
+	"github.com/tass-io/tass-operator/pkg/execution"
+)
+
+const (
+	// DefaultMaxAttempts is the number of times a message is tried by default
+	DefaultMaxAttempts = 3
+	// DefaultBackoff is the time waited before the second attempt, it doubles after each attempt
+	DefaultBackoff = time.Second
+)
+
+// DeadLetter is the message published to the dead-letter topic
+type DeadLetter struct {
+	// Topic is the topic the message comes from
+	Topic string `json:"topic"`
+	// ID is the ID of the message in the Source
+	ID string `json:"id"`
+	// Data is the original message, it is base64 encoded in JSON
+	Data []byte `json:"data"`
+	// Attempts is the number of times the Workflow was invoked with the message
+	Attempts int `json:"attempts"`
+	// Error is the error of the last attempt
+	Error string `json:"error"`
+}
+
+// Dispatcher consumes the messages of a Source and invokes a Workflow with them
+type Dispatcher struct {
+	Source  Source
+	Invoker execution.Invoker
+	Log     logr.Logger
+
+	// Namespace and Workflow locate the Service of the Workflow
+	Namespace string
+	Workflow  string
+	// Flow is the Flow the invocations start from
+	Flow string
+	// Topic is the topic the Source consumes, it's recorded in the dead letters
+	Topic string
+	// DeadLetter is the topic the messages failing all attempts go,
+	// if empty the messages are redelivered until they succeed
+	DeadLetter  string
+	MaxAttempts int
+	Backoff     time.Duration
+}
+
+// Run dispatches the messages until the context is done
+func (d *Dispatcher) Run(ctx context.Context) error {
+	if d.MaxAttempts <= 0 {
+		d.MaxAttempts = DefaultMaxAttempts
+	}
+	if d.Backoff <= 0 {
+		d.Backoff = DefaultBackoff
+	}
+	return d.Source.Consume(ctx, d.handle)
+}
+
+// handle invokes the Workflow with the message, a nil error acknowledges the message
+func (d *Dispatcher) handle(ctx context.Context, msg Message) error {
+	log := d.Log.WithValues("message", msg.ID)
+	if !json.Valid(msg.Data) {
+		// retrying a malformed message never succeeds
+		return d.deadLetter(ctx, msg, 0, errors.New("message is not a legal JSON"))
+	}
+
+	backoff := d.Backoff
+	var err error
+	for attempt := 1; attempt <= d.MaxAttempts; attempt++ {
+		_, err = d.Invoker.Invoke(ctx, d.Namespace, execution.Request{
+			WorkflowName: d.Workflow,
+			FlowName:     d.Flow,
+			Parameters:   json.RawMessage(msg.Data),
+		})
+		if err == nil {
+			log.V(1).Info("message dispatched", "attempt", attempt)
+			return nil
+		}
+		log.Info("invocation failed", "attempt", attempt, "error", err.Error())
+		if attempt == d.MaxAttempts {
+			break
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+	}
+	return d.deadLetter(ctx, msg, d.MaxAttempts, err)
+}
+
+// deadLetter publishes the failed message to the dead-letter topic
+// It returns the error when there is no dead-letter topic, so that the message is redelivered.
+func (d *Dispatcher) deadLetter(ctx context.Context, msg Message, attempts int, cause error) error {
+	if d.DeadLetter == "" {
+		return cause
+	}
+	data, err := json.Marshal(DeadLetter{
+		Topic:    d.Topic,
+		ID:       msg.ID,
+		Data:     msg.Data,
+		Attempts: attempts,
+		Error:    cause.Error(),
+	})
+	if err != nil {
+		return err
+	}
+	if err := d.Source.Publish(ctx, d.DeadLetter, data); err != nil {
+		d.Log.Error(err, "unable to publish the dead letter", "message", msg.ID)
+		return err
+	}
+	d.Log.Info("message sent to the dead-letter topic", "message", msg.ID, "error", cause.Error())
+	return nil
+}
@@ -0,0 +1,141 @@
+package dispatcher
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/tass-io/tass-operator/pkg/execution"
+)
+
+// fakeInvoker fails the first failures invocations
+type fakeInvoker struct {
+	mu       sync.Mutex
+	failures int
+	requests []execution.Request
+}
+
+func (f *fakeInvoker) Invoke(ctx context.Context, namespace string, req execution.Request) (string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.requests = append(f.requests, req)
+	if len(f.requests) <= f.failures {
+		return "", errors.New("503 Service Unavailable")
+	}
+	return "{}", nil
+}
+
+func (f *fakeInvoker) count() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return len(f.requests)
+}
+
+// dispatch runs a Dispatcher on the topic "orders" until the condition holds
+func dispatch(t *testing.T, broker *Broker, invoker *fakeInvoker, deadLetter string, until func() bool) {
+	ctx, cancel := context.WithCancel(context.Background())
+	d := &Dispatcher{
+		Source:      broker.Source("orders", "workflow"),
+		Invoker:     invoker,
+		Log:         logf.Log,
+		Namespace:   "default",
+		Workflow:    "order",
+		Topic:       "orders",
+		DeadLetter:  deadLetter,
+		MaxAttempts: 3,
+		Backoff:     time.Millisecond,
+	}
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		if err := d.Run(ctx); err != nil {
+			t.Errorf("Run() error = %v", err)
+		}
+	}()
+	deadline := time.Now().Add(5 * time.Second)
+	for !until() {
+		if time.Now().After(deadline) {
+			t.Fatal("timed out")
+		}
+		time.Sleep(time.Millisecond)
+	}
+	cancel()
+	<-done
+}
+
+func TestDispatcherDelivers(t *testing.T) {
+	broker := NewBroker()
+	broker.Publish("orders", []byte(`{"id": 1}`))
+	broker.Publish("orders", []byte(`{"id": 2}`))
+	invoker := &fakeInvoker{failures: 1}
+
+	dispatch(t, broker, invoker, "orders-dead", func() bool { return invoker.count() == 3 })
+	if got := string(invoker.requests[1].Parameters); got != `{"id": 1}` {
+		t.Errorf("the retried request = %s, want the first message", got)
+	}
+	if got := invoker.requests[2].WorkflowName; got != "order" {
+		t.Errorf("WorkflowName = %s, want order", got)
+	}
+	if dead := broker.Messages("orders-dead"); len(dead) != 0 {
+		t.Errorf("dead letters = %d, want 0", len(dead))
+	}
+}
+
+func TestDispatcherDeadLetter(t *testing.T) {
+	broker := NewBroker()
+	broker.Publish("orders", []byte(`not json`))
+	broker.Publish("orders", []byte(`{"id": 1}`))
+	broker.Publish("orders", []byte(`{"id": 2}`))
+	invoker := &fakeInvoker{failures: 3}
+
+	dispatch(t, broker, invoker, "orders-dead", func() bool { return invoker.count() == 4 })
+	dead := broker.Messages("orders-dead")
+	if len(dead) != 2 {
+		t.Fatalf("dead letters = %d, want 2", len(dead))
+	}
+	var letters []DeadLetter
+	for _, data := range dead {
+		var letter DeadLetter
+		if err := json.Unmarshal(data, &letter); err != nil {
+			t.Fatal(err)
+		}
+		letters = append(letters, letter)
+	}
+	if letters[0].Attempts != 0 || string(letters[0].Data) != "not json" {
+		t.Errorf("dead letter of the malformed message = %+v", letters[0])
+	}
+	if letters[1].Attempts != 3 || letters[1].ID != "orders/1" || letters[1].Topic != "orders" {
+		t.Errorf("dead letter of the failing message = %+v", letters[1])
+	}
+}
+
+func TestDispatcherRedelivers(t *testing.T) {
+	broker := NewBroker()
+	broker.Publish("orders", []byte(`{"id": 1}`))
+	// without a dead-letter topic, the message is redelivered until it succeeds
+	invoker := &fakeInvoker{failures: 7}
+
+	dispatch(t, broker, invoker, "", func() bool { return invoker.count() == 8 })
+	for _, req := range invoker.requests {
+		if string(req.Parameters) != `{"id": 1}` {
+			t.Errorf("request = %s, want the first message", req.Parameters)
+		}
+	}
+}
+
+func TestNew(t *testing.T) {
+	if _, err := New(Config{Type: "nats", Topic: "orders"}); err == nil {
+		t.Errorf("New(nats) error = nil, want not registered")
+	}
+	if _, err := New(Config{Type: MemorySourceType}); err == nil {
+		t.Errorf("New() without topic error = nil")
+	}
+	if _, err := New(Config{Type: MemorySourceType, Topic: "orders"}); err != nil {
+		t.Errorf("New(memory) error = %v", err)
+	}
+}
@@ -0,0 +1,124 @@
+package dispatcher
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// MemorySourceType is the type of the in-process Source
+const MemorySourceType = "memory"
+
+// redeliveryDelay is the time the Broker waits before delivering a failed message again
+const redeliveryDelay = 10 * time.Millisecond
+
+// Broker is an in-process message queue standing in for NATS in tests and local runs
+// Each topic is a log of messages, and each consumer group has its own offset in the log like Kafka.
+// A message is delivered again until the Handler of the group returns nil.
+type Broker struct {
+	mu      sync.Mutex
+	topics  map[string][][]byte
+	offsets map[string]int
+	// changed is closed and replaced when a message is published
+	changed chan struct{}
+}
+
+// NewBroker returns an empty Broker
+func NewBroker() *Broker {
+	return &Broker{
+		topics:  map[string][][]byte{},
+		offsets: map[string]int{},
+		changed: make(chan struct{}),
+	}
+}
+
+var defaultBroker = NewBroker()
+
+func init() {
+	// the Sources of the type "memory" share a Broker in the process
+	Register(MemorySourceType, func(config Config) (Source, error) {
+		return defaultBroker.Source(config.Topic, config.Group), nil
+	})
+}
+
+// Publish appends a message to the topic
+func (b *Broker) Publish(topic string, data []byte) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.topics[topic] = append(b.topics[topic], data)
+	close(b.changed)
+	b.changed = make(chan struct{})
+}
+
+// Messages returns the messages published to the topic
+func (b *Broker) Messages(topic string) [][]byte {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return append([][]byte(nil), b.topics[topic]...)
+}
+
+// Source returns a Source consuming the topic as the consumer group
+func (b *Broker) Source(topic, group string) Source {
+	return &memorySource{broker: b, topic: topic, group: group}
+}
+
+// next returns the message at the offset of the group, or a channel closed when a message comes
+func (b *Broker) next(topic, group string) (Message, bool, <-chan struct{}) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	offset := b.offsets[topic+"/"+group]
+	if offset >= len(b.topics[topic]) {
+		return Message{}, false, b.changed
+	}
+	return Message{
+		ID:   topic + "/" + strconv.Itoa(offset),
+		Data: b.topics[topic][offset],
+	}, true, nil
+}
+
+// commit moves the offset of the group forward
+func (b *Broker) commit(topic, group string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.offsets[topic+"/"+group]++
+}
+
+// memorySource is a Source of a Broker, the consumers of the same group handle the messages one by one
+type memorySource struct {
+	broker *Broker
+	topic  string
+	group  string
+}
+
+func (s *memorySource) Consume(ctx context.Context, handle Handler) error {
+	for {
+		msg, ok, changed := s.broker.next(s.topic, s.group)
+		if !ok {
+			select {
+			case <-ctx.Done():
+				return nil
+			case <-changed:
+				continue
+			}
+		}
+		if err := handle(ctx, msg); err != nil {
+			select {
+			case <-ctx.Done():
+				return nil
+			case <-time.After(redeliveryDelay):
+				continue
+			}
+		}
+		s.broker.commit(s.topic, s.group)
+	}
+}
+
+func (s *memorySource) Publish(ctx context.Context, topic string, data []byte) error {
+	s.broker.Publish(topic, data)
+	return nil
+}
+
+func (s *memorySource) Close() error {
+	return nil
+}
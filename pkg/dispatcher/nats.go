@@ -0,0 +1,119 @@
+package dispatcher
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/nats-io/nats.go"
+)
+
+// NATSSourceType is the type of the Source on NATS JetStream
+const NATSSourceType = "nats"
+
+const (
+	// natsFetchWait is how long a pull waits for a message, the context is checked between the pulls
+	natsFetchWait = time.Second
+	// natsAckWait is how long JetStream waits for the ack before delivering the message again,
+	// it covers the dispatchers crashing without a chance to nak the message
+	natsAckWait = time.Minute
+)
+
+// durableReplacer replaces the characters a durable name of JetStream should not have
+var durableReplacer = strings.NewReplacer(".", "_", "*", "_", ">", "_", " ", "_")
+
+func init() {
+	Register(NATSSourceType, NewNATSSource)
+}
+
+// natsSource is a Source consuming a subject of a JetStream stream with a durable pull consumer
+// The dispatchers of the same group share the durable consumer, so each message goes to one of them.
+// A message is acked after the Handler returns nil, and nacked to be delivered again right away
+// when the Handler fails or the dispatcher exits. If the dispatcher crashes, JetStream delivers
+// the message again after natsAckWait.
+type natsSource struct {
+	conn *nats.Conn
+	js   nats.JetStreamContext
+	sub  *nats.Subscription
+}
+
+// NewNATSSource connects to the NATS servers and creates the durable consumer of the group on the Topic
+// The Topic and the dead-letter topic should be subjects of JetStream streams,
+// the streams are managed by the administrators of NATS.
+func NewNATSSource(config Config) (Source, error) {
+	if len(config.Servers) == 0 {
+		return nil, errors.New("nats source requires the servers")
+	}
+	if config.Group == "" {
+		return nil, errors.New("nats source requires the group as the durable consumer")
+	}
+	conn, err := nats.Connect(strings.Join(config.Servers, ","), nats.Name("tass-dispatcher"))
+	if err != nil {
+		return nil, err
+	}
+	js, err := conn.JetStream()
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+	sub, err := js.PullSubscribe(config.Topic, durableReplacer.Replace(config.Group),
+		nats.AckExplicit(), nats.AckWait(natsAckWait))
+	if err != nil {
+		conn.Close()
+		return nil, errors.New("unable to consume " + config.Topic + ": " + err.Error())
+	}
+	return &natsSource{conn: conn, js: js, sub: sub}, nil
+}
+
+func (s *natsSource) Consume(ctx context.Context, handle Handler) error {
+	for {
+		fetchCtx, cancel := context.WithTimeout(ctx, natsFetchWait)
+		msgs, err := s.sub.Fetch(1, nats.Context(fetchCtx))
+		cancel()
+		if ctx.Err() != nil {
+			return nil
+		}
+		if err == nats.ErrTimeout || err == context.DeadlineExceeded {
+			continue
+		} else if err != nil {
+			return err
+		}
+		for _, msg := range msgs {
+			if err := s.handle(ctx, handle, msg); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// handle hands the message to the Handler, and acks it only if the Handler succeeds
+func (s *natsSource) handle(ctx context.Context, handle Handler, msg *nats.Msg) error {
+	if err := handle(ctx, Message{ID: natsMessageID(msg), Data: msg.Data}); err != nil {
+		// the message is delivered again to the group right away
+		return msg.Nak()
+	}
+	// a lost ack only leads to a duplicate delivery
+	return msg.AckSync()
+}
+
+// natsMessageID returns "<stream>/<stream sequence>" of the message
+func natsMessageID(msg *nats.Msg) string {
+	meta, err := msg.Metadata()
+	if err != nil {
+		return msg.Subject
+	}
+	return meta.Stream + "/" + strconv.FormatUint(meta.Sequence.Stream, 10)
+}
+
+// Publish publishes the message to the stream of the topic, it returns after JetStream stores it
+func (s *natsSource) Publish(ctx context.Context, topic string, data []byte) error {
+	_, err := s.js.Publish(topic, data, nats.Context(ctx))
+	return err
+}
+
+func (s *natsSource) Close() error {
+	s.conn.Close()
+	return nil
+}
@@ -0,0 +1,182 @@
+package dispatcher
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/nats-io/nats-server/v2/server"
+	"github.com/nats-io/nats.go"
+)
+
+// runNATS runs a NATS server with JetStream and the stream "ORDERS" of the subjects "orders" and "orders-dead"
+func runNATS(t *testing.T) (string, nats.JetStreamContext) {
+	t.Helper()
+	s, err := server.NewServer(&server.Options{
+		Host:      "127.0.0.1",
+		Port:      -1,
+		JetStream: true,
+		StoreDir:  t.TempDir(),
+		NoLog:     true,
+		NoSigs:    true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	go s.Start()
+	if !s.ReadyForConnections(5 * time.Second) {
+		t.Fatal("nats server is not ready")
+	}
+	t.Cleanup(s.Shutdown)
+
+	conn, err := nats.Connect(s.ClientURL())
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(conn.Close)
+	js, err := conn.JetStream()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := js.AddStream(&nats.StreamConfig{Name: "ORDERS", Subjects: []string{"orders", "orders-dead"}}); err != nil {
+		t.Fatal(err)
+	}
+	return s.ClientURL(), js
+}
+
+// consume runs the Source until the Handler has been called n times, and returns the IDs it got
+func consume(t *testing.T, source Source, n int, handle Handler) []string {
+	t.Helper()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	var mu sync.Mutex
+	var ids []string
+	called := make(chan struct{}, n)
+	done := make(chan error, 1)
+	go func() {
+		done <- source.Consume(ctx, func(ctx context.Context, msg Message) error {
+			mu.Lock()
+			ids = append(ids, msg.ID)
+			mu.Unlock()
+			called <- struct{}{}
+			return handle(ctx, msg)
+		})
+	}()
+	for i := 0; i < n; i++ {
+		select {
+		case <-called:
+		case <-time.After(5 * time.Second):
+			t.Fatalf("the Handler was called %d times, want %d", i, n)
+		}
+	}
+	cancel()
+	if err := <-done; err != nil {
+		t.Errorf("Consume() error = %v", err)
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	return ids
+}
+
+func newNATSSource(t *testing.T, url string) Source {
+	t.Helper()
+	source, err := New(Config{Type: NATSSourceType, Servers: []string{url}, Topic: "orders", Group: "default.order"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = source.Close() })
+	return source
+}
+
+func TestNATSSourceRedeliversFailures(t *testing.T) {
+	url, js := runNATS(t)
+	for _, data := range []string{`{"id": 1}`, `{"id": 2}`} {
+		if _, err := js.Publish("orders", []byte(data)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	failed := false
+	ids := consume(t, newNATSSource(t, url), 3, func(ctx context.Context, msg Message) error {
+		if !failed {
+			failed = true
+			return errors.New("503 Service Unavailable")
+		}
+		return nil
+	})
+	want := []string{"ORDERS/1", "ORDERS/1", "ORDERS/2"}
+	if len(ids) != len(want) {
+		t.Fatalf("delivered %v, want %v", ids, want)
+	}
+	for i := range want {
+		if ids[i] != want[i] {
+			t.Errorf("delivered %v, want %v", ids, want)
+			break
+		}
+	}
+
+	// the group shares a durable consumer, the dots of the group are replaced
+	info, err := js.ConsumerInfo("ORDERS", "default_order")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.NumAckPending != 0 || info.NumPending != 0 {
+		t.Errorf("consumer has %d messages unacked and %d pending, want all acked",
+			info.NumAckPending, info.NumPending)
+	}
+}
+
+func TestNATSSourceRedeliversOnExit(t *testing.T) {
+	url, js := runNATS(t)
+	if _, err := js.Publish("orders", []byte(`{"id": 1}`)); err != nil {
+		t.Fatal(err)
+	}
+
+	// the dispatcher exits while the Handler is running
+	ids := consume(t, newNATSSource(t, url), 1, func(ctx context.Context, msg Message) error {
+		<-ctx.Done()
+		return ctx.Err()
+	})
+	if len(ids) != 1 || ids[0] != "ORDERS/1" {
+		t.Fatalf("delivered %v, want [ORDERS/1]", ids)
+	}
+
+	// another dispatcher of the group gets the message without waiting for the ack timeout
+	ids = consume(t, newNATSSource(t, url), 1, func(ctx context.Context, msg Message) error {
+		return nil
+	})
+	if len(ids) != 1 || ids[0] != "ORDERS/1" {
+		t.Errorf("redelivered %v, want [ORDERS/1]", ids)
+	}
+}
+
+func TestNATSSourcePublish(t *testing.T) {
+	url, js := runNATS(t)
+	source := newNATSSource(t, url)
+	if err := source.Publish(context.Background(), "orders-dead", []byte(`{"id": 1}`)); err != nil {
+		t.Fatal(err)
+	}
+	info, err := js.StreamInfo("ORDERS")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.State.Msgs != 1 {
+		t.Errorf("stream has %d messages, want 1", info.State.Msgs)
+	}
+
+	if err := source.Publish(context.Background(), "unknown", []byte(`{}`)); err == nil {
+		t.Error("Publish() to a subject without stream error = nil, want an error")
+	}
+}
+
+func TestNewNATSSource(t *testing.T) {
+	url, _ := runNATS(t)
+	if _, err := New(Config{Type: NATSSourceType, Servers: []string{url}, Topic: "orders"}); err == nil {
+		t.Error("New() without group error = nil, want an error")
+	}
+	if _, err := New(Config{Type: NATSSourceType, Servers: []string{url}, Topic: "payments", Group: "g"}); err == nil {
+		t.Error("New() on a subject without stream error = nil, want an error")
+	}
+}
@@ -0,0 +1,68 @@
+package dispatcher
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+// Message is a message consumed from a Source
+type Message struct {
+	// ID identifies the message in the Source, e.g. "ORDERS/42" for a sequence of a JetStream stream
+	ID   string
+	Data []byte
+}
+
+// Handler handles a message, the message is acknowledged only when it returns nil
+type Handler func(ctx context.Context, msg Message) error
+
+// Source is a topic of a message queue
+// The implementations should deliver a message again when the Handler fails,
+// or when the dispatcher exits before the Handler returns, so that no message is lost.
+type Source interface {
+	// Consume delivers the messages to the Handler until the context is done
+	Consume(ctx context.Context, handle Handler) error
+	// Publish publishes a message to another topic of the same message queue
+	Publish(ctx context.Context, topic string, data []byte) error
+	// Close releases the connections to the message queue
+	Close() error
+}
+
+// Config is the config of a Source
+type Config struct {
+	// Type is the type of the message queue, e.g. "nats"
+	Type    string
+	Servers []string
+	Topic   string
+	Group   string
+}
+
+// Factory creates a Source from the Config
+type Factory func(config Config) (Source, error)
+
+var (
+	factoriesMu sync.RWMutex
+	factories   = map[string]Factory{}
+)
+
+// Register makes a type of Source available to New
+// The adapters of the message queues register themselves in their init functions.
+func Register(sourceType string, factory Factory) {
+	factoriesMu.Lock()
+	defer factoriesMu.Unlock()
+	factories[sourceType] = factory
+}
+
+// New creates a Source of the type in the Config
+func New(config Config) (Source, error) {
+	factoriesMu.RLock()
+	factory, ok := factories[config.Type]
+	factoriesMu.RUnlock()
+	if !ok {
+		return nil, errors.New("source type " + config.Type + " is not registered in this build")
+	}
+	if config.Topic == "" {
+		return nil, errors.New("topic should not be empty")
+	}
+	return factory(config)
+}
@@ -3,31 +3,43 @@ package endpointslice
 import (
 	"context"
 	"encoding/json"
+	"sort"
 	"strings"
 
 	"github.com/go-logr/logr"
 	serverlessv1alpha1 "github.com/tass-io/tass-operator/api/v1alpha1"
+	"github.com/tass-io/tass-operator/pkg/metrics"
+	"github.com/tass-io/tass-operator/pkg/tracing"
 	"github.com/tass-io/tass-operator/pkg/utils/jsonpatch"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	corev1 "k8s.io/api/core/v1"
 	discoveryv1beta1 "k8s.io/api/discovery/v1beta1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
 type Reconciler struct {
+	// ctx carries the span of the reconcile the API calls are traced in
+	ctx      context.Context
 	cli      client.Client
 	log      logr.Logger
 	scheme   *runtime.Scheme
+	recorder record.EventRecorder
 	instance *discoveryv1beta1.EndpointSlice
 }
 
-func NewReconciler(cli client.Client, l logr.Logger,
-	s *runtime.Scheme, i *discoveryv1beta1.EndpointSlice) (*Reconciler, error) {
+func NewReconciler(ctx context.Context, cli client.Client, l logr.Logger,
+	s *runtime.Scheme, rec record.EventRecorder, i *discoveryv1beta1.EndpointSlice) (*Reconciler, error) {
 	return &Reconciler{
+		ctx:      ctx,
 		cli:      cli,
 		log:      l,
 		scheme:   s,
+		recorder: rec,
 		instance: i,
 	}, nil
 }
@@ -37,8 +49,8 @@ func NewReconciler(cli client.Client, l logr.Logger,
 // 2. Get the corresponding WorkflowRuntime instance
 // 3. Check the change of the endpoint address, add the new ip and remove the deprecated with nil
 // 4. Marshal to patch bytes and patch the result
-func (r Reconciler) Reconcile() error {
-	ctx := context.Background()
+func (r Reconciler) Reconcile() (err error) {
+	ctx := r.ctx
 	log := r.log.WithValues("endpointslice", types.NamespacedName{
 		Namespace: r.instance.Namespace,
 		Name:      r.instance.Name,
@@ -65,11 +77,17 @@ func (r Reconciler) Reconcile() error {
 	if err := r.cli.Get(ctx, wfrtNamespacedName, &wfrt); err != nil {
 		return err
 	}
+	// the reconcile continues the trace of the WorkflowRuntime, so that the instances patched are
+	// followed from the Workflow creating it
+	ctx, span := tracing.Start(tracing.Extract(ctx, &wfrt), "EndpointSlice.Reconcile", trace.WithAttributes(
+		attribute.String("k8s.namespace", r.instance.Namespace), attribute.String("k8s.name", r.instance.Name)))
+	defer func() { tracing.End(span, err) }()
 
 	// 3. Check the change of the endpoint address, add the new ip and remove the deprecated with nil
 	//
 	log.Info("check the change of the endpoint address")
 	jsonPatchItems := []jsonpatch.Item{}
+	var added, removed []string
 	// 3.1 check the existed info of WorkflowRuntime resource instance
 	//
 	for name := range wfrt.Spec.Status.Instances {
@@ -92,6 +110,7 @@ func (r Reconciler) Reconcile() error {
 				Path: jsonpatch.SetPath(false, "spec", "status", "instances", name),
 			}
 			jsonPatchItems = append(jsonPatchItems, newItem)
+			removed = append(removed, name)
 		}
 	}
 	// 3.2 the rest currentSvcMesh objects are new elements
@@ -105,6 +124,7 @@ func (r Reconciler) Reconcile() error {
 			},
 		}
 		jsonPatchItems = append(jsonPatchItems, newItem)
+		added = append(added, name)
 	}
 
 	// 4. Marshal to patch bytes and patch the result
@@ -116,13 +136,37 @@ func (r Reconciler) Reconcile() error {
 			Name:      wfrtNamespacedName.Name,
 		},
 	}, client.RawPatch(types.JSONPatchType, patchBytes)); err != nil {
+		countOperations(jsonPatchItems, "error")
+		r.recorder.Event(&wfrt, corev1.EventTypeWarning, "PatchFailed",
+			"cannot patch the instances from EndpointSlice "+r.instance.Name+": "+err.Error())
 		return err
 	}
+	countOperations(jsonPatchItems, "success")
+	if len(added) > 0 {
+		sort.Strings(added)
+		r.recorder.Event(&wfrt, corev1.EventTypeNormal, "InstancesAdded", "added instances "+strings.Join(added, ", "))
+	}
+	if len(removed) > 0 {
+		sort.Strings(removed)
+		r.recorder.Event(&wfrt, corev1.EventTypeNormal, "InstancesRemoved",
+			"removed instances "+strings.Join(removed, ", "))
+	}
 	log.Info("update the Workflow runtime " + wfrtNamespacedName.String() + " successfully")
 
 	return nil
 }
 
+// countOperations counts the patch operations by op type with the result of the patch
+func countOperations(items []jsonpatch.Item, result string) {
+	ops := map[jsonpatch.Operation]int{}
+	for _, item := range items {
+		ops[item.Op]++
+	}
+	for op, n := range ops {
+		metrics.PatchApplied(string(op), result, n)
+	}
+}
+
 // getPodSelfName returns the pod slef name from endpointslice item TargetRef
 // e.g. (workflow-sample-9657bf88d-btxwt) => (9657bf88d-btxwt)
 func getPodSelfName(name string) string {
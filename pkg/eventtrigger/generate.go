@@ -0,0 +1,123 @@
+package eventtrigger
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	serverlessv1alpha1 "github.com/tass-io/tass-operator/api/v1alpha1"
+	"github.com/tass-io/tass-operator/pkg/dispatcher"
+)
+
+type generator struct {
+	trigger *serverlessv1alpha1.EventTrigger
+	image   string
+	labels  map[string]string
+}
+
+func newGenerator(t *serverlessv1alpha1.EventTrigger, image string) (*generator, error) {
+	if t == nil {
+		return nil, fmt.Errorf("got nil when initializing Generator")
+	}
+	return &generator{
+		trigger: t,
+		image:   image,
+		labels: map[string]string{
+			"type": "eventTrigger",
+			"name": t.Name,
+		},
+	}, nil
+}
+
+// deploymentName returns the name of the dispatcher Deployment of the EventTrigger
+func deploymentName(t *serverlessv1alpha1.EventTrigger) string {
+	return t.Name + "-dispatcher"
+}
+
+// desiredDeployment returns the dispatcher Deployment of the EventTrigger
+func (g generator) desiredDeployment() *appsv1.Deployment {
+	spec := g.trigger.Spec
+	replicas := int32(1)
+	if spec.Replicas != nil {
+		replicas = *spec.Replicas
+	}
+	maxAttempts := dispatcher.DefaultMaxAttempts
+	if spec.MaxAttempts != nil {
+		maxAttempts = int(*spec.MaxAttempts)
+	}
+	group := spec.Source.Group
+	if group == "" {
+		group = g.trigger.Namespace + "." + g.trigger.Name
+	}
+	args := []string{
+		"-source", string(spec.Source.Type),
+		"-servers", strings.Join(spec.Source.Servers, ","),
+		"-topic", spec.Source.Topic,
+		"-group", group,
+		"-workflow", spec.Workflow,
+		"-max-attempts", strconv.Itoa(maxAttempts),
+	}
+	if spec.Flow != "" {
+		args = append(args, "-flow", spec.Flow)
+	}
+	if spec.DeadLetter != "" {
+		args = append(args, "-dead-letter", spec.DeadLetter)
+	}
+
+	return &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: g.trigger.Namespace,
+			Name:      deploymentName(g.trigger),
+			Labels:    g.labels,
+		},
+		Spec: appsv1.DeploymentSpec{
+			Selector: &metav1.LabelSelector{
+				MatchLabels: g.labels,
+			},
+			Replicas: &replicas,
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: g.labels,
+				},
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{
+						{
+							Name:  "dispatcher",
+							Image: g.image,
+							Args:  args,
+							Env: []corev1.EnvVar{{
+								Name: "POD_NAMESPACE",
+								ValueFrom: &corev1.EnvVarSource{
+									FieldRef: &corev1.ObjectFieldSelector{FieldPath: "metadata.namespace"},
+								},
+							}},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// syncDeployment copies the fields derived from the EventTrigger to an existing Deployment
+// It returns true if the Deployment has been changed and needs an update
+func (g generator) syncDeployment(deploy *appsv1.Deployment) bool {
+	desired := g.desiredDeployment()
+	changed := false
+	if !equality.Semantic.DeepEqual(deploy.Spec.Replicas, desired.Spec.Replicas) {
+		deploy.Spec.Replicas = desired.Spec.Replicas
+		changed = true
+	}
+	actual := &deploy.Spec.Template.Spec.Containers
+	if len(*actual) != 1 || (*actual)[0].Image != g.image ||
+		!equality.Semantic.DeepEqual((*actual)[0].Args, desired.Spec.Template.Spec.Containers[0].Args) {
+		*actual = desired.Spec.Template.Spec.Containers
+		changed = true
+	}
+	return changed
+}
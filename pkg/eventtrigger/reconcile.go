@@ -0,0 +1,79 @@
+package eventtrigger
+
+import (
+	"context"
+
+	"github.com/go-logr/logr"
+	appsv1 "k8s.io/api/apps/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	serverlessv1alpha1 "github.com/tass-io/tass-operator/api/v1alpha1"
+)
+
+type Reconciler struct {
+	cli      client.Client
+	log      logr.Logger
+	scheme   *runtime.Scheme
+	instance *serverlessv1alpha1.EventTrigger
+	gen      *generator
+}
+
+func NewReconciler(cli client.Client, l logr.Logger, s *runtime.Scheme,
+	i *serverlessv1alpha1.EventTrigger, image string) (*Reconciler, error) {
+	g, err := newGenerator(i, image)
+	if err != nil {
+		return nil, err
+	}
+	return &Reconciler{
+		cli:      cli,
+		log:      l,
+		scheme:   s,
+		instance: i,
+		gen:      g,
+	}, nil
+}
+
+// Reconcile creates or updates the dispatcher Deployment of the EventTrigger,
+// and reports the ready dispatchers in the status of the instance, the caller updates it.
+func (r *Reconciler) Reconcile() error {
+	ctx := context.Background()
+	namespacedName := types.NamespacedName{
+		Namespace: r.instance.Namespace,
+		Name:      deploymentName(r.instance),
+	}
+	log := r.log.WithValues("deployment", namespacedName)
+
+	desired := r.gen.desiredDeployment()
+	if err := ctrl.SetControllerReference(r.instance, desired, r.scheme); err != nil {
+		return err
+	}
+	actual := &appsv1.Deployment{}
+	if err := r.cli.Get(ctx, namespacedName, actual); errors.IsNotFound(err) {
+		if err := r.cli.Create(ctx, desired); err != nil {
+			log.Error(err, "failed to create the dispatcher deployment")
+			return err
+		}
+		log.Info("dispatcher Deployment created successfully")
+		r.instance.Status.ReadyReplicas = 0
+		r.instance.Status.ObservedGeneration = r.instance.Generation
+		return nil
+	} else if err != nil {
+		log.Error(err, "failed to get the dispatcher deployment")
+		return err
+	}
+
+	if r.gen.syncDeployment(actual) {
+		if err := r.cli.Update(ctx, actual); err != nil {
+			log.Error(err, "failed to update the dispatcher deployment")
+			return err
+		}
+		log.Info("dispatcher Deployment updated successfully")
+	}
+	r.instance.Status.ReadyReplicas = actual.Status.ReadyReplicas
+	r.instance.Status.ObservedGeneration = r.instance.Generation
+	return nil
+}
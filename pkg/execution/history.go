@@ -0,0 +1,88 @@
+package execution
+
+import (
+	"sort"
+	"time"
+
+	serverlessv1alpha1 "github.com/tass-io/tass-operator/api/v1alpha1"
+)
+
+const (
+	// WorkflowLabel is the label of a WorkflowExecution or a WorkflowRevision holding the name of its Workflow
+	WorkflowLabel = "workflow"
+	// CronTriggerLabel is the label of a WorkflowExecution holding the name of the CronTrigger creating it
+	CronTriggerLabel = "crontrigger"
+	// DefaultSucceededLimit is the number of succeeded WorkflowExecutions kept by default
+	DefaultSucceededLimit = 10
+	// DefaultFailedLimit is the number of failed WorkflowExecutions kept by default
+	DefaultFailedLimit = 10
+)
+
+// Finished returns whether the WorkflowExecution has succeeded or failed
+func Finished(e *serverlessv1alpha1.WorkflowExecution) bool {
+	return e.Status.Phase == serverlessv1alpha1.ExecutionSucceeded ||
+		e.Status.Phase == serverlessv1alpha1.ExecutionFailed
+}
+
+// finishedAt returns the time the WorkflowExecution finished,
+// a WorkflowExecution reported without CompletionTime is taken as finished when it was created
+func finishedAt(e *serverlessv1alpha1.WorkflowExecution) time.Time {
+	if e.Status.CompletionTime != nil {
+		return e.Status.CompletionTime.Time
+	}
+	return e.CreationTimestamp.Time
+}
+
+// Expire returns whether the finished WorkflowExecution has outlived the TTL,
+// and how long it has left otherwise. A zero duration means it never expires.
+func Expire(e *serverlessv1alpha1.WorkflowExecution, history *serverlessv1alpha1.ExecutionHistory,
+	now time.Time) (bool, time.Duration) {
+	if !Finished(e) || history == nil || history.TTL == nil {
+		return false, 0
+	}
+	left := finishedAt(e).Add(history.TTL.Duration).Sub(now)
+	if left <= 0 {
+		return true, 0
+	}
+	return false, left
+}
+
+// Overflow returns the finished WorkflowExecutions beyond the history limits, the oldest ones first
+// The WorkflowExecutions should belong to the same Workflow.
+func Overflow(executions []serverlessv1alpha1.WorkflowExecution,
+	history *serverlessv1alpha1.ExecutionHistory) []*serverlessv1alpha1.WorkflowExecution {
+	succeededLimit, failedLimit := int32(DefaultSucceededLimit), int32(DefaultFailedLimit)
+	if history != nil && history.SucceededLimit != nil {
+		succeededLimit = *history.SucceededLimit
+	}
+	if history != nil && history.FailedLimit != nil {
+		failedLimit = *history.FailedLimit
+	}
+
+	var succeeded, failed []*serverlessv1alpha1.WorkflowExecution
+	for i := range executions {
+		e := &executions[i]
+		switch e.Status.Phase {
+		case serverlessv1alpha1.ExecutionSucceeded:
+			succeeded = append(succeeded, e)
+		case serverlessv1alpha1.ExecutionFailed:
+			failed = append(failed, e)
+		}
+	}
+	return append(oldest(succeeded, succeededLimit), oldest(failed, failedLimit)...)
+}
+
+// oldest returns the WorkflowExecutions except the newest limit ones, the oldest ones first
+func oldest(executions []*serverlessv1alpha1.WorkflowExecution, limit int32) []*serverlessv1alpha1.WorkflowExecution {
+	if int32(len(executions)) <= limit {
+		return nil
+	}
+	sort.SliceStable(executions, func(i, j int) bool {
+		ti, tj := finishedAt(executions[i]), finishedAt(executions[j])
+		if ti.Equal(tj) {
+			return executions[i].Name < executions[j].Name
+		}
+		return ti.Before(tj)
+	})
+	return executions[:len(executions)-int(limit)]
+}
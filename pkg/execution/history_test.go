@@ -0,0 +1,172 @@
+package execution
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	serverlessv1alpha1 "github.com/tass-io/tass-operator/api/v1alpha1"
+)
+
+var now = time.Date(2021, 6, 1, 12, 0, 0, 0, time.UTC)
+
+// execution returns a WorkflowExecution in the phase, created an hour ago and finished at the time if any
+func execution(name string, phase serverlessv1alpha1.ExecutionPhase,
+	completion *time.Time) serverlessv1alpha1.WorkflowExecution {
+	e := serverlessv1alpha1.WorkflowExecution{
+		ObjectMeta: metav1.ObjectMeta{Name: name, CreationTimestamp: metav1.NewTime(now.Add(-time.Hour))},
+	}
+	e.Status.Phase = phase
+	if completion != nil {
+		t := metav1.NewTime(*completion)
+		e.Status.CompletionTime = &t
+	}
+	return e
+}
+
+// ago returns the time the duration before now
+func ago(d time.Duration) *time.Time {
+	t := now.Add(-d)
+	return &t
+}
+
+func int32Ptr(i int32) *int32 {
+	return &i
+}
+
+func TestExpire(t *testing.T) {
+	ttl := &serverlessv1alpha1.ExecutionHistory{TTL: &metav1.Duration{Duration: 10 * time.Minute}}
+	tests := []struct {
+		name      string
+		execution serverlessv1alpha1.WorkflowExecution
+		history   *serverlessv1alpha1.ExecutionHistory
+		want      bool
+		wantLeft  time.Duration
+	}{
+		{
+			name:      "no history",
+			execution: execution("e", serverlessv1alpha1.ExecutionSucceeded, ago(time.Hour)),
+		},
+		{
+			name:      "no ttl",
+			execution: execution("e", serverlessv1alpha1.ExecutionSucceeded, ago(time.Hour)),
+			history:   &serverlessv1alpha1.ExecutionHistory{SucceededLimit: int32Ptr(1)},
+		},
+		{
+			name:      "running",
+			execution: execution("e", serverlessv1alpha1.ExecutionRunning, nil),
+			history:   ttl,
+		},
+		{
+			name:      "succeeded within ttl",
+			execution: execution("e", serverlessv1alpha1.ExecutionSucceeded, ago(4*time.Minute)),
+			history:   ttl,
+			wantLeft:  6 * time.Minute,
+		},
+		{
+			name:      "failed beyond ttl",
+			execution: execution("e", serverlessv1alpha1.ExecutionFailed, ago(11*time.Minute)),
+			history:   ttl,
+			want:      true,
+		},
+		{
+			name:      "exactly at ttl",
+			execution: execution("e", serverlessv1alpha1.ExecutionSucceeded, ago(10*time.Minute)),
+			history:   ttl,
+			want:      true,
+		},
+		{
+			name:      "no completion time falls back to creation",
+			execution: execution("e", serverlessv1alpha1.ExecutionSucceeded, nil),
+			history:   ttl,
+			want:      true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, left := Expire(&tt.execution, tt.history, now)
+			if got != tt.want || left != tt.wantLeft {
+				t.Errorf("Expire() = %v, %v, want %v, %v", got, left, tt.want, tt.wantLeft)
+			}
+		})
+	}
+}
+
+func TestOverflow(t *testing.T) {
+	tests := []struct {
+		name       string
+		executions []serverlessv1alpha1.WorkflowExecution
+		history    *serverlessv1alpha1.ExecutionHistory
+		want       []string
+	}{
+		{
+			name: "within default limits",
+			executions: []serverlessv1alpha1.WorkflowExecution{
+				execution("a", serverlessv1alpha1.ExecutionSucceeded, ago(time.Minute)),
+				execution("b", serverlessv1alpha1.ExecutionFailed, ago(time.Minute)),
+			},
+		},
+		{
+			name: "limits per phase, oldest first",
+			executions: []serverlessv1alpha1.WorkflowExecution{
+				execution("s1", serverlessv1alpha1.ExecutionSucceeded, ago(3*time.Minute)),
+				execution("s3", serverlessv1alpha1.ExecutionSucceeded, ago(time.Minute)),
+				execution("s2", serverlessv1alpha1.ExecutionSucceeded, ago(2*time.Minute)),
+				execution("f1", serverlessv1alpha1.ExecutionFailed, ago(3*time.Minute)),
+				execution("f2", serverlessv1alpha1.ExecutionFailed, ago(2*time.Minute)),
+			},
+			history: &serverlessv1alpha1.ExecutionHistory{SucceededLimit: int32Ptr(1), FailedLimit: int32Ptr(1)},
+			want:    []string{"s1", "s2", "f1"},
+		},
+		{
+			name: "running ones are never removed",
+			executions: []serverlessv1alpha1.WorkflowExecution{
+				execution("r", serverlessv1alpha1.ExecutionRunning, nil),
+				execution("s", serverlessv1alpha1.ExecutionSucceeded, ago(time.Minute)),
+			},
+			history: &serverlessv1alpha1.ExecutionHistory{SucceededLimit: int32Ptr(0), FailedLimit: int32Ptr(0)},
+			want:    []string{"s"},
+		},
+		{
+			name: "only one limit given",
+			executions: []serverlessv1alpha1.WorkflowExecution{
+				execution("s", serverlessv1alpha1.ExecutionSucceeded, ago(time.Minute)),
+				execution("f", serverlessv1alpha1.ExecutionFailed, ago(time.Minute)),
+			},
+			history: &serverlessv1alpha1.ExecutionHistory{SucceededLimit: int32Ptr(0)},
+			want:    []string{"s"},
+		},
+		{
+			name: "equal timestamps ordered by name",
+			executions: []serverlessv1alpha1.WorkflowExecution{
+				execution("c", serverlessv1alpha1.ExecutionSucceeded, ago(time.Minute)),
+				execution("a", serverlessv1alpha1.ExecutionSucceeded, ago(time.Minute)),
+				execution("b", serverlessv1alpha1.ExecutionSucceeded, ago(time.Minute)),
+			},
+			history: &serverlessv1alpha1.ExecutionHistory{SucceededLimit: int32Ptr(1)},
+			want:    []string{"a", "b"},
+		},
+		{
+			name: "no completion time falls back to creation",
+			executions: []serverlessv1alpha1.WorkflowExecution{
+				execution("recent", serverlessv1alpha1.ExecutionFailed, ago(time.Minute)),
+				execution("unreported", serverlessv1alpha1.ExecutionFailed, nil),
+			},
+			history: &serverlessv1alpha1.ExecutionHistory{FailedLimit: int32Ptr(1)},
+			want:    []string{"unreported"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, e := range Overflow(tt.executions, tt.history) {
+				got = append(got, e.Name)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Overflow() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,80 @@
+package execution
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"time"
+
+	serverlessv1alpha1 "github.com/tass-io/tass-operator/api/v1alpha1"
+)
+
+// ServicePort is the port the WorkflowRuntime Service exposes
+const ServicePort = 80
+
+// Request is the body the local scheduler accepts at /v1/workflow/
+type Request struct {
+	WorkflowName string          `json:"workflowName"`
+	FlowName     string          `json:"flowName"`
+	Parameters   json.RawMessage `json:"parameters"`
+}
+
+// Invoker invokes a Workflow and returns the JSON result
+type Invoker interface {
+	Invoke(ctx context.Context, namespace string, req Request) (string, error)
+}
+
+// HTTPInvoker invokes Workflows through their Services in the cluster
+type HTTPInvoker struct {
+	Client *http.Client
+}
+
+// NewHTTPInvoker returns an HTTPInvoker, the deadline of the invocation is given by the context
+func NewHTTPInvoker() *HTTPInvoker {
+	return &HTTPInvoker{Client: &http.Client{}}
+}
+
+// Invoke sends the request to the Service of the Workflow, the Service has the same name as the Workflow
+func (i *HTTPInvoker) Invoke(ctx context.Context, namespace string, req Request) (string, error) {
+	if len(req.Parameters) == 0 {
+		req.Parameters = json.RawMessage("{}")
+	}
+	body, err := json.Marshal(req)
+	if err != nil {
+		return "", err
+	}
+	url := "http://" + req.WorkflowName + "." + namespace + ".svc:" + strconv.Itoa(ServicePort) + "/v1/workflow/"
+	httpReq, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return "", err
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	resp, err := i.Client.Do(httpReq.WithContext(ctx))
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	result, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return "", err
+	}
+	if resp.StatusCode/100 != 2 {
+		return "", errors.New(resp.Status + ": " + string(result))
+	}
+	return string(result), nil
+}
+
+// DefaultTimeout is the time an invocation may take when the Workflow has no Deadline
+const DefaultTimeout = 5 * time.Minute
+
+// Timeout returns the time an invocation of the Workflow may take
+func Timeout(wf *serverlessv1alpha1.Workflow) time.Duration {
+	if wf.Spec.Deadline != nil {
+		return wf.Spec.Deadline.Duration
+	}
+	return DefaultTimeout
+}
@@ -0,0 +1,235 @@
+package metrics
+
+import (
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	serverlessv1alpha1 "github.com/tass-io/tass-operator/api/v1alpha1"
+)
+
+const namespace = "tass"
+
+const (
+	// StateValid is the validation state of a Workflow passing all the checks
+	StateValid = "valid"
+	// StateInvalid is the validation state of a Workflow failing any check
+	StateInvalid = "invalid"
+)
+
+// the reasons of the validation failures
+const (
+	ReasonFunctions = "functions"
+	ReasonFlows     = "flows"
+	ReasonTimeouts  = "timeouts"
+	ReasonHTTP      = "http"
+	ReasonRollout   = "rollout"
+	ReasonEnv       = "env"
+)
+
+// the sub-steps of a WorkflowRuntime reconcile
+const (
+	StepRBAC       = "rbac"
+	StepDeployment = "deployment"
+	StepService    = "service"
+)
+
+var (
+	workflows = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "workflows",
+		Help:      "Number of Workflows by validation state",
+	}, []string{"namespace", "state"})
+
+	validationFailures = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "workflow_validation_failures_total",
+		Help:      "Total number of times a Workflow started failing a validation check, by reason",
+	}, []string{"reason"})
+
+	instances = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "workflowruntime_instances",
+		Help:      "Number of instances a WorkflowRuntime records",
+	}, []string{"namespace", "workflowruntime"})
+
+	readyInstances = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "workflowruntime_ready_instances",
+		Help:      "Number of the instances of a WorkflowRuntime having a Pod IP",
+	}, []string{"namespace", "workflowruntime"})
+
+	processes = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "function_processes",
+		Help:      "Number of processes running a Function in a WorkflowRuntime",
+	}, []string{"namespace", "workflowruntime", "function"})
+
+	patchOperations = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "endpoint_patch_operations_total",
+		Help:      "Total number of the JSON patch operations applied to WorkflowRuntime instances by op type",
+	}, []string{"op", "result"})
+
+	stepDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "reconcile_step_duration_seconds",
+		Help:      "Latency of the sub-steps of a reconcile",
+		Buckets:   prometheus.ExponentialBuckets(0.005, 2, 12),
+	}, []string{"controller", "step"})
+)
+
+func init() {
+	metrics.Registry.MustRegister(
+		workflows,
+		validationFailures,
+		instances,
+		readyInstances,
+		processes,
+		patchOperations,
+		stepDuration,
+	)
+}
+
+// workflowStates records the validation state of every Workflow, keyed by namespace/name,
+// the workflows gauge is counted from it, and the checks every Workflow fails, keyed by "namespace/name"
+var workflowStates = struct {
+	sync.Mutex
+	states   map[string]map[string]string
+	failures map[string]map[string]bool
+}{states: map[string]map[string]string{}, failures: map[string]map[string]bool{}}
+
+// SetWorkflowState records the reasons of the checks the Workflow fails, it's valid if there is none
+// A failure is counted when the check starts failing, the reconciles of a Workflow still failing it,
+// e.g. on the requeues, the resyncs and the status updates, don't count it again.
+func SetWorkflowState(ns, name string, reasons []string) {
+	state := StateInvalid
+	if len(reasons) == 0 {
+		state = StateValid
+	}
+	workflowStates.Lock()
+	defer workflowStates.Unlock()
+	if workflowStates.states[ns] == nil {
+		workflowStates.states[ns] = map[string]string{}
+	}
+	workflowStates.states[ns][name] = state
+	countWorkflows(ns)
+
+	key := ns + "/" + name
+	failures := map[string]bool{}
+	for _, reason := range reasons {
+		if !workflowStates.failures[key][reason] {
+			validationFailures.WithLabelValues(reason).Inc()
+		}
+		failures[reason] = true
+	}
+	if len(failures) == 0 {
+		delete(workflowStates.failures, key)
+	} else {
+		workflowStates.failures[key] = failures
+	}
+}
+
+// DeleteWorkflow forgets the validation state of a deleted Workflow
+func DeleteWorkflow(ns, name string) {
+	workflowStates.Lock()
+	defer workflowStates.Unlock()
+	delete(workflowStates.states[ns], name)
+	delete(workflowStates.failures, ns+"/"+name)
+	countWorkflows(ns)
+}
+
+// countWorkflows sets the workflows gauge of the namespace, the caller holds the lock
+func countWorkflows(ns string) {
+	counts := map[string]float64{StateValid: 0, StateInvalid: 0}
+	for _, state := range workflowStates.states[ns] {
+		counts[state]++
+	}
+	for state, count := range counts {
+		workflows.WithLabelValues(ns, state).Set(count)
+	}
+}
+
+// functionsSeen records the Functions of every WorkflowRuntime exported,
+// so that the series of a Function no longer running are deleted
+var functionsSeen = struct {
+	sync.Mutex
+	functions map[string]map[string]bool
+}{functions: map[string]map[string]bool{}}
+
+// SetWorkflowRuntime exports the instances, the ready instances and the processes per Function of the WorkflowRuntime
+func SetWorkflowRuntime(wfrt *serverlessv1alpha1.WorkflowRuntime) {
+	total, ready, perFunction := CountInstances(wfrt)
+	instances.WithLabelValues(wfrt.Namespace, wfrt.Name).Set(float64(total))
+	readyInstances.WithLabelValues(wfrt.Namespace, wfrt.Name).Set(float64(ready))
+
+	key := wfrt.Namespace + "/" + wfrt.Name
+	functionsSeen.Lock()
+	defer functionsSeen.Unlock()
+	for function := range functionsSeen.functions[key] {
+		if _, ok := perFunction[function]; !ok {
+			processes.DeleteLabelValues(wfrt.Namespace, wfrt.Name, function)
+		}
+	}
+	seen := map[string]bool{}
+	for function, number := range perFunction {
+		processes.WithLabelValues(wfrt.Namespace, wfrt.Name, function).Set(float64(number))
+		seen[function] = true
+	}
+	functionsSeen.functions[key] = seen
+}
+
+// DeleteWorkflowRuntime deletes the series of a deleted WorkflowRuntime
+func DeleteWorkflowRuntime(ns, name string) {
+	instances.DeleteLabelValues(ns, name)
+	readyInstances.DeleteLabelValues(ns, name)
+
+	key := ns + "/" + name
+	functionsSeen.Lock()
+	defer functionsSeen.Unlock()
+	for function := range functionsSeen.functions[key] {
+		processes.DeleteLabelValues(ns, name, function)
+	}
+	delete(functionsSeen.functions, key)
+}
+
+// CountInstances returns the number of the instances of the WorkflowRuntime,
+// the ones having a Pod IP and the processes of every Function over all the instances.
+// The placeholder instance a WorkflowRuntime is created with is not counted.
+func CountInstances(wfrt *serverlessv1alpha1.WorkflowRuntime) (total, ready int, perFunction map[string]int) {
+	perFunction = map[string]int{}
+	if wfrt.Spec == nil {
+		return 0, 0, perFunction
+	}
+	for name, instance := range wfrt.Spec.Status.Instances {
+		if placeholder(name, instance) {
+			continue
+		}
+		total++
+		if instance.Status != nil && instance.Status.PodIP != nil && *instance.Status.PodIP != "" {
+			ready++
+		}
+		for function, process := range instance.ProcessRuntimes {
+			perFunction[function] += process.Number
+		}
+	}
+	return total, ready, perFunction
+}
+
+// placeholder returns whether the instance is the placeholder of a new WorkflowRuntime rather than a Pod
+func placeholder(name string, instance serverlessv1alpha1.Instance) bool {
+	return name == serverlessv1alpha1.PlaceholderInstance && instance.Status != nil &&
+		instance.Status.PodIP != nil && *instance.Status.PodIP == serverlessv1alpha1.PlaceholderPodIP
+}
+
+// PatchApplied counts the JSON patch operations of the op type, result is either "success" or "error"
+func PatchApplied(op, result string, n int) {
+	patchOperations.WithLabelValues(op, result).Add(float64(n))
+}
+
+// ObserveStep records the latency of the sub-step of the controller started at the time
+func ObserveStep(controller, step string, start time.Time) {
+	stepDuration.WithLabelValues(controller, step).Observe(time.Since(start).Seconds())
+}
@@ -0,0 +1,99 @@
+package metrics
+
+import (
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+
+	serverlessv1alpha1 "github.com/tass-io/tass-operator/api/v1alpha1"
+)
+
+func TestCountInstances(t *testing.T) {
+	ip := "10.0.0.1"
+	empty := ""
+	wfrt := &serverlessv1alpha1.WorkflowRuntime{Spec: &serverlessv1alpha1.WorkflowRuntimeSpec{
+		Status: serverlessv1alpha1.WfrtStatus{Instances: serverlessv1alpha1.Instances{
+			"a": {
+				Status:          &serverlessv1alpha1.InstanceStatus{PodIP: &ip},
+				ProcessRuntimes: serverlessv1alpha1.ProcessRuntimes{"f": {Number: 2}, "g": {Number: 1}},
+			},
+			"b": {
+				Status:          &serverlessv1alpha1.InstanceStatus{PodIP: &empty},
+				ProcessRuntimes: serverlessv1alpha1.ProcessRuntimes{"f": {Number: 3}},
+			},
+			"c": {},
+		}},
+	}}
+	total, ready, perFunction := CountInstances(wfrt)
+	if total != 3 || ready != 1 {
+		t.Errorf("CountInstances() = %d, %d, want 3, 1", total, ready)
+	}
+	if perFunction["f"] != 5 || perFunction["g"] != 1 || len(perFunction) != 2 {
+		t.Errorf("CountInstances() processes = %v, want f: 5, g: 1", perFunction)
+	}
+
+	// the placeholder of a new WorkflowRuntime is not a Pod
+	placeholder := serverlessv1alpha1.PlaceholderPodIP
+	wfrt.Spec.Status.Instances[serverlessv1alpha1.PlaceholderInstance] = serverlessv1alpha1.Instance{
+		Status: &serverlessv1alpha1.InstanceStatus{PodIP: &placeholder},
+	}
+	total, ready, _ = CountInstances(wfrt)
+	if total != 3 || ready != 1 {
+		t.Errorf("CountInstances() with the placeholder = %d, %d, want 3, 1", total, ready)
+	}
+	delete(wfrt.Spec.Status.Instances, serverlessv1alpha1.PlaceholderInstance)
+	total, ready, _ = CountInstances(&serverlessv1alpha1.WorkflowRuntime{Spec: &serverlessv1alpha1.WorkflowRuntimeSpec{
+		Status: serverlessv1alpha1.WfrtStatus{Instances: serverlessv1alpha1.Instances{
+			serverlessv1alpha1.PlaceholderInstance: {Status: &serverlessv1alpha1.InstanceStatus{PodIP: &placeholder}},
+		}},
+	}})
+	if total != 0 || ready != 0 {
+		t.Errorf("CountInstances() of a new WorkflowRuntime = %d, %d, want 0, 0", total, ready)
+	}
+
+	total, ready, perFunction = CountInstances(&serverlessv1alpha1.WorkflowRuntime{})
+	if total != 0 || ready != 0 || len(perFunction) != 0 {
+		t.Errorf("CountInstances() without spec = %d, %d, %v", total, ready, perFunction)
+	}
+}
+
+func TestWorkflowStates(t *testing.T) {
+	SetWorkflowState("test", "a", nil)
+	SetWorkflowState("test", "b", []string{ReasonFlows})
+	SetWorkflowState("test", "c", []string{ReasonFlows})
+	SetWorkflowState("test", "c", nil)
+	if got := testutil.ToFloat64(workflows.WithLabelValues("test", StateValid)); got != 2 {
+		t.Errorf("valid workflows = %v, want 2", got)
+	}
+	if got := testutil.ToFloat64(workflows.WithLabelValues("test", StateInvalid)); got != 1 {
+		t.Errorf("invalid workflows = %v, want 1", got)
+	}
+	DeleteWorkflow("test", "b")
+	if got := testutil.ToFloat64(workflows.WithLabelValues("test", StateInvalid)); got != 0 {
+		t.Errorf("invalid workflows after the deletion = %v, want 0", got)
+	}
+}
+
+func TestValidationFailures(t *testing.T) {
+	failures := func() float64 { return testutil.ToFloat64(validationFailures.WithLabelValues(ReasonHTTP)) }
+	before := failures()
+	// the reconciles of a Workflow failing the same check count it once
+	SetWorkflowState("failures", "a", []string{ReasonHTTP})
+	SetWorkflowState("failures", "a", []string{ReasonHTTP})
+	SetWorkflowState("failures", "a", []string{ReasonHTTP, ReasonEnv})
+	if got := failures() - before; got != 1 {
+		t.Errorf("failures of a Workflow still failing = %v, want 1", got)
+	}
+	// the check failing again after passing is counted again
+	SetWorkflowState("failures", "a", nil)
+	SetWorkflowState("failures", "a", []string{ReasonHTTP})
+	SetWorkflowState("failures", "b", []string{ReasonHTTP})
+	if got := failures() - before; got != 3 {
+		t.Errorf("failures after the check passed = %v, want 3", got)
+	}
+	DeleteWorkflow("failures", "a")
+	SetWorkflowState("failures", "a", []string{ReasonHTTP})
+	if got := failures() - before; got != 4 {
+		t.Errorf("failures of a recreated Workflow = %v, want 4", got)
+	}
+}
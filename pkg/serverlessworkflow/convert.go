@@ -0,0 +1,504 @@
+package serverlessworkflow
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+
+	serverlessv1alpha1 "github.com/tass-io/tass-operator/api/v1alpha1"
+	"github.com/tass-io/tass-operator/pkg/workflow"
+)
+
+// Issue is a construct of the Serverless Workflow definition which Tass can't represent
+// The converter drops or approximates the construct, so the result should be reviewed.
+type Issue struct {
+	// State is the name of the state where the construct is, empty for the top level constructs
+	State   string
+	Message string
+}
+
+func (i Issue) String() string {
+	if i.State == "" {
+		return i.Message
+	}
+	return "state " + i.State + ": " + i.Message
+}
+
+// ValidationErrors are the failed checks of an imported Workflow
+type ValidationErrors []*workflow.ValidationError
+
+func (e ValidationErrors) Error() string {
+	messages := make([]string, 0, len(e))
+	for _, err := range e {
+		messages = append(messages, err.Error())
+	}
+	return strings.Join(messages, "; ")
+}
+
+// Import parses a Serverless Workflow definition, converts it to a Tass Workflow,
+// and validates the result with the checks tassctl validate runs, see workflow.Validate.
+// The Issues are returned along with the Workflow, a non-nil error means the definition is
+// malformed or the result is not a valid Workflow, every failed check is in the ValidationErrors then.
+func Import(data []byte) (*serverlessv1alpha1.Workflow, []Issue, error) {
+	sw, err := Parse(data)
+	if err != nil {
+		return nil, nil, err
+	}
+	wf, issues, err := Convert(sw)
+	if err != nil {
+		return nil, issues, err
+	}
+	if errs := workflow.Validate(wf, nil, nil); len(errs) != 0 {
+		return wf, issues, ValidationErrors(errs)
+	}
+	return wf, issues, nil
+}
+
+// converter keeps the states of the conversion
+type converter struct {
+	sw     *Workflow
+	states map[string]*State
+	flows  []*serverlessv1alpha1.Flow
+	// stateFlows are the Flows converted from each state
+	stateFlows map[string][]*serverlessv1alpha1.Flow
+	// entries are the Flows the data goes to when transited to each state
+	entries map[string][]string
+	// exits are the Flows whose results leave each state
+	exits  map[string][]*serverlessv1alpha1.Flow
+	issues []Issue
+}
+
+// Convert converts a Serverless Workflow definition to a Tass Workflow
+// It maps the states onto Flows as follows:
+// - operation: a Flow per action, the actions are chained if the actionMode is sequential;
+// - parallel: a Flow per action, the branches are fanned out and the actions in a branch are chained;
+// - foreach: a foreach Flow running the only action over the inputCollection;
+// - switch: the Flows transiting to the state become switch Flows, the dataConditions are chained
+// by the isFalse Destination in order, and the defaultCondition is the last isFalse Destination.
+// The Functions are referenced by the refName of the functionRef, and the Workflows by the subFlowRef.
+func Convert(sw *Workflow) (*serverlessv1alpha1.Workflow, []Issue, error) {
+	if len(sw.States) == 0 {
+		return nil, nil, fmt.Errorf("workflow %s has no state", sw.ID)
+	}
+	c := &converter{
+		sw:         sw,
+		states:     map[string]*State{},
+		stateFlows: map[string][]*serverlessv1alpha1.Flow{},
+		entries:    map[string][]string{},
+		exits:      map[string][]*serverlessv1alpha1.Flow{},
+	}
+	for i := range sw.States {
+		state := &sw.States[i]
+		if _, ok := c.states[state.Name]; ok {
+			return nil, nil, fmt.Errorf("state %s has defined more than once", state.Name)
+		}
+		c.states[state.Name] = state
+	}
+	if len(sw.Events) != 0 && string(sw.Events) != "null" {
+		c.report("", "events are not supported, the event definitions are dropped")
+	}
+
+	for i := range sw.States {
+		if err := c.convertState(&sw.States[i]); err != nil {
+			return nil, c.issues, err
+		}
+	}
+	for i := range sw.States {
+		if err := c.connectState(&sw.States[i]); err != nil {
+			return nil, c.issues, err
+		}
+	}
+
+	wf := &serverlessv1alpha1.Workflow{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: serverlessv1alpha1.GroupVersion.String(),
+			Kind:       "Workflow",
+		},
+		ObjectMeta: metav1.ObjectMeta{Name: strings.ToLower(sw.ID)},
+	}
+	if errs := validation.IsDNS1123Subdomain(wf.Name); len(errs) != 0 {
+		c.report("", "id %s is not a legal Workflow name: %s", sw.ID, strings.Join(errs, ", "))
+	}
+	if err := c.setRoles(); err != nil {
+		return nil, c.issues, err
+	}
+	if sw.Timeouts != nil && len(sw.Timeouts.WorkflowExecTimeout) != 0 {
+		duration, err := refName(sw.Timeouts.WorkflowExecTimeout, "duration")
+		if err != nil {
+			return nil, c.issues, fmt.Errorf("workflowExecTimeout: %v", err)
+		}
+		if wf.Spec.Deadline, err = parseDuration(duration); err != nil {
+			c.report("", "workflowExecTimeout: %v", err)
+		}
+	}
+	for _, flow := range c.flows {
+		wf.Spec.Spec = append(wf.Spec.Spec, *flow)
+	}
+	return wf, c.issues, nil
+}
+
+func (c *converter) report(state, format string, args ...interface{}) {
+	c.issues = append(c.issues, Issue{State: state, Message: fmt.Sprintf(format, args...)})
+}
+
+// convertState converts the actions of the state to Flows, the Flows are connected later
+func (c *converter) convertState(state *State) error {
+	if state.CompensatedBy != "" {
+		c.report(state.Name, "compensation is not supported, compensatedBy is dropped")
+	}
+	switch state.Type {
+	case "operation":
+		if len(state.Actions) == 0 {
+			c.report(state.Name, "operation state has no action")
+			return nil
+		}
+		var chain []*serverlessv1alpha1.Flow
+		for i := range state.Actions {
+			flow, err := c.convertAction(state, state.Name, i, len(state.Actions))
+			if err != nil {
+				return err
+			}
+			chain = append(chain, flow)
+		}
+		if state.ActionMode == "parallel" {
+			c.fanOut(state, chain)
+		} else {
+			c.chain(state, chain)
+		}
+	case "parallel":
+		var tails []*serverlessv1alpha1.Flow
+		for _, branch := range state.Branches {
+			if len(branch.Actions) == 0 {
+				c.report(state.Name, "branch %s has no action", branch.Name)
+				continue
+			}
+			// the actions of the branch are taken as the actions of a sequential operation state
+			b := &State{Name: state.Name + "-" + branch.Name, Actions: branch.Actions, Timeouts: state.Timeouts}
+			var chain []*serverlessv1alpha1.Flow
+			for i := range branch.Actions {
+				flow, err := c.convertAction(b, b.Name, i, len(branch.Actions))
+				if err != nil {
+					return err
+				}
+				chain = append(chain, flow)
+			}
+			c.chain(b, chain)
+			c.stateFlows[state.Name] = append(c.stateFlows[state.Name], c.stateFlows[b.Name]...)
+			c.entries[state.Name] = append(c.entries[state.Name], c.entries[b.Name]...)
+			tails = append(tails, c.exits[b.Name]...)
+		}
+		c.exits[state.Name] = tails
+		if state.CompletionType != "" && state.CompletionType != "allOf" {
+			c.report(state.Name, "completionType %s is not supported, all branches run to the end",
+				state.CompletionType)
+		}
+	case "foreach":
+		if len(state.Actions) == 0 {
+			c.report(state.Name, "foreach state has no action")
+			return nil
+		}
+		if len(state.Actions) > 1 {
+			c.report(state.Name, "a foreach Flow runs one Function, only the first action is converted")
+		}
+		flow, err := c.convertAction(state, state.Name, 0, 1)
+		if err != nil {
+			return err
+		}
+		flow.Statement = serverlessv1alpha1.Foreach
+		flow.Foreach = &serverlessv1alpha1.Iteration{}
+		if flow.Foreach.Target, err = toTarget(strings.TrimSpace(
+			strings.TrimSuffix(strings.TrimPrefix(state.InputCollection, "${"), "}"))); err != nil {
+			c.report(state.Name, "inputCollection: %v", err)
+		}
+		batch, err := integer(state.BatchSize)
+		if err != nil {
+			c.report(state.Name, "batchSize %s is not an integer", string(state.BatchSize))
+		}
+		flow.Foreach.MaxConcurrency = int32(batch)
+		if state.Mode == "sequential" {
+			flow.Foreach.MaxConcurrency = 1
+		}
+		if state.OutputCollection != "" {
+			c.report(state.Name, "outputCollection is not supported, the results are aggregated as a list")
+		}
+		if state.IterationParam != "" {
+			c.report(state.Name, "iterationParam is not supported, the element is the input of the Function")
+		}
+		c.chain(state, []*serverlessv1alpha1.Flow{flow})
+	case "switch":
+		if len(state.EventConditions) != 0 {
+			c.report(state.Name, "eventConditions are not supported")
+		}
+		if state.Name == c.start() {
+			c.report(state.Name, "a switch state can't be the start state, "+
+				"Tass switches on the result of a Function")
+		}
+	default:
+		c.report(state.Name, "%s state is not supported", state.Type)
+	}
+	return nil
+}
+
+// convertAction converts the i-th of the n actions in the state to a Flow
+func (c *converter) convertAction(state *State, prefix string, i, n int) (*serverlessv1alpha1.Flow, error) {
+	action := state.Actions[i]
+	name := prefix
+	if n > 1 {
+		if action.Name != "" {
+			name += "-" + action.Name
+		} else {
+			name += "-" + strconv.Itoa(i)
+		}
+	}
+	flow := &serverlessv1alpha1.Flow{
+		Name:      name,
+		Outputs:   []string{},
+		Statement: serverlessv1alpha1.Direct,
+	}
+	var err error
+	if flow.Function, err = refName(action.FunctionRef, "refName"); err != nil {
+		return nil, fmt.Errorf("state %s: functionRef: %v", state.Name, err)
+	}
+	if flow.Workflow, err = refName(action.SubFlowRef, "workflowId"); err != nil {
+		return nil, fmt.Errorf("state %s: subFlowRef: %v", state.Name, err)
+	}
+	if len(action.EventRef) != 0 {
+		c.report(state.Name, "action %s: eventRef is not supported", name)
+	}
+	if timeout := c.actionTimeout(state); timeout != "" {
+		if flow.Timeout, err = parseDuration(timeout); err != nil {
+			c.report(state.Name, "actionExecTimeout: %v", err)
+		}
+	}
+	c.flows = append(c.flows, flow)
+	return flow, nil
+}
+
+// actionTimeout returns the actionExecTimeout of the state, it falls back to the one of the Workflow
+func (c *converter) actionTimeout(state *State) string {
+	if state.Timeouts != nil && state.Timeouts.ActionExecTimeout != "" {
+		return state.Timeouts.ActionExecTimeout
+	}
+	if c.sw.Timeouts != nil {
+		return c.sw.Timeouts.ActionExecTimeout
+	}
+	return ""
+}
+
+// chain connects the Flows one by one, the data enters the first and leaves from the last
+func (c *converter) chain(state *State, flows []*serverlessv1alpha1.Flow) {
+	for i := 0; i+1 < len(flows); i++ {
+		flows[i].Outputs = append(flows[i].Outputs, flows[i+1].Name)
+	}
+	c.stateFlows[state.Name] = flows
+	c.entries[state.Name] = []string{flows[0].Name}
+	c.exits[state.Name] = []*serverlessv1alpha1.Flow{flows[len(flows)-1]}
+}
+
+// fanOut runs the Flows at the same time, the data enters and leaves from all of them
+func (c *converter) fanOut(state *State, flows []*serverlessv1alpha1.Flow) {
+	c.stateFlows[state.Name] = flows
+	c.exits[state.Name] = flows
+	for _, flow := range flows {
+		c.entries[state.Name] = append(c.entries[state.Name], flow.Name)
+	}
+}
+
+// start returns the name of the start state
+func (c *converter) start() string {
+	if name, err := refName(c.sw.Start, "stateName"); err == nil && name != "" {
+		return name
+	}
+	return c.sw.States[0].Name
+}
+
+// connectState sets the Outputs of the Flows leaving the state and the error handlers of its Flows
+func (c *converter) connectState(state *State) error {
+	exits := c.exits[state.Name]
+	if len(exits) == 0 {
+		return nil
+	}
+	for _, onError := range state.OnErrors {
+		if err := c.connectOnError(state, onError); err != nil {
+			return err
+		}
+	}
+	if isEnd(state.End) {
+		return nil
+	}
+	next, err := refName(state.Transition, "nextState")
+	if err != nil {
+		return fmt.Errorf("state %s: transition: %v", state.Name, err)
+	}
+	if next == "" {
+		c.report(state.Name, "state has neither transition nor end, it's taken as an end")
+		return nil
+	}
+	target, ok := c.states[next]
+	if !ok {
+		return fmt.Errorf("state %s: transition to state %s which has not defined", state.Name, next)
+	}
+	if len(exits) > 1 {
+		c.report(state.Name, "Tass can't join parallel Flows, state %s runs once per Flow of the state", next)
+	}
+	if target.Type == "switch" {
+		for _, flow := range exits {
+			if err := c.connectSwitch(flow, target); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	for _, flow := range exits {
+		flow.Outputs = append(flow.Outputs, c.entries[next]...)
+	}
+	return nil
+}
+
+// connectOnError adds the error handler to all the Flows of the state
+func (c *converter) connectOnError(state *State, onError OnError) error {
+	refs := onError.ErrorRefs
+	if onError.ErrorRef != "" {
+		refs = append([]string{onError.ErrorRef}, refs...)
+	}
+	if isEnd(onError.End) {
+		c.report(state.Name, "ending the workflow on errors %s is not supported, the errors are dropped",
+			strings.Join(refs, ", "))
+		return nil
+	}
+	next, err := refName(onError.Transition, "nextState")
+	if err != nil {
+		return fmt.Errorf("state %s: onErrors: transition: %v", state.Name, err)
+	}
+	target, ok := c.states[next]
+	if !ok {
+		return fmt.Errorf("state %s: onErrors: transition to state %s which has not defined", state.Name, next)
+	}
+	if target.Type == "switch" {
+		c.report(state.Name, "the errors %s transit to switch state %s which is not supported",
+			strings.Join(refs, ", "), next)
+		return nil
+	}
+	if len(refs) == 0 {
+		refs = []string{""}
+	}
+	for _, flow := range c.stateFlows[state.Name] {
+		for _, ref := range refs {
+			if ref == "*" {
+				ref = ""
+			}
+			flow.OnError = append(flow.OnError, serverlessv1alpha1.ErrorHandler{
+				Type:  ref,
+				Flows: c.entries[next],
+			})
+		}
+	}
+	return nil
+}
+
+// connectSwitch makes the Flow switch on its result by the dataConditions of the switch state
+func (c *converter) connectSwitch(flow *serverlessv1alpha1.Flow, state *State) error {
+	if flow.Statement == serverlessv1alpha1.Foreach {
+		c.report(state.Name, "foreach Flow %s can't switch on its result, the conditions are dropped", flow.Name)
+		return nil
+	}
+	destination := func(name string, target Target) ([]string, error) {
+		if isEnd(target.End) {
+			c.report(state.Name, "condition %s ends the workflow, Flow %s goes nowhere instead", name, flow.Name)
+			return nil, nil
+		}
+		next, err := refName(target.Transition, "nextState")
+		if err != nil {
+			return nil, fmt.Errorf("state %s: condition %s: transition: %v", state.Name, name, err)
+		}
+		s, ok := c.states[next]
+		if !ok {
+			return nil, fmt.Errorf("state %s: condition %s: transition to state %s which has not defined",
+				state.Name, name, next)
+		}
+		if s.Type == "switch" {
+			c.report(state.Name, "condition %s transits to switch state %s which is not supported", name, next)
+			return nil, nil
+		}
+		return c.entries[next], nil
+	}
+
+	var conditions []*serverlessv1alpha1.Condition
+	outputs := map[string]bool{}
+	addOutputs := func(flows []string) {
+		for _, f := range flows {
+			if !outputs[f] {
+				outputs[f] = true
+				flow.Outputs = append(flow.Outputs, f)
+			}
+		}
+	}
+	// the previous root Condition, whose isFalse Destination is the next one
+	var previous *serverlessv1alpha1.Condition
+	for i, dc := range state.DataConditions {
+		name := dc.Name
+		if name == "" {
+			name = state.Name + "-" + strconv.Itoa(i)
+		}
+		group, err := parseCondition(name, dc.Condition)
+		if err != nil {
+			c.report(state.Name, "%v, the condition is dropped", err)
+			continue
+		}
+		flows, err := destination(name, dc.Target)
+		if err != nil {
+			return err
+		}
+		group[0].Destination.IsTrue.Flows = flows
+		addOutputs(flows)
+		if previous != nil {
+			previous.Destination.IsFalse.Conditions = []string{name}
+		}
+		previous = group[0]
+		conditions = append(conditions, group...)
+	}
+	if len(conditions) == 0 {
+		c.report(state.Name, "switch state has no supported dataCondition, Flow %s goes nowhere", flow.Name)
+		return nil
+	}
+	if state.DefaultCondition != nil {
+		flows, err := destination("defaultCondition", *state.DefaultCondition)
+		if err != nil {
+			return err
+		}
+		previous.Destination.IsFalse.Flows = flows
+		addOutputs(flows)
+	}
+	flow.Statement = serverlessv1alpha1.Switch
+	flow.Conditions = conditions
+	return nil
+}
+
+// setRoles marks the entrance and the exits of the Workflow
+func (c *converter) setRoles() error {
+	start := c.start()
+	state, ok := c.states[start]
+	if !ok {
+		return fmt.Errorf("start state %s has not defined", start)
+	}
+	entries := c.entries[start]
+	// a switch start state has been reported
+	if len(entries) != 1 && state.Type != "switch" {
+		c.report(start, "a Workflow should have one and only one entrance, the start state has %d",
+			len(entries))
+	}
+	for _, flow := range c.flows {
+		switch {
+		case len(c.flows) == 1:
+			flow.Role = serverlessv1alpha1.Orphan
+		case len(entries) == 1 && flow.Name == entries[0]:
+			flow.Role = serverlessv1alpha1.Start
+		case len(flow.Outputs) == 0:
+			flow.Role = serverlessv1alpha1.End
+		}
+	}
+	return nil
+}
@@ -0,0 +1,238 @@
+package serverlessworkflow
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	serverlessv1alpha1 "github.com/tass-io/tass-operator/api/v1alpha1"
+	"github.com/tass-io/tass-operator/pkg/metrics"
+)
+
+const orderWorkflow = `
+id: order
+version: "1.0"
+specVersion: "0.8"
+start: validate
+timeouts:
+  workflowExecTimeout:
+    duration: PT1M
+  actionExecTimeout: PT10S
+functions:
+- name: check
+  operation: http://check
+states:
+- name: validate
+  type: operation
+  actions:
+  - name: check
+    functionRef: check
+  - name: price
+    functionRef:
+      refName: price
+  transition: route
+  onErrors:
+  - errorRef: TimeoutError
+    transition: fallback
+- name: route
+  type: switch
+  dataConditions:
+  - name: vip
+    condition: "${ .vip && .total >= 100 }"
+    transition: notify
+  - name: bulk
+    condition: '${ .items > 10 || .kind == "bulk" }'
+    transition: ship
+  defaultCondition:
+    transition: ship
+- name: ship
+  type: foreach
+  inputCollection: "${ .items }"
+  batchSize: 5
+  actions:
+  - functionRef: pack
+  end: true
+- name: notify
+  type: parallel
+  branches:
+  - name: mail
+    actions:
+    - functionRef: mail
+  - name: sms
+    actions:
+    - subFlowRef: sms-workflow
+  end: true
+- name: fallback
+  type: operation
+  actions:
+  - functionRef: fallback
+  end:
+    terminate: true
+`
+
+func TestImport(t *testing.T) {
+	wf, issues, err := Import([]byte(orderWorkflow))
+	if err != nil {
+		t.Fatalf("Import() error = %v, issues = %v", err, issues)
+	}
+	if len(issues) != 0 {
+		t.Errorf("Import() issues = %v, want no issue", issues)
+	}
+	if wf.Name != "order" || wf.Spec.Deadline.Duration != time.Minute {
+		t.Errorf("Import() name = %s, deadline = %v", wf.Name, wf.Spec.Deadline)
+	}
+
+	flows := map[string]serverlessv1alpha1.Flow{}
+	for _, flow := range wf.Spec.Spec {
+		flows[flow.Name] = flow
+	}
+	check := flows["validate-check"]
+	if check.Role != serverlessv1alpha1.Start || !reflect.DeepEqual(check.Outputs, []string{"validate-price"}) {
+		t.Errorf("flow validate-check = %+v", check)
+	}
+	onError := []serverlessv1alpha1.ErrorHandler{{Type: "TimeoutError", Flows: []string{"fallback"}}}
+	if check.Timeout.Duration != 10*time.Second || !reflect.DeepEqual(check.OnError, onError) {
+		t.Errorf("flow validate-check timeout = %v, onError = %v", check.Timeout, check.OnError)
+	}
+
+	price := flows["validate-price"]
+	if price.Statement != serverlessv1alpha1.Switch ||
+		!reflect.DeepEqual(price.Outputs, []string{"notify-mail", "notify-sms", "ship"}) {
+		t.Errorf("flow validate-price = %+v", price)
+	}
+	var names []string
+	for _, c := range price.Conditions {
+		names = append(names, c.Name)
+	}
+	if !reflect.DeepEqual(names, []string{"vip", "vip-1", "vip-2", "bulk", "bulk-1", "bulk-2"}) {
+		t.Errorf("conditions of validate-price = %v", names)
+	}
+	if vip := price.Conditions[0]; !reflect.DeepEqual(vip.All, []string{"vip-1", "vip-2"}) ||
+		!reflect.DeepEqual(vip.Destination.IsFalse.Conditions, []string{"bulk"}) {
+		t.Errorf("condition vip = %+v", vip)
+	}
+	if bulk := price.Conditions[3]; !reflect.DeepEqual(bulk.Any, []string{"bulk-1", "bulk-2"}) ||
+		!reflect.DeepEqual(bulk.Destination.IsFalse.Flows, []string{"ship"}) {
+		t.Errorf("condition bulk = %+v", bulk)
+	}
+
+	ship := flows["ship"]
+	if ship.Statement != serverlessv1alpha1.Foreach || ship.Role != serverlessv1alpha1.End ||
+		ship.Foreach.Target != "$.items" || ship.Foreach.MaxConcurrency != 5 {
+		t.Errorf("flow ship = %+v", ship)
+	}
+	if sms := flows["notify-sms"]; sms.Workflow != "sms-workflow" || sms.Role != serverlessv1alpha1.End {
+		t.Errorf("flow notify-sms = %+v", sms)
+	}
+}
+
+func TestImportUnsupported(t *testing.T) {
+	_, issues, err := Import([]byte(`
+id: events
+start: wait
+states:
+- name: wait
+  type: event
+  end: true
+`))
+	errs, ok := err.(ValidationErrors)
+	if !ok || len(errs) == 0 || errs[0].Reason != metrics.ReasonFlows {
+		t.Errorf("Import() error = %v, want the validation errors of an empty Workflow", err)
+	}
+	if len(issues) == 0 || !strings.Contains(issues[0].String(), "event state is not supported") {
+		t.Errorf("Import() issues = %v", issues)
+	}
+}
+
+func TestConvertWithoutStates(t *testing.T) {
+	if _, _, err := Convert(&Workflow{ID: "empty"}); err == nil {
+		t.Errorf("Convert() error = nil, want an error on a definition without states")
+	}
+}
+
+func TestParseCondition(t *testing.T) {
+	tests := []struct {
+		expression string
+		want       []*serverlessv1alpha1.Condition
+		wantErr    bool
+	}{
+		{
+			expression: `${ .age >= 18 }`,
+			want: []*serverlessv1alpha1.Condition{
+				{Name: "c", Type: serverlessv1alpha1.Int, Operator: serverlessv1alpha1.Ge, Target: "$.age", Comparison: "18"},
+			},
+		},
+		{
+			expression: `$.info.rate < 0.5`,
+			want: []*serverlessv1alpha1.Condition{
+				{Name: "c", Type: serverlessv1alpha1.Float, Operator: serverlessv1alpha1.Lt, Target: "$.info.rate",
+					Comparison: "0.5"},
+			},
+		},
+		{
+			expression: `${ "a && b" == .name }`,
+			want: []*serverlessv1alpha1.Condition{
+				{Name: "c", Type: serverlessv1alpha1.String, Operator: serverlessv1alpha1.Eq, Target: "$.name",
+					Comparison: "a && b"},
+			},
+		},
+		{
+			expression: `${ 3 < .n }`,
+			want: []*serverlessv1alpha1.Condition{
+				{Name: "c", Type: serverlessv1alpha1.Int, Operator: serverlessv1alpha1.Gt, Target: "$.n", Comparison: "3"},
+			},
+		},
+		{
+			expression: `${ .a || .b && .c != false }`,
+			want: []*serverlessv1alpha1.Condition{
+				{Name: "c", Any: []string{"c-1", "c-4"}},
+				{Name: "c-1", Type: serverlessv1alpha1.Bool, Operator: serverlessv1alpha1.Eq, Target: "$.a", Comparison: "true"},
+				{Name: "c-2", Type: serverlessv1alpha1.Bool, Operator: serverlessv1alpha1.Eq, Target: "$.b", Comparison: "true"},
+				{Name: "c-3", Type: serverlessv1alpha1.Bool, Operator: serverlessv1alpha1.Ne, Target: "$.c", Comparison: "false"},
+				{Name: "c-4", All: []string{"c-2", "c-3"}},
+			},
+		},
+		{expression: `${ (.a) }`, wantErr: true},
+		{expression: `${ .items[0] == 1 }`, wantErr: true},
+		{expression: `${ .a == .b }`, wantErr: true},
+		{expression: `${ length(.a) > 1 }`, wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := parseCondition("c", tt.expression)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("parseCondition(%q) error = %v, wantErr %v", tt.expression, err, tt.wantErr)
+			continue
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("parseCondition(%q) = %+v, want %+v", tt.expression, got, tt.want)
+		}
+	}
+}
+
+func TestParseDuration(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    time.Duration
+		wantErr bool
+	}{
+		{value: "PT10S", want: 10 * time.Second},
+		{value: "PT1M30S", want: 90 * time.Second},
+		{value: "P1DT2H", want: 26 * time.Hour},
+		{value: "PT0.5S", want: 500 * time.Millisecond},
+		{value: "P", wantErr: true},
+		{value: "PT", wantErr: true},
+		{value: "P1Y", wantErr: true},
+		{value: "10s", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := parseDuration(tt.value)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("parseDuration(%q) error = %v, wantErr %v", tt.value, err, tt.wantErr)
+			continue
+		}
+		if err == nil && got.Duration != tt.want {
+			t.Errorf("parseDuration(%q) = %v, want %v", tt.value, got.Duration, tt.want)
+		}
+	}
+}
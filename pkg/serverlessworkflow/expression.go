@@ -0,0 +1,237 @@
+package serverlessworkflow
+
+import (
+	"errors"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	serverlessv1alpha1 "github.com/tass-io/tass-operator/api/v1alpha1"
+)
+
+// operators maps the comparison operators of the expressions to Tass operators,
+// flipped is used when the literal is on the left side, e.g. "18 <= .age".
+// Longer operators come first so that "<=" is not taken as "<".
+var operators = []struct {
+	token    string
+	operator serverlessv1alpha1.OperatorType
+	flipped  serverlessv1alpha1.OperatorType
+}{
+	{"==", serverlessv1alpha1.Eq, serverlessv1alpha1.Eq},
+	{"!=", serverlessv1alpha1.Ne, serverlessv1alpha1.Ne},
+	{"<=", serverlessv1alpha1.Le, serverlessv1alpha1.Ge},
+	{">=", serverlessv1alpha1.Ge, serverlessv1alpha1.Le},
+	{"<", serverlessv1alpha1.Lt, serverlessv1alpha1.Gt},
+	{">", serverlessv1alpha1.Gt, serverlessv1alpha1.Lt},
+}
+
+var pathKey = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+
+// parseCondition converts a data condition expression, e.g. `${ .age >= 18 && .country == "NL" }`,
+// to Tass Conditions. The first Condition returned is the root one and it's named by the name given,
+// the operands of the logical groups are named "<name>-1", "<name>-2" and so on.
+// Only comparisons between a path and a literal joined by "&&" or "||" are supported,
+// "&&" binds tighter than "||" and parentheses are not allowed.
+func parseCondition(name, expression string) ([]*serverlessv1alpha1.Condition, error) {
+	expr := strings.TrimSpace(expression)
+	if strings.HasPrefix(expr, "${") && strings.HasSuffix(expr, "}") {
+		expr = strings.TrimSpace(expr[2 : len(expr)-1])
+	}
+	if expr == "" {
+		return nil, errors.New("condition is empty")
+	}
+	if strings.ContainsAny(unquoted(expr), "()") {
+		return nil, errors.New("condition " + expression + " has parentheses which are not supported")
+	}
+
+	var groups [][]*serverlessv1alpha1.Condition
+	for _, alternative := range split(expr, "||") {
+		var group []*serverlessv1alpha1.Condition
+		for _, term := range split(alternative, "&&") {
+			predicate, err := parsePredicate(term)
+			if err != nil {
+				return nil, errors.New("condition " + expression + ": " + err.Error())
+			}
+			group = append(group, predicate)
+		}
+		groups = append(groups, group)
+	}
+
+	operands := 0
+	var conditions []*serverlessv1alpha1.Condition
+	add := func(c *serverlessv1alpha1.Condition) string {
+		operands++
+		c.Name = name + "-" + strconv.Itoa(operands)
+		conditions = append(conditions, c)
+		return c.Name
+	}
+	all := func(group []*serverlessv1alpha1.Condition) *serverlessv1alpha1.Condition {
+		c := &serverlessv1alpha1.Condition{}
+		for _, predicate := range group {
+			c.All = append(c.All, add(predicate))
+		}
+		return c
+	}
+
+	root := &serverlessv1alpha1.Condition{}
+	switch {
+	case len(groups) == 1 && len(groups[0]) == 1:
+		root = groups[0][0]
+	case len(groups) == 1:
+		root = all(groups[0])
+	default:
+		for _, group := range groups {
+			if len(group) == 1 {
+				root.Any = append(root.Any, add(group[0]))
+			} else {
+				root.Any = append(root.Any, add(all(group)))
+			}
+		}
+	}
+	root.Name = name
+	return append([]*serverlessv1alpha1.Condition{root}, conditions...), nil
+}
+
+// parsePredicate converts a comparison like ".age >= 18" to a Condition without name
+// A path alone, like ".approved", is the same as ".approved == true"
+func parsePredicate(term string) (*serverlessv1alpha1.Condition, error) {
+	term = strings.TrimSpace(term)
+	plain := unquoted(term)
+	for _, op := range operators {
+		i := strings.Index(plain, op.token)
+		if i < 0 {
+			continue
+		}
+		left := strings.TrimSpace(term[:i])
+		right := strings.TrimSpace(term[i+len(op.token):])
+		operator := op.operator
+		if !isPath(left) {
+			left, right = right, left
+			operator = op.flipped
+		}
+		target, err := toTarget(left)
+		if err != nil {
+			return nil, err
+		}
+		if isPath(right) {
+			return nil, errors.New("comparing two paths " + term + " is not supported")
+		}
+		t, comparison, err := literal(right)
+		if err != nil {
+			return nil, err
+		}
+		return &serverlessv1alpha1.Condition{
+			Type:       t,
+			Operator:   operator,
+			Target:     target,
+			Comparison: serverlessv1alpha1.Comparison(comparison),
+		}, nil
+	}
+	target, err := toTarget(term)
+	if err != nil {
+		return nil, err
+	}
+	return &serverlessv1alpha1.Condition{
+		Type:       serverlessv1alpha1.Bool,
+		Operator:   serverlessv1alpha1.Eq,
+		Target:     target,
+		Comparison: "true",
+	}, nil
+}
+
+// isPath returns whether the operand is a jq path like ".a.b" or a JSONPath like "$.a.b"
+func isPath(operand string) bool {
+	return strings.HasPrefix(operand, ".") || strings.HasPrefix(operand, "$")
+}
+
+// toTarget converts a jq path like ".a.b" or a JSONPath like "$.a.b" to the Target "$.a.b"
+func toTarget(path string) (string, error) {
+	switch {
+	case path == "." || path == "$":
+		return "$", nil
+	case strings.HasPrefix(path, "$."):
+		path = path[1:]
+	case !strings.HasPrefix(path, "."):
+		return "", errors.New(path + " is not a path")
+	}
+	for _, key := range strings.Split(path[1:], ".") {
+		if !pathKey.MatchString(key) {
+			return "", errors.New("path " + path + " is not supported, only object keys are allowed")
+		}
+	}
+	return "$" + path, nil
+}
+
+// literal returns the ConditionType and the Comparison of a literal
+func literal(value string) (serverlessv1alpha1.ConditionType, string, error) {
+	switch {
+	case len(value) >= 2 && (value[0] == '"' || value[0] == '\'') && value[len(value)-1] == value[0]:
+		return serverlessv1alpha1.String, value[1 : len(value)-1], nil
+	case value == "true" || value == "false":
+		return serverlessv1alpha1.Bool, value, nil
+	}
+	if _, err := strconv.ParseInt(value, 10, 64); err == nil {
+		return serverlessv1alpha1.Int, value, nil
+	}
+	if _, err := strconv.ParseFloat(value, 64); err == nil {
+		return serverlessv1alpha1.Float, value, nil
+	}
+	return "", "", errors.New("literal " + value + " is not supported")
+}
+
+// split splits the expression by the separator outside string literals
+func split(expr, sep string) []string {
+	plain := unquoted(expr)
+	var parts []string
+	for {
+		i := strings.Index(plain, sep)
+		if i < 0 {
+			return append(parts, expr)
+		}
+		parts = append(parts, expr[:i])
+		expr, plain = expr[i+len(sep):], plain[i+len(sep):]
+	}
+}
+
+// unquoted returns the expression with the characters in string literals replaced by spaces,
+// so that the operators are searched outside string literals at the same offsets
+func unquoted(expr string) string {
+	b := []byte(expr)
+	var quote byte
+	for i := range b {
+		switch {
+		case quote != 0 && b[i] == quote:
+			quote = 0
+		case quote != 0:
+			b[i] = ' '
+		case b[i] == '"' || b[i] == '\'':
+			quote = b[i]
+		}
+	}
+	return string(b)
+}
+
+var isoDuration = regexp.MustCompile(`^P(?:(\d+)D)?(?:T(?:(\d+)H)?(?:(\d+)M)?(?:(\d+(?:\.\d+)?)S)?)?$`)
+
+// parseDuration parses an ISO 8601 duration like "PT1M30S", years, months and weeks are not supported
+func parseDuration(value string) (*metav1.Duration, error) {
+	m := isoDuration.FindStringSubmatch(value)
+	if m == nil || value == "P" || strings.HasSuffix(value, "T") {
+		return nil, errors.New("duration " + value + " is not supported")
+	}
+	var d time.Duration
+	for i, unit := range []time.Duration{24 * time.Hour, time.Hour, time.Minute, time.Second} {
+		if m[i+1] == "" {
+			continue
+		}
+		n, err := strconv.ParseFloat(m[i+1], 64)
+		if err != nil {
+			return nil, err
+		}
+		d += time.Duration(n * float64(unit))
+	}
+	return &metav1.Duration{Duration: d}, nil
+}
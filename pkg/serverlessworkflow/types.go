@@ -0,0 +1,162 @@
+package serverlessworkflow
+
+import (
+	"encoding/json"
+	"errors"
+	"strconv"
+
+	"sigs.k8s.io/yaml"
+)
+
+// Workflow is the subset of the CNCF Serverless Workflow definition the converter reads
+// See https://github.com/serverlessworkflow/specification/blob/main/specification.md
+// Fields accepting either a string or an object in the specification are kept raw,
+// and resolved by the converter.
+type Workflow struct {
+	ID          string          `json:"id"`
+	Name        string          `json:"name,omitempty"`
+	Version     string          `json:"version,omitempty"`
+	SpecVersion string          `json:"specVersion,omitempty"`
+	Start       json.RawMessage `json:"start,omitempty"`
+	Functions   json.RawMessage `json:"functions,omitempty"`
+	Events      json.RawMessage `json:"events,omitempty"`
+	Timeouts    *Timeouts       `json:"timeouts,omitempty"`
+	States      []State         `json:"states"`
+}
+
+// Function is a function definition of the Workflow
+type Function struct {
+	Name      string `json:"name"`
+	Operation string `json:"operation,omitempty"`
+	Type      string `json:"type,omitempty"`
+}
+
+// Timeouts are the timeouts of the Workflow or a State, durations are in ISO 8601 format
+type Timeouts struct {
+	WorkflowExecTimeout json.RawMessage `json:"workflowExecTimeout,omitempty"`
+	ActionExecTimeout   string          `json:"actionExecTimeout,omitempty"`
+}
+
+// State is a state of the Workflow, the fields used depend on the Type
+type State struct {
+	Name string `json:"name"`
+	Type string `json:"type"`
+	// operation and foreach states
+	Actions    []Action `json:"actions,omitempty"`
+	ActionMode string   `json:"actionMode,omitempty"`
+	// parallel states
+	Branches       []Branch `json:"branches,omitempty"`
+	CompletionType string   `json:"completionType,omitempty"`
+	// switch states
+	DataConditions   []DataCondition   `json:"dataConditions,omitempty"`
+	EventConditions  []json.RawMessage `json:"eventConditions,omitempty"`
+	DefaultCondition *Target           `json:"defaultCondition,omitempty"`
+	// foreach states
+	InputCollection  string          `json:"inputCollection,omitempty"`
+	OutputCollection string          `json:"outputCollection,omitempty"`
+	IterationParam   string          `json:"iterationParam,omitempty"`
+	BatchSize        json.RawMessage `json:"batchSize,omitempty"`
+	Mode             string          `json:"mode,omitempty"`
+
+	OnErrors      []OnError `json:"onErrors,omitempty"`
+	Timeouts      *Timeouts `json:"timeouts,omitempty"`
+	CompensatedBy string    `json:"compensatedBy,omitempty"`
+	Target
+}
+
+// Target is where the data goes after a State, a condition or an error,
+// one of Transition and End should be specified
+type Target struct {
+	Transition json.RawMessage `json:"transition,omitempty"`
+	End        json.RawMessage `json:"end,omitempty"`
+}
+
+// Action is an invocation of a function or a sub workflow
+type Action struct {
+	Name        string          `json:"name,omitempty"`
+	FunctionRef json.RawMessage `json:"functionRef,omitempty"`
+	SubFlowRef  json.RawMessage `json:"subFlowRef,omitempty"`
+	EventRef    json.RawMessage `json:"eventRef,omitempty"`
+}
+
+// Branch is a branch of a parallel state
+type Branch struct {
+	Name    string   `json:"name"`
+	Actions []Action `json:"actions"`
+}
+
+// DataCondition is a condition of a switch state evaluated against the state data
+type DataCondition struct {
+	Name      string `json:"name,omitempty"`
+	Condition string `json:"condition"`
+	Target
+}
+
+// OnError handles the errors of a State
+type OnError struct {
+	ErrorRef  string   `json:"errorRef,omitempty"`
+	ErrorRefs []string `json:"errorRefs,omitempty"`
+	Target
+}
+
+// Parse parses a Serverless Workflow definition in JSON or YAML
+func Parse(data []byte) (*Workflow, error) {
+	js, err := yaml.YAMLToJSON(data)
+	if err != nil {
+		return nil, err
+	}
+	sw := &Workflow{}
+	if err := json.Unmarshal(js, sw); err != nil {
+		return nil, err
+	}
+	if len(sw.States) == 0 {
+		return nil, errors.New("workflow " + sw.ID + " has no state")
+	}
+	return sw, nil
+}
+
+// refName returns the value of a field which is either a string or an object,
+// the key is the field of the object holding the value, e.g. "refName" of a functionRef
+func refName(raw json.RawMessage, key string) (string, error) {
+	if len(raw) == 0 {
+		return "", nil
+	}
+	var s string
+	if err := json.Unmarshal(raw, &s); err == nil {
+		return s, nil
+	}
+	var obj map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &obj); err != nil {
+		return "", errors.New("expect a string or an object, got " + string(raw))
+	}
+	if err := json.Unmarshal(obj[key], &s); err != nil {
+		return "", errors.New("expect " + key + " in " + string(raw))
+	}
+	return s, nil
+}
+
+// isEnd returns whether the end field is true or an end definition object
+func isEnd(raw json.RawMessage) bool {
+	if len(raw) == 0 {
+		return false
+	}
+	var b bool
+	if err := json.Unmarshal(raw, &b); err == nil {
+		return b
+	}
+	return string(raw) != "null"
+}
+
+// integer returns the value of a field which is either a number or a numeric string
+func integer(raw json.RawMessage) (int, error) {
+	if len(raw) == 0 {
+		return 0, nil
+	}
+	var s string
+	if err := json.Unmarshal(raw, &s); err == nil {
+		return strconv.Atoi(s)
+	}
+	var n int
+	err := json.Unmarshal(raw, &n)
+	return n, err
+}
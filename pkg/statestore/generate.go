@@ -0,0 +1,224 @@
+package statestore
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	serverlessv1alpha1 "github.com/tass-io/tass-operator/api/v1alpha1"
+)
+
+const (
+	// DefaultPort is the port of a Redis
+	DefaultPort = 6379
+	// PasswordKey is the key of the password in the Secret the operator generates
+	PasswordKey = "password"
+
+	defaultImage   = "redis:6.2-alpine"
+	redisContainer = "redis"
+	dataVolume     = "data"
+	dataPath       = "/data"
+	passwordEnv    = "REDIS_PASSWORD"
+	passwordBytes  = 24
+)
+
+type generator struct {
+	store  *serverlessv1alpha1.StateStore
+	labels map[string]string
+}
+
+func newGenerator(s *serverlessv1alpha1.StateStore) (*generator, error) {
+	if s == nil {
+		return nil, fmt.Errorf("got nil when initializing Generator")
+	}
+	return &generator{
+		store: s,
+		labels: map[string]string{
+			"type": "stateStore",
+			"name": s.Name,
+		},
+	}, nil
+}
+
+// resourceName returns the name of the Deployment, Service, Secret and PersistentVolumeClaim of the provisioned Redis
+func resourceName(s *serverlessv1alpha1.StateStore) string {
+	return s.Name + "-redis"
+}
+
+// redis returns the configuration of the provisioned Redis, the defaults if there is none
+func (g generator) redis() *serverlessv1alpha1.RedisStore {
+	if g.store.Spec.Redis != nil {
+		return g.store.Spec.Redis
+	}
+	return &serverlessv1alpha1.RedisStore{}
+}
+
+// desiredSecret returns the Secret holding a new random password of the Redis
+func (g generator) desiredSecret() (*corev1.Secret, error) {
+	password := make([]byte, passwordBytes)
+	if _, err := rand.Read(password); err != nil {
+		return nil, err
+	}
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: g.store.Namespace,
+			Name:      resourceName(g.store),
+			Labels:    g.labels,
+		},
+		Type: corev1.SecretTypeOpaque,
+		Data: map[string][]byte{
+			PasswordKey: []byte(hex.EncodeToString(password)),
+		},
+	}, nil
+}
+
+// desiredPersistentVolumeClaim returns the claim of the data of the Redis, nil if no persistence is claimed
+func (g generator) desiredPersistentVolumeClaim() *corev1.PersistentVolumeClaim {
+	persistence := g.redis().Persistence
+	if persistence == nil {
+		return nil
+	}
+	return &corev1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: g.store.Namespace,
+			Name:      resourceName(g.store),
+			Labels:    g.labels,
+		},
+		Spec: corev1.PersistentVolumeClaimSpec{
+			AccessModes:      []corev1.PersistentVolumeAccessMode{corev1.ReadWriteOnce},
+			StorageClassName: persistence.StorageClassName,
+			Resources: corev1.ResourceRequirements{
+				Requests: corev1.ResourceList{
+					corev1.ResourceStorage: persistence.Size,
+				},
+			},
+		},
+	}
+}
+
+// desiredDeployment returns the single replica Deployment of the Redis,
+// the Pod is recreated on update since the volume can only be mounted once
+func (g generator) desiredDeployment() *appsv1.Deployment {
+	redis := g.redis()
+	image := redis.Image
+	if image == "" {
+		image = defaultImage
+	}
+	args := []string{"--requirepass", "$(" + passwordEnv + ")"}
+	volume := corev1.Volume{
+		Name:         dataVolume,
+		VolumeSource: corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{}},
+	}
+	if redis.Persistence != nil {
+		args = append(args, "--appendonly", "yes")
+		volume.VolumeSource = corev1.VolumeSource{
+			PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{ClaimName: resourceName(g.store)},
+		}
+	}
+	replicas := int32(1)
+
+	return &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: g.store.Namespace,
+			Name:      resourceName(g.store),
+			Labels:    g.labels,
+		},
+		Spec: appsv1.DeploymentSpec{
+			Selector: &metav1.LabelSelector{
+				MatchLabels: g.labels,
+			},
+			Replicas: &replicas,
+			Strategy: appsv1.DeploymentStrategy{Type: appsv1.RecreateDeploymentStrategyType},
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: g.labels,
+				},
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{
+						{
+							Name:  redisContainer,
+							Image: image,
+							Args:  args,
+							Ports: []corev1.ContainerPort{{
+								ContainerPort: DefaultPort,
+								Protocol:      "TCP",
+							}},
+							Env: []corev1.EnvVar{{
+								Name: passwordEnv,
+								ValueFrom: &corev1.EnvVarSource{
+									SecretKeyRef: g.passwordSecret(),
+								},
+							}},
+							Resources: redis.Resources,
+							VolumeMounts: []corev1.VolumeMount{{
+								Name:      dataVolume,
+								MountPath: dataPath,
+							}},
+							ReadinessProbe: &corev1.Probe{
+								Handler: corev1.Handler{
+									TCPSocket: &corev1.TCPSocketAction{Port: intstr.FromInt(DefaultPort)},
+								},
+							},
+						},
+					},
+					Volumes: []corev1.Volume{volume},
+				},
+			},
+		},
+	}
+}
+
+// syncDeployment copies the fields derived from the StateStore to an existing Deployment
+// It returns true if the Deployment has been changed and needs an update
+func (g generator) syncDeployment(deploy *appsv1.Deployment) bool {
+	desired := g.desiredDeployment()
+	changed := false
+	actual := &deploy.Spec.Template.Spec
+	if len(actual.Containers) != 1 || actual.Containers[0].Image != desired.Spec.Template.Spec.Containers[0].Image ||
+		!equality.Semantic.DeepEqual(actual.Containers[0].Args, desired.Spec.Template.Spec.Containers[0].Args) ||
+		!equality.Semantic.DeepEqual(actual.Containers[0].Resources,
+			desired.Spec.Template.Spec.Containers[0].Resources) {
+		actual.Containers = desired.Spec.Template.Spec.Containers
+		changed = true
+	}
+	if !equality.Semantic.DeepEqual(actual.Volumes, desired.Spec.Template.Spec.Volumes) {
+		actual.Volumes = desired.Spec.Template.Spec.Volumes
+		changed = true
+	}
+	return changed
+}
+
+// desiredService returns the Service of the Redis
+func (g generator) desiredService() *corev1.Service {
+	return &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: g.store.Namespace,
+			Name:      resourceName(g.store),
+			Labels:    g.labels,
+		},
+		Spec: corev1.ServiceSpec{
+			Selector: g.labels,
+			Ports: []corev1.ServicePort{
+				{
+					Protocol:   "TCP",
+					Port:       DefaultPort,
+					TargetPort: intstr.FromInt(DefaultPort),
+				},
+			},
+		},
+	}
+}
+
+// passwordSecret returns the key of the Secret holding the password of the provisioned Redis
+func (g generator) passwordSecret() *corev1.SecretKeySelector {
+	return &corev1.SecretKeySelector{
+		LocalObjectReference: corev1.LocalObjectReference{Name: resourceName(g.store)},
+		Key:                  PasswordKey,
+	}
+}
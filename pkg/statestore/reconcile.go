@@ -0,0 +1,235 @@
+package statestore
+
+import (
+	"context"
+
+	"github.com/go-logr/logr"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	serverlessv1alpha1 "github.com/tass-io/tass-operator/api/v1alpha1"
+)
+
+type Reconciler struct {
+	ctx      context.Context
+	cli      client.Client
+	log      logr.Logger
+	scheme   *runtime.Scheme
+	recorder record.EventRecorder
+	instance *serverlessv1alpha1.StateStore
+	gen      *generator
+}
+
+func NewReconciler(ctx context.Context, cli client.Client, l logr.Logger, s *runtime.Scheme,
+	rec record.EventRecorder, i *serverlessv1alpha1.StateStore) (*Reconciler, error) {
+	g, err := newGenerator(i)
+	if err != nil {
+		return nil, err
+	}
+	return &Reconciler{
+		ctx:      ctx,
+		cli:      cli,
+		log:      l,
+		scheme:   s,
+		recorder: rec,
+		instance: i,
+		gen:      g,
+	}, nil
+}
+
+// Reconcile provisions the Redis of the StateStore, or checks the external one,
+// and reports how to connect to it in the status of the instance, the caller updates it.
+func (r *Reconciler) Reconcile() error {
+	if r.instance.Spec.External != nil {
+		return r.reconcileExternal()
+	}
+
+	secret, err := r.gen.desiredSecret()
+	if err != nil {
+		return err
+	}
+	if err := r.ensure(secret, &corev1.Secret{}); err != nil {
+		return err
+	}
+	if pvc := r.gen.desiredPersistentVolumeClaim(); pvc != nil {
+		// the claim is never updated, most of its spec is immutable
+		if err := r.ensure(pvc, &corev1.PersistentVolumeClaim{}); err != nil {
+			return err
+		}
+	}
+	deploy, err := r.reconcileDeployment()
+	if err != nil {
+		return err
+	}
+	if err := r.ensure(r.gen.desiredService(), &corev1.Service{}); err != nil {
+		return err
+	}
+
+	status := &r.instance.Status
+	status.Host = resourceName(r.instance) + "." + r.instance.Namespace + ".svc"
+	status.Port = DefaultPort
+	status.PasswordSecret = r.gen.passwordSecret()
+	status.Ready = deploy.Status.AvailableReplicas > 0
+	status.Message = ""
+	if !status.Ready {
+		status.Message = "waiting for the Redis to be available"
+	}
+	return nil
+}
+
+// reconcileExternal reports the external Redis in the status,
+// it's ready once the Secret of its password is found
+func (r *Reconciler) reconcileExternal() error {
+	// a Redis provisioned before the store was pointed at an external one is not used anymore,
+	// its Secret and claim are kept so that going back doesn't lose the data
+	if err := r.remove(r.gen.desiredDeployment()); err != nil {
+		return err
+	}
+	if err := r.remove(r.gen.desiredService()); err != nil {
+		return err
+	}
+
+	external := r.instance.Spec.External
+	status := &r.instance.Status
+	status.Host = external.Host
+	status.Port = DefaultPort
+	if external.Port != nil {
+		status.Port = *external.Port
+	}
+	status.PasswordSecret = external.PasswordSecret
+	status.Ready = true
+	status.Message = ""
+	if external.PasswordSecret == nil {
+		return nil
+	}
+
+	secret := &corev1.Secret{}
+	err := r.cli.Get(r.ctx, types.NamespacedName{
+		Namespace: r.instance.Namespace,
+		Name:      external.PasswordSecret.Name,
+	}, secret)
+	if errors.IsNotFound(err) {
+		status.Ready = false
+		status.Message = "password Secret " + external.PasswordSecret.Name + " not found"
+	} else if err != nil {
+		r.log.Error(err, "cannot get the password Secret")
+		return err
+	} else if _, ok := secret.Data[external.PasswordSecret.Key]; !ok {
+		status.Ready = false
+		status.Message = "password Secret " + external.PasswordSecret.Name + " has no key " + external.PasswordSecret.Key
+	}
+	if !status.Ready {
+		r.recorder.Event(r.instance, corev1.EventTypeWarning, "PasswordNotFound", status.Message)
+	}
+	return nil
+}
+
+// reconcileDeployment creates or updates the Deployment of the Redis, the Deployment in the cluster is returned
+func (r *Reconciler) reconcileDeployment() (*appsv1.Deployment, error) {
+	desired := r.gen.desiredDeployment()
+	log := r.log.WithValues("deployment", types.NamespacedName{Namespace: desired.Namespace, Name: desired.Name})
+	if err := ctrl.SetControllerReference(r.instance, desired, r.scheme); err != nil {
+		return nil, err
+	}
+	actual := &appsv1.Deployment{}
+	if err := r.cli.Get(r.ctx, types.NamespacedName{
+		Namespace: desired.Namespace,
+		Name:      desired.Name,
+	}, actual); errors.IsNotFound(err) {
+		if err := r.cli.Create(r.ctx, desired); err != nil {
+			log.Error(err, "failed to create the Redis deployment")
+			r.recorder.Event(r.instance, corev1.EventTypeWarning, "CreateFailed",
+				"cannot create Deployment "+desired.Name+": "+err.Error())
+			return nil, err
+		}
+		log.Info("Redis Deployment created successfully")
+		r.recorder.Event(r.instance, corev1.EventTypeNormal, "Created", "created Deployment "+desired.Name)
+		return desired, nil
+	} else if err != nil {
+		log.Error(err, "failed to get the Redis deployment")
+		return nil, err
+	}
+
+	if r.gen.syncDeployment(actual) {
+		if err := r.cli.Update(r.ctx, actual); err != nil {
+			log.Error(err, "failed to update the Redis deployment")
+			return nil, err
+		}
+		log.Info("Redis Deployment updated successfully")
+		r.recorder.Event(r.instance, corev1.EventTypeNormal, "Updated", "updated Deployment "+desired.Name)
+	}
+	return actual, nil
+}
+
+// ensure creates the desired object owned by the StateStore if it doesn't exist,
+// an existing one is left as it is, actual is an empty object of the same kind
+func (r *Reconciler) ensure(desired, actual client.Object) error {
+	accessor, err := meta.Accessor(desired)
+	if err != nil {
+		return err
+	}
+	if err := ctrl.SetControllerReference(r.instance, accessor, r.scheme); err != nil {
+		return err
+	}
+	kind := desired.GetObjectKind().GroupVersionKind().Kind
+	if gvks, _, err := r.scheme.ObjectKinds(desired); err == nil && len(gvks) > 0 {
+		kind = gvks[0].Kind
+	}
+	log := r.log.WithValues("kind", kind, "name", accessor.GetName())
+
+	if err := r.cli.Get(r.ctx, types.NamespacedName{
+		Namespace: accessor.GetNamespace(),
+		Name:      accessor.GetName(),
+	}, actual); err == nil {
+		return nil
+	} else if !errors.IsNotFound(err) {
+		log.Error(err, "cannot get the object")
+		return err
+	}
+	if err := r.cli.Create(r.ctx, desired); err != nil && !errors.IsAlreadyExists(err) {
+		log.Error(err, "cannot create the object")
+		r.recorder.Event(r.instance, corev1.EventTypeWarning, "CreateFailed",
+			"cannot create "+kind+" "+accessor.GetName()+": "+err.Error())
+		return err
+	}
+	log.Info("created successfully")
+	r.recorder.Event(r.instance, corev1.EventTypeNormal, "Created", "created "+kind+" "+accessor.GetName())
+	return nil
+}
+
+// remove deletes the object of the provisioned Redis if it exists and is controlled by the StateStore
+func (r *Reconciler) remove(obj client.Object) error {
+	accessor, err := meta.Accessor(obj)
+	if err != nil {
+		return err
+	}
+	if err := r.cli.Get(r.ctx, types.NamespacedName{
+		Namespace: accessor.GetNamespace(),
+		Name:      accessor.GetName(),
+	}, obj); errors.IsNotFound(err) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	if !metav1.IsControlledBy(accessor, r.instance) {
+		return nil
+	}
+	if err := r.cli.Delete(r.ctx, obj); errors.IsNotFound(err) {
+		return nil
+	} else if err != nil {
+		r.log.Error(err, "cannot delete the provisioned Redis object", "name", accessor.GetName())
+		return err
+	}
+	r.log.Info("provisioned Redis object deleted", "name", accessor.GetName())
+	r.recorder.Event(r.instance, corev1.EventTypeNormal, "Deleted",
+		"deleted "+accessor.GetName()+" of the provisioned Redis")
+	return nil
+}
@@ -0,0 +1,125 @@
+package statestore
+
+import (
+	"context"
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	serverlessv1alpha1 "github.com/tass-io/tass-operator/api/v1alpha1"
+)
+
+func reconcile(t *testing.T, store *serverlessv1alpha1.StateStore, objs ...runtime.Object) client.Client {
+	s := runtime.NewScheme()
+	_ = clientgoscheme.AddToScheme(s)
+	_ = serverlessv1alpha1.AddToScheme(s)
+	cli := fake.NewFakeClientWithScheme(s, objs...)
+	r, err := NewReconciler(context.Background(), cli, log.Log, s, record.NewFakeRecorder(10), store)
+	if err != nil {
+		t.Fatalf("NewReconciler() error = %v", err)
+	}
+	if err := r.Reconcile(); err != nil {
+		t.Fatalf("Reconcile() error = %v", err)
+	}
+	return cli
+}
+
+func TestReconcileProvisioned(t *testing.T) {
+	store := &serverlessv1alpha1.StateStore{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "store"},
+		Spec: serverlessv1alpha1.StateStoreSpec{Redis: &serverlessv1alpha1.RedisStore{
+			Persistence: &serverlessv1alpha1.StorePersistence{Size: resource.MustParse("1Gi")},
+		}},
+	}
+	cli := reconcile(t, store)
+
+	key := types.NamespacedName{Namespace: "default", Name: "store-redis"}
+	secret := &corev1.Secret{}
+	if err := cli.Get(context.Background(), key, secret); err != nil || len(secret.Data[PasswordKey]) == 0 {
+		t.Fatalf("the password Secret should be generated, got %v, error = %v", secret.Data, err)
+	}
+	for _, obj := range []client.Object{&corev1.PersistentVolumeClaim{}, &corev1.Service{}} {
+		if err := cli.Get(context.Background(), key, obj); err != nil {
+			t.Errorf("Get(%T) error = %v", obj, err)
+		}
+	}
+	deploy := &appsv1.Deployment{}
+	if err := cli.Get(context.Background(), key, deploy); err != nil {
+		t.Fatalf("Get(Deployment) error = %v", err)
+	}
+	if claim := deploy.Spec.Template.Spec.Volumes[0].PersistentVolumeClaim; claim == nil || claim.ClaimName != key.Name {
+		t.Errorf("the Deployment should mount the claim, got %+v", deploy.Spec.Template.Spec.Volumes)
+	}
+
+	status := store.Status
+	if status.Host != "store-redis.default.svc" || status.Port != DefaultPort || status.Ready ||
+		status.PasswordSecret == nil || status.PasswordSecret.Name != key.Name {
+		t.Errorf("status = %+v, want the provisioned Redis not ready yet", status)
+	}
+
+	// the password is generated once, the existing Secret is kept
+	password := string(secret.Data[PasswordKey])
+	cli = reconcile(t, store, secret)
+	if err := cli.Get(context.Background(), key, secret); err != nil || string(secret.Data[PasswordKey]) != password {
+		t.Errorf("the password should be kept, got %q, error = %v", secret.Data[PasswordKey], err)
+	}
+}
+
+func TestReconcileExternal(t *testing.T) {
+	port := int32(6380)
+	external := &serverlessv1alpha1.ExternalStore{
+		Host: "redis.example.com",
+		Port: &port,
+		PasswordSecret: &corev1.SecretKeySelector{
+			LocalObjectReference: corev1.LocalObjectReference{Name: "redis"},
+			Key:                  "pass",
+		},
+	}
+	tests := []struct {
+		name      string
+		objs      []runtime.Object
+		wantReady bool
+	}{
+		{name: "missing secret"},
+		{
+			name: "missing key",
+			objs: []runtime.Object{&corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "redis"},
+				Data:       map[string][]byte{"password": []byte("p")},
+			}},
+		},
+		{
+			name: "ready",
+			objs: []runtime.Object{&corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "redis"},
+				Data:       map[string][]byte{"pass": []byte("p")},
+			}},
+			wantReady: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := &serverlessv1alpha1.StateStore{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "store"},
+				Spec:       serverlessv1alpha1.StateStoreSpec{External: external},
+			}
+			reconcile(t, store, tt.objs...)
+			if store.Status.Ready != tt.wantReady || (store.Status.Message == "") != tt.wantReady {
+				t.Errorf("status = %+v, want ready %v", store.Status, tt.wantReady)
+			}
+			if store.Status.Host != external.Host || store.Status.Port != port {
+				t.Errorf("status = %+v, want the external host and port", store.Status)
+			}
+		})
+	}
+}
@@ -0,0 +1,126 @@
+package tracing
+
+import (
+	"context"
+	"reflect"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Client traces the API calls made with a context having a span, each call gets a child span.
+// The objects created get the trace context as annotations, so that the reconciles of them continue the trace.
+type Client struct {
+	client.Client
+}
+
+// NewClient returns the Client tracing the calls of cli
+func NewClient(cli client.Client) client.Client {
+	return &Client{Client: cli}
+}
+
+// startCall starts the span of the API call if the context has a span, the returned span does nothing otherwise
+func startCall(ctx context.Context, verb string, obj runtime.Object) (context.Context, trace.Span) {
+	if !trace.SpanContextFromContext(ctx).IsValid() {
+		return ctx, trace.SpanFromContext(ctx)
+	}
+	kind := reflect.Indirect(reflect.ValueOf(obj)).Type().Name()
+	attrs := []attribute.KeyValue{attribute.String("k8s.verb", verb), attribute.String("k8s.kind", kind)}
+	if accessor, err := meta.Accessor(obj); err == nil {
+		attrs = append(attrs,
+			attribute.String("k8s.namespace", accessor.GetNamespace()),
+			attribute.String("k8s.name", accessor.GetName()))
+	}
+	return Start(ctx, verb+" "+kind, trace.WithSpanKind(trace.SpanKindClient), trace.WithAttributes(attrs...))
+}
+
+// endCall ends the span started by startCall, the span of the caller is left running
+func endCall(ctx context.Context, span trace.Span, err error) {
+	if span == trace.SpanFromContext(ctx) {
+		return
+	}
+	End(span, err)
+}
+
+func (c *Client) Get(ctx context.Context, key client.ObjectKey, obj client.Object) error {
+	callCtx, span := startCall(ctx, "get", obj)
+	err := c.Client.Get(callCtx, key, obj)
+	if key.Name != "" {
+		span.SetAttributes(attribute.String("k8s.name", key.Name), attribute.String("k8s.namespace", key.Namespace))
+	}
+	endCall(ctx, span, err)
+	return err
+}
+
+func (c *Client) List(ctx context.Context, list client.ObjectList, opts ...client.ListOption) error {
+	callCtx, span := startCall(ctx, "list", list)
+	err := c.Client.List(callCtx, list, opts...)
+	endCall(ctx, span, err)
+	return err
+}
+
+func (c *Client) Create(ctx context.Context, obj client.Object, opts ...client.CreateOption) error {
+	callCtx, span := startCall(ctx, "create", obj)
+	if accessor, err := meta.Accessor(obj); err == nil {
+		Inject(callCtx, accessor)
+	}
+	err := c.Client.Create(callCtx, obj, opts...)
+	endCall(ctx, span, err)
+	return err
+}
+
+func (c *Client) Delete(ctx context.Context, obj client.Object, opts ...client.DeleteOption) error {
+	callCtx, span := startCall(ctx, "delete", obj)
+	err := c.Client.Delete(callCtx, obj, opts...)
+	endCall(ctx, span, err)
+	return err
+}
+
+func (c *Client) Update(ctx context.Context, obj client.Object, opts ...client.UpdateOption) error {
+	callCtx, span := startCall(ctx, "update", obj)
+	err := c.Client.Update(callCtx, obj, opts...)
+	endCall(ctx, span, err)
+	return err
+}
+
+func (c *Client) Patch(ctx context.Context, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+	callCtx, span := startCall(ctx, "patch", obj)
+	span.SetAttributes(attribute.String("k8s.patch", string(patch.Type())))
+	err := c.Client.Patch(callCtx, obj, patch, opts...)
+	endCall(ctx, span, err)
+	return err
+}
+
+func (c *Client) DeleteAllOf(ctx context.Context, obj client.Object, opts ...client.DeleteAllOfOption) error {
+	callCtx, span := startCall(ctx, "deleteAllOf", obj)
+	err := c.Client.DeleteAllOf(callCtx, obj, opts...)
+	endCall(ctx, span, err)
+	return err
+}
+
+func (c *Client) Status() client.StatusWriter {
+	return &statusWriter{StatusWriter: c.Client.Status()}
+}
+
+// statusWriter traces the calls to the status subresource
+type statusWriter struct {
+	client.StatusWriter
+}
+
+func (w *statusWriter) Update(ctx context.Context, obj client.Object, opts ...client.UpdateOption) error {
+	callCtx, span := startCall(ctx, "update status", obj)
+	err := w.StatusWriter.Update(callCtx, obj, opts...)
+	endCall(ctx, span, err)
+	return err
+}
+
+func (w *statusWriter) Patch(ctx context.Context, obj client.Object, patch client.Patch,
+	opts ...client.PatchOption) error {
+	callCtx, span := startCall(ctx, "patch status", obj)
+	err := w.StatusWriter.Patch(callCtx, obj, patch, opts...)
+	endCall(ctx, span, err)
+	return err
+}
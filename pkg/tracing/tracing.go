@@ -0,0 +1,171 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"strings"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.4.0"
+	"go.opentelemetry.io/otel/trace"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// instrumentationName is the name of the tracer of the operator
+	instrumentationName = "github.com/tass-io/tass-operator"
+	// annotationPrefix prefixes the W3C trace context fields stored on the objects,
+	// e.g. serverless.tass.io/traceparent
+	annotationPrefix = "serverless.tass.io/"
+)
+
+// the exporters the spans can be sent to
+const (
+	// ExporterNone disables the tracing
+	ExporterNone = "none"
+	// ExporterStdout writes the spans to the standard output in JSON
+	ExporterStdout = "stdout"
+	// ExporterFile appends the spans to a file in JSON
+	ExporterFile = "file"
+	// ExporterOTLP sends the spans to an OpenTelemetry collector over OTLP/gRPC
+	ExporterOTLP = "otlp"
+)
+
+// serviceName is the service the spans are reported as
+const serviceName = "tass-operator"
+
+// propagator encodes the trace context in the annotations
+var propagator = propagation.TraceContext{}
+
+// Setup installs the global tracer provider sending the spans to the exporter,
+// file is the path of the file exporter, and endpoint is the address of the collector of the otlp exporter.
+// An endpoint prefixed with "http://" is reached without TLS, and an empty one is taken from
+// OTEL_EXPORTER_OTLP_ENDPOINT, or "localhost:4317" if unset.
+// The returned function flushes the spans and closes the exporter.
+// Without an exporter the spans are not recorded, the instrumentation costs nothing.
+func Setup(exporter, file, endpoint string) (func(context.Context) error, error) {
+	var spanExporter sdktrace.SpanExporter
+	var closer io.Closer
+	switch exporter {
+	case "", ExporterNone:
+		return func(context.Context) error { return nil }, nil
+	case ExporterStdout:
+		e, err := stdouttrace.New(stdouttrace.WithWriter(os.Stdout))
+		if err != nil {
+			return nil, err
+		}
+		spanExporter = e
+	case ExporterFile:
+		if file == "" {
+			return nil, errors.New("the file exporter needs a file")
+		}
+		f, err := os.OpenFile(file, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+		if err != nil {
+			return nil, err
+		}
+		e, err := stdouttrace.New(stdouttrace.WithWriter(f))
+		if err != nil {
+			f.Close()
+			return nil, err
+		}
+		spanExporter, closer = e, f
+	case ExporterOTLP:
+		var opts []otlptracegrpc.Option
+		switch {
+		case strings.HasPrefix(endpoint, "http://"):
+			opts = append(opts, otlptracegrpc.WithInsecure(),
+				otlptracegrpc.WithEndpoint(strings.TrimPrefix(endpoint, "http://")))
+		case strings.HasPrefix(endpoint, "https://"):
+			opts = append(opts, otlptracegrpc.WithEndpoint(strings.TrimPrefix(endpoint, "https://")))
+		case endpoint != "":
+			opts = append(opts, otlptracegrpc.WithEndpoint(endpoint))
+		}
+		// the connection is made in the background, an unavailable collector doesn't stop the operator
+		e, err := otlptracegrpc.New(context.Background(), opts...)
+		if err != nil {
+			return nil, err
+		}
+		spanExporter = e
+	default:
+		return nil, errors.New("unknown trace exporter " + exporter)
+	}
+
+	tp := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(spanExporter),
+		sdktrace.WithResource(resource.NewWithAttributes(semconv.SchemaURL, semconv.ServiceNameKey.String(serviceName))),
+	)
+	otel.SetTracerProvider(tp)
+	otel.SetTextMapPropagator(propagator)
+	return func(ctx context.Context) error {
+		err := tp.Shutdown(ctx)
+		if closer != nil {
+			if closeErr := closer.Close(); err == nil {
+				err = closeErr
+			}
+		}
+		return err
+	}, nil
+}
+
+// Start starts a span of the operator tracer as a child of the span in the context
+func Start(ctx context.Context, name string, opts ...trace.SpanStartOption) (context.Context, trace.Span) {
+	return otel.Tracer(instrumentationName).Start(ctx, name, opts...)
+}
+
+// End records the error on the span if any and ends it
+func End(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// Inject stores the trace context of the span in the context as annotations of the object,
+// the object is not changed if the context has no span being recorded
+func Inject(ctx context.Context, obj metav1.Object) {
+	if !trace.SpanContextFromContext(ctx).IsValid() {
+		return
+	}
+	annotations := obj.GetAnnotations()
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+	propagator.Inject(ctx, annotationCarrier(annotations))
+	obj.SetAnnotations(annotations)
+}
+
+// Extract returns the context with the trace context stored in the annotations of the object as the remote parent,
+// the context is returned as it is if the object has none
+func Extract(ctx context.Context, obj metav1.Object) context.Context {
+	return propagator.Extract(ctx, annotationCarrier(obj.GetAnnotations()))
+}
+
+// annotationCarrier carries the trace context fields in the annotations with the prefix
+type annotationCarrier map[string]string
+
+func (c annotationCarrier) Get(key string) string {
+	return c[annotationPrefix+key]
+}
+
+func (c annotationCarrier) Set(key, value string) {
+	c[annotationPrefix+key] = value
+}
+
+func (c annotationCarrier) Keys() []string {
+	var keys []string
+	for key := range c {
+		if strings.HasPrefix(key, annotationPrefix) {
+			keys = append(keys, strings.TrimPrefix(key, annotationPrefix))
+		}
+	}
+	return keys
+}
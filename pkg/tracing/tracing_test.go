@@ -0,0 +1,141 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+// record installs a tracer provider exporting the spans synchronously to the returned exporter
+func record(t *testing.T) *tracetest.InMemoryExporter {
+	exporter := tracetest.NewInMemoryExporter()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+	otel.SetTracerProvider(tp)
+	t.Cleanup(func() { otel.SetTracerProvider(trace.NewNoopTracerProvider()) })
+	return exporter
+}
+
+// attributeOf returns the value of the attribute of the span
+func attributeOf(span tracetest.SpanStub, key attribute.Key) string {
+	for _, kv := range span.Attributes {
+		if kv.Key == key {
+			return kv.Value.Emit()
+		}
+	}
+	return ""
+}
+
+func TestInjectExtract(t *testing.T) {
+	record(t)
+	pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{"a": "b"}}}
+	Inject(context.Background(), pod)
+	if len(pod.Annotations) != 1 {
+		t.Fatalf("Inject() without a span should not annotate, got %v", pod.Annotations)
+	}
+
+	ctx, span := Start(context.Background(), "parent")
+	defer span.End()
+	Inject(ctx, pod)
+	if pod.Annotations[annotationPrefix+"traceparent"] == "" {
+		t.Fatalf("Inject() annotations = %v, want the traceparent", pod.Annotations)
+	}
+	got := trace.SpanContextFromContext(Extract(context.Background(), pod))
+	if !got.IsRemote() || got.TraceID() != span.SpanContext().TraceID() || got.SpanID() != span.SpanContext().SpanID() {
+		t.Errorf("Extract() = %v, want the span context of %v", got, span.SpanContext())
+	}
+	if pod.Annotations["a"] != "b" {
+		t.Errorf("Inject() annotations = %v, want the annotation a kept", pod.Annotations)
+	}
+	if got := trace.SpanContextFromContext(Extract(context.Background(), &corev1.Pod{})); got.IsValid() {
+		t.Errorf("Extract() without annotation = %v, want an invalid span context", got)
+	}
+}
+
+func TestClient(t *testing.T) {
+	exporter := record(t)
+	cli := NewClient(fake.NewFakeClientWithScheme(scheme.Scheme))
+
+	// the calls without a span are not traced
+	cm := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "untraced"}}
+	if err := cli.Create(context.Background(), cm); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	if len(cm.Annotations) != 0 || len(exporter.GetSpans()) != 0 {
+		t.Fatalf("Create() without a span should not be traced, annotations = %v", cm.Annotations)
+	}
+
+	ctx, span := Start(context.Background(), "reconcile")
+	cm = &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "traced"}}
+	if err := cli.Create(ctx, cm); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	err := cli.Get(ctx, types.NamespacedName{Namespace: "default", Name: "missing"}, &corev1.ConfigMap{})
+	if err == nil {
+		t.Fatalf("Get() should fail on a missing ConfigMap")
+	}
+	End(span, errors.New("failed"))
+
+	got := exporter.GetSpans()
+	if len(got) != 3 {
+		t.Fatalf("got %d spans, want 3: %+v", len(got), got)
+	}
+	create, get, reconcile := got[0], got[1], got[2]
+	if create.Name != "create ConfigMap" || create.Parent.SpanID() != reconcile.SpanContext.SpanID() ||
+		attributeOf(create, "k8s.name") != "traced" {
+		t.Errorf("create span = %+v, want a child of the reconcile", create)
+	}
+	if cm.Annotations[annotationPrefix+"traceparent"] == "" {
+		t.Errorf("the created object should have the trace context, got %v", cm.Annotations)
+	}
+	if get.Name != "get ConfigMap" || get.Status.Code != codes.Error || attributeOf(get, "k8s.name") != "missing" {
+		t.Errorf("get span = %+v, want a failed get of missing", get)
+	}
+	if reconcile.Status.Code != codes.Error || reconcile.Status.Description != "failed" || len(reconcile.Events) != 1 {
+		t.Errorf("reconcile span = %+v, want the error recorded", reconcile)
+	}
+}
+
+func TestSetup(t *testing.T) {
+	tests := []struct {
+		name     string
+		exporter string
+		file     string
+		endpoint string
+		wantErr  bool
+	}{
+		{name: "none", exporter: ExporterNone},
+		{name: "default", exporter: ""},
+		{name: "file without path", exporter: ExporterFile, wantErr: true},
+		{name: "file", exporter: ExporterFile, file: t.TempDir() + "/traces.json"},
+		// the collector is dialed in the background, it doesn't have to be up
+		{name: "otlp", exporter: ExporterOTLP, endpoint: "http://127.0.0.1:4317"},
+		{name: "otlp from the environment", exporter: ExporterOTLP},
+		{name: "unknown", exporter: "jaeger", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			shutdown, err := Setup(tt.exporter, tt.file, tt.endpoint)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Setup() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil {
+				if err := shutdown(context.Background()); err != nil {
+					t.Errorf("shutdown() error = %v", err)
+				}
+			}
+		})
+	}
+	otel.SetTracerProvider(trace.NewNoopTracerProvider())
+}
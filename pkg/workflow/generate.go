@@ -4,6 +4,8 @@ import (
 	"fmt"
 
 	serverlessv1alpha1 "github.com/tass-io/tass-operator/api/v1alpha1"
+	"github.com/tass-io/tass-operator/pkg/execution"
+	"k8s.io/apimachinery/pkg/api/equality"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -21,23 +23,31 @@ func newGenerator(wf *serverlessv1alpha1.Workflow) (*generator, error) {
 	return g, nil
 }
 
-// desiredWorkflowRuntime returns a default config of WorkflowRuntime resource
-func (g generator) desiredWorkflowRuntime() *serverlessv1alpha1.WorkflowRuntime {
+// desiredWorkflowRuntime returns a default config of WorkflowRuntime resource serving the current revision
+// The stable WorkflowRuntime has the same name as the Workflow, and the canary one is named by canaryName
+func (g generator) desiredWorkflowRuntime(name string) *serverlessv1alpha1.WorkflowRuntime {
 	replicas := int32(2)
-	podip := "localhost"
+	podip := serverlessv1alpha1.PlaceholderPodIP
 	return &serverlessv1alpha1.WorkflowRuntime{
 		ObjectMeta: metav1.ObjectMeta{
 			Namespace: g.workflow.Namespace,
-			Name:      g.workflow.Name,
+			Name:      name,
 		},
 		// TODO: Provide customization future
 		Spec: &serverlessv1alpha1.WorkflowRuntimeSpec{
-			Replicas: &replicas,
+			Replicas:        &replicas,
+			Deadline:        g.workflow.Spec.Deadline.DeepCopy(),
+			FlowTimeouts:    g.flowTimeouts(),
+			Revision:        Revision(g.workflow),
+			Flows:           g.flows(),
+			Env:             g.env(),
+			StateStore:      g.workflow.Spec.StateStore,
+			SecurityContext: g.workflow.Spec.SecurityContext.DeepCopy(),
 			Status: serverlessv1alpha1.WfrtStatus{
 				// NOTE: This part initializing is essential,
 				// or the operator cannot send a add json-patch action at the first time.
 				Instances: serverlessv1alpha1.Instances{
-					"init": serverlessv1alpha1.Instance{
+					serverlessv1alpha1.PlaceholderInstance: serverlessv1alpha1.Instance{
 						Status: &serverlessv1alpha1.InstanceStatus{
 							PodIP: &podip,
 						},
@@ -47,3 +57,119 @@ func (g generator) desiredWorkflowRuntime() *serverlessv1alpha1.WorkflowRuntime
 		},
 	}
 }
+
+// flows returns a snapshot of the Flows of the Workflow
+func (g generator) flows() []serverlessv1alpha1.Flow {
+	flows := make([]serverlessv1alpha1.Flow, 0, len(g.workflow.Spec.Spec))
+	for i := range g.workflow.Spec.Spec {
+		flows = append(flows, *g.workflow.Spec.Spec[i].DeepCopy())
+	}
+	return flows
+}
+
+// env returns a copy of the environment variables of the Workflow
+func (g generator) env() map[string]string {
+	if len(g.workflow.Spec.Env) == 0 {
+		return nil
+	}
+	env := make(map[string]string, len(g.workflow.Spec.Env))
+	for name, value := range g.workflow.Spec.Env {
+		env[name] = value
+	}
+	return env
+}
+
+// flowTimeouts collects the timeouts the Flows claim, the key is the Flow name
+func (g generator) flowTimeouts() map[string]metav1.Duration {
+	var timeouts map[string]metav1.Duration
+	for _, flow := range g.workflow.Spec.Spec {
+		if flow.Timeout == nil {
+			continue
+		}
+		if timeouts == nil {
+			timeouts = map[string]metav1.Duration{}
+		}
+		timeouts[flow.Name] = *flow.Timeout
+	}
+	return timeouts
+}
+
+// syncWorkflowRuntime copies the fields derived from the Workflow to an existing WorkflowRuntime
+// It returns true if the WorkflowRuntime has been changed and needs an update
+func (g generator) syncWorkflowRuntime(wfrt *serverlessv1alpha1.WorkflowRuntime) bool {
+	if wfrt.Spec == nil {
+		wfrt.Spec = &serverlessv1alpha1.WorkflowRuntimeSpec{}
+	}
+	desired := g.desiredWorkflowRuntime(wfrt.Name)
+	changed := false
+	if !equality.Semantic.DeepEqual(wfrt.Spec.Deadline, desired.Spec.Deadline) {
+		wfrt.Spec.Deadline = desired.Spec.Deadline
+		changed = true
+	}
+	if !equality.Semantic.DeepEqual(wfrt.Spec.FlowTimeouts, desired.Spec.FlowTimeouts) {
+		wfrt.Spec.FlowTimeouts = desired.Spec.FlowTimeouts
+		changed = true
+	}
+	if wfrt.Spec.Revision != desired.Spec.Revision {
+		wfrt.Spec.Revision = desired.Spec.Revision
+		changed = true
+	}
+	if !equality.Semantic.DeepEqual(wfrt.Spec.Flows, desired.Spec.Flows) {
+		wfrt.Spec.Flows = desired.Spec.Flows
+		changed = true
+	}
+	if !equality.Semantic.DeepEqual(wfrt.Spec.Env, desired.Spec.Env) {
+		wfrt.Spec.Env = desired.Spec.Env
+		changed = true
+	}
+	if wfrt.Spec.StateStore != desired.Spec.StateStore {
+		wfrt.Spec.StateStore = desired.Spec.StateStore
+		changed = true
+	}
+	if !equality.Semantic.DeepEqual(wfrt.Spec.SecurityContext, desired.Spec.SecurityContext) {
+		wfrt.Spec.SecurityContext = desired.Spec.SecurityContext
+		changed = true
+	}
+	return changed
+}
+
+// desiredWorkflowRevision returns the WorkflowRevision holding the snapshot of the Workflow
+// and the Functions the Flows resolve to in the FunctionList
+func (g generator) desiredWorkflowRevision(hash string, revision int64,
+	functions *serverlessv1alpha1.FunctionList) *serverlessv1alpha1.WorkflowRevision {
+	snapshot := g.workflow.Spec.DeepCopy()
+	snapshot.RollbackTo = nil
+
+	var resolved []serverlessv1alpha1.FunctionSnapshot
+	visited := map[string]bool{}
+	for _, flow := range g.workflow.Spec.Spec {
+		if flow.Function == "" || visited[flow.Function] {
+			continue
+		}
+		visited[flow.Function] = true
+		function := serverlessv1alpha1.FunctionSnapshot{Name: flow.Function}
+		for i := range functions.Items {
+			if functions.Items[i].Name == flow.Function {
+				function.ResourceVersion = functions.Items[i].ResourceVersion
+				function.Spec = functions.Items[i].Spec.DeepCopy()
+				break
+			}
+		}
+		resolved = append(resolved, function)
+	}
+
+	return &serverlessv1alpha1.WorkflowRevision{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: g.workflow.Namespace,
+			Name:      g.workflow.Name + "-" + hash,
+			Labels:    map[string]string{execution.WorkflowLabel: g.workflow.Name},
+		},
+		Spec: serverlessv1alpha1.WorkflowRevisionSpec{
+			Workflow:  g.workflow.Name,
+			Revision:  revision,
+			Hash:      hash,
+			Snapshot:  *snapshot,
+			Functions: resolved,
+		},
+	}
+}
@@ -0,0 +1,154 @@
+package workflow
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"strconv"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	serverlessv1alpha1 "github.com/tass-io/tass-operator/api/v1alpha1"
+	"github.com/tass-io/tass-operator/pkg/execution"
+)
+
+// DefaultRevisionHistoryLimit is the number of the previous WorkflowRevisions kept by default
+const DefaultRevisionHistoryLimit = 10
+
+// ListRevisions returns the WorkflowRevisions of the Workflow, the oldest revision first
+func ListRevisions(ctx context.Context, cli client.Client,
+	wf *serverlessv1alpha1.Workflow) ([]serverlessv1alpha1.WorkflowRevision, error) {
+	var list serverlessv1alpha1.WorkflowRevisionList
+	if err := cli.List(ctx, &list, client.InNamespace(wf.Namespace),
+		client.MatchingLabels{execution.WorkflowLabel: wf.Name}); err != nil {
+		return nil, err
+	}
+	revisions := list.Items[:0]
+	for _, revision := range list.Items {
+		if revision.Spec.Workflow == wf.Name {
+			revisions = append(revisions, revision)
+		}
+	}
+	sort.Slice(revisions, func(i, j int) bool {
+		return revisions[i].Spec.Revision < revisions[j].Spec.Revision
+	})
+	return revisions, nil
+}
+
+// Rollback restores the Env, Spec, Deadline, StateStore and SecurityContext of the WorkflowRevision RollbackTo claims
+// and clears RollbackTo, the revisions should be sorted as ListRevisions returns. It returns a message of the result,
+// or an error when the revision is not found, then the Workflow only has RollbackTo cleared.
+func Rollback(wf *serverlessv1alpha1.Workflow, revisions []serverlessv1alpha1.WorkflowRevision) (string, error) {
+	if wf.Spec.RollbackTo == nil {
+		return "", nil
+	}
+	to := *wf.Spec.RollbackTo
+	wf.Spec.RollbackTo = nil
+
+	var target *serverlessv1alpha1.WorkflowRevision
+	for i := range revisions {
+		revision := &revisions[i]
+		if to == 0 && revision.Spec.Revision < wf.Status.Revision ||
+			to != 0 && revision.Spec.Revision == to {
+			target = revision
+		}
+	}
+	if target == nil {
+		if to == 0 {
+			return "", errors.New("no previous revision found")
+		}
+		return "", errors.New("revision " + strconv.FormatInt(to, 10) + " not found")
+	}
+	snapshot := target.Spec.Snapshot.DeepCopy()
+	wf.Spec.Env = snapshot.Env
+	wf.Spec.Spec = snapshot.Spec
+	wf.Spec.Deadline = snapshot.Deadline
+	wf.Spec.StateStore = snapshot.StateStore
+	wf.Spec.SecurityContext = snapshot.SecurityContext
+	return "rolled back to revision " + strconv.FormatInt(target.Spec.Revision, 10), nil
+}
+
+// staleRevisions returns the WorkflowRevisions beyond the history limit, the current one is always kept
+// The revisions should be sorted as ListRevisions returns.
+func staleRevisions(revisions []serverlessv1alpha1.WorkflowRevision, current int64,
+	limit int32) []*serverlessv1alpha1.WorkflowRevision {
+	var previous []*serverlessv1alpha1.WorkflowRevision
+	for i := range revisions {
+		if revisions[i].Spec.Revision != current {
+			previous = append(previous, &revisions[i])
+		}
+	}
+	if int32(len(previous)) <= limit {
+		return nil
+	}
+	return previous[:int32(len(previous))-limit]
+}
+
+// ReconcileHistory records the current Spec of the Workflow as a WorkflowRevision,
+// along with the Functions the Flows resolve to, and deletes the ones beyond the history limit.
+// A Spec the same as a previous revision, e.g. after a rollback, reuses its WorkflowRevision with a new number.
+// The revision number is only recorded on the instance status, the caller updates it.
+func (r *Reconciler) ReconcileHistory(functions *serverlessv1alpha1.FunctionList) error {
+	ctx := r.ctx
+	revisions, err := ListRevisions(ctx, r.cli, r.instance)
+	if err != nil {
+		r.log.Error(err, "cannot list WorkflowRevisions")
+		return err
+	}
+
+	hash := Revision(r.instance)
+	var latest int64
+	var current *serverlessv1alpha1.WorkflowRevision
+	for i := range revisions {
+		if revisions[i].Spec.Revision > latest {
+			latest = revisions[i].Spec.Revision
+		}
+		if revisions[i].Spec.Hash == hash {
+			current = &revisions[i]
+		}
+	}
+
+	switch {
+	case current == nil:
+		current = r.gen.desiredWorkflowRevision(hash, latest+1, functions)
+		if err := ctrl.SetControllerReference(r.instance, current, r.scheme); err != nil {
+			return err
+		}
+		if err := r.cli.Create(ctx, current); err != nil && !apierrors.IsAlreadyExists(err) {
+			r.log.Error(err, "cannot create WorkflowRevision", "revision", current.Spec.Revision)
+			return err
+		}
+		r.log.Info("WorkflowRevision created successfully", "revision", current.Spec.Revision)
+		r.recorder.Eventf(r.instance, corev1.EventTypeNormal, "RevisionCreated",
+			"recorded revision %d", current.Spec.Revision)
+		revisions = append(revisions, *current)
+	case current.Spec.Revision != latest:
+		current.Spec.Revision = latest + 1
+		if err := r.cli.Update(ctx, current); err != nil {
+			r.log.Error(err, "cannot update WorkflowRevision", "revision", current.Spec.Revision)
+			return err
+		}
+		r.log.Info("WorkflowRevision reused", "revision", current.Spec.Revision)
+	}
+	r.instance.Status.Revision = current.Spec.Revision
+
+	limit := int32(DefaultRevisionHistoryLimit)
+	if r.instance.Spec.RevisionHistoryLimit != nil {
+		limit = *r.instance.Spec.RevisionHistoryLimit
+	}
+	for _, stale := range staleRevisions(revisions, current.Spec.Revision, limit) {
+		if !metav1.IsControlledBy(stale, r.instance) {
+			continue
+		}
+		if err := r.cli.Delete(ctx, stale); err != nil && !apierrors.IsNotFound(err) {
+			r.log.Error(err, "cannot delete WorkflowRevision", "revision", stale.Spec.Revision)
+			return err
+		}
+		r.log.Info("WorkflowRevision deleted successfully", "revision", stale.Spec.Revision)
+	}
+	return nil
+}
@@ -0,0 +1,106 @@
+package workflow
+
+import (
+	"testing"
+
+	serverlessv1alpha1 "github.com/tass-io/tass-operator/api/v1alpha1"
+)
+
+func revisionsOf(numbers ...int64) []serverlessv1alpha1.WorkflowRevision {
+	var revisions []serverlessv1alpha1.WorkflowRevision
+	for _, n := range numbers {
+		revisions = append(revisions, serverlessv1alpha1.WorkflowRevision{
+			Spec: serverlessv1alpha1.WorkflowRevisionSpec{
+				Revision: n,
+				Snapshot: serverlessv1alpha1.WorkflowSpec{
+					Env:        map[string]string{"revision": string(rune('0' + n))},
+					StateStore: "store-" + string(rune('0'+n)),
+					SecurityContext: &serverlessv1alpha1.SchedulerSecurity{
+						SeccompProfile: "localhost/" + string(rune('0'+n)),
+					},
+				},
+			},
+		})
+	}
+	return revisions
+}
+
+func TestRollback(t *testing.T) {
+	tests := []struct {
+		name      string
+		to        int64
+		current   int64
+		revisions []int64
+		wantEnv   string
+		wantMsg   string
+		wantErr   bool
+	}{
+		{"previous", 0, 3, []int64{1, 2, 3}, "2", "rolled back to revision 2", false},
+		{"specific", 1, 3, []int64{1, 2, 3}, "1", "rolled back to revision 1", false},
+		{"not found", 5, 3, []int64{1, 2, 3}, "current", "", true},
+		{"no previous", 0, 1, []int64{1}, "current", "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			to := tt.to
+			wf := &serverlessv1alpha1.Workflow{
+				Spec: serverlessv1alpha1.WorkflowSpec{
+					Env:        map[string]string{"revision": "current"},
+					StateStore: "store-current",
+					SecurityContext: &serverlessv1alpha1.SchedulerSecurity{
+						SeccompProfile: "localhost/current",
+					},
+					RollbackTo: &to,
+				},
+				Status: serverlessv1alpha1.WorkflowStatus{Revision: tt.current},
+			}
+			msg, err := Rollback(wf, revisionsOf(tt.revisions...))
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Rollback() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if msg != tt.wantMsg {
+				t.Errorf("Rollback() = %q, want %q", msg, tt.wantMsg)
+			}
+			if wf.Spec.RollbackTo != nil {
+				t.Errorf("Rollback() should clear RollbackTo")
+			}
+			if got := wf.Spec.Env["revision"]; got != tt.wantEnv {
+				t.Errorf("Rollback() restored env %q, want %q", got, tt.wantEnv)
+			}
+			if got := wf.Spec.StateStore; got != "store-"+tt.wantEnv {
+				t.Errorf("Rollback() restored state store %q, want %q", got, "store-"+tt.wantEnv)
+			}
+			if got := wf.Spec.SecurityContext.SeccompProfile; got != "localhost/"+tt.wantEnv {
+				t.Errorf("Rollback() restored seccomp profile %q, want %q", got, "localhost/"+tt.wantEnv)
+			}
+		})
+	}
+}
+
+func TestStaleRevisions(t *testing.T) {
+	tests := []struct {
+		name      string
+		revisions []int64
+		current   int64
+		limit     int32
+		want      []int64
+	}{
+		{"under limit", []int64{1, 2, 3}, 3, 2, nil},
+		{"over limit", []int64{1, 2, 3, 4}, 4, 2, []int64{1}},
+		{"current kept", []int64{1, 2, 3, 4}, 1, 1, []int64{2, 3}},
+		{"no history", []int64{1, 2}, 2, 0, []int64{1}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stale := staleRevisions(revisionsOf(tt.revisions...), tt.current, tt.limit)
+			if len(stale) != len(tt.want) {
+				t.Fatalf("staleRevisions() returned %d revisions, want %v", len(stale), tt.want)
+			}
+			for i, revision := range stale {
+				if revision.Spec.Revision != tt.want[i] {
+					t.Errorf("staleRevisions()[%d] = %d, want %d", i, revision.Spec.Revision, tt.want[i])
+				}
+			}
+		})
+	}
+}
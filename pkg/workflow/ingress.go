@@ -0,0 +1,181 @@
+package workflow
+
+import (
+	"strconv"
+	"strings"
+
+	networkingv1beta1 "k8s.io/api/networking/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	serverlessv1alpha1 "github.com/tass-io/tass-operator/api/v1alpha1"
+	"github.com/tass-io/tass-operator/pkg/execution"
+)
+
+const (
+	defaultIngressClass = "nginx"
+	// schedulerPath is the path of the local scheduler the requests are rewritten to
+	schedulerPath = "/v1/workflow/"
+
+	ingressClassAnnotation  = "kubernetes.io/ingress.class"
+	rewriteTargetAnnotation = "nginx.ingress.kubernetes.io/rewrite-target"
+	snippetAnnotation       = "nginx.ingress.kubernetes.io/configuration-snippet"
+	authTypeAnnotation      = "nginx.ingress.kubernetes.io/auth-type"
+	authSecretAnnotation    = "nginx.ingress.kubernetes.io/auth-secret"
+	authURLAnnotation       = "nginx.ingress.kubernetes.io/auth-url"
+	canaryAnnotation        = "nginx.ingress.kubernetes.io/canary"
+	canaryWeightAnnotation  = "nginx.ingress.kubernetes.io/canary-weight"
+)
+
+// ownedIngressAnnotations are the annotations of the Ingresses the operator manages,
+// the others are left to the controllers adding them, like cert-manager and external-dns
+var ownedIngressAnnotations = []string{
+	ingressClassAnnotation,
+	rewriteTargetAnnotation,
+	snippetAnnotation,
+	authTypeAnnotation,
+	authSecretAnnotation,
+	authURLAnnotation,
+	canaryAnnotation,
+	canaryWeightAnnotation,
+}
+
+// syncIngressAnnotations sets the owned annotations of the actual Ingress to the desired ones,
+// and removes the owned annotations no longer desired. It returns true if the annotations have been changed.
+func syncIngressAnnotations(actual, desired *networkingv1beta1.Ingress) bool {
+	changed := false
+	for _, key := range ownedIngressAnnotations {
+		value, wanted := desired.Annotations[key]
+		current, ok := actual.Annotations[key]
+		switch {
+		case wanted && (!ok || current != value):
+			if actual.Annotations == nil {
+				actual.Annotations = map[string]string{}
+			}
+			actual.Annotations[key] = value
+			changed = true
+		case !wanted && ok:
+			delete(actual.Annotations, key)
+			changed = true
+		}
+	}
+	return changed
+}
+
+// nginxClass returns whether the IngressClass is served by ingress-nginx, an empty one is the default class
+func nginxClass(class string) bool {
+	return class == "" || class == defaultIngressClass || strings.HasPrefix(class, defaultIngressClass+"-")
+}
+
+// httpPath returns the path prefix the Ingress of the Workflow matches
+func httpPath(wf *serverlessv1alpha1.Workflow) string {
+	if wf.Spec.HTTP.Path != "" {
+		return wf.Spec.HTTP.Path
+	}
+	return "/" + wf.Name
+}
+
+// desiredIngress returns the Ingress routing the requests of the HTTPTrigger to the WorkflowRuntime Service,
+// the Service has the same name as the Workflow
+func (g generator) desiredIngress() *networkingv1beta1.Ingress {
+	trigger := g.workflow.Spec.HTTP
+	class := trigger.IngressClass
+	if class == "" {
+		class = defaultIngressClass
+	}
+	methods := []string{"POST"}
+	if len(trigger.Methods) != 0 {
+		methods = methods[:0]
+		for _, m := range trigger.Methods {
+			methods = append(methods, string(m))
+		}
+	}
+	annotations := map[string]string{
+		ingressClassAnnotation:  class,
+		rewriteTargetAnnotation: schedulerPath,
+		// ingress-nginx has no annotation for the methods, it requires allow-snippet-annotations
+		snippetAnnotation: "limit_except " + strings.Join(methods, " ") + " {\n  deny all;\n}\n",
+	}
+	if trigger.Auth != nil {
+		switch trigger.Auth.Type {
+		case serverlessv1alpha1.BasicAuth:
+			annotations[authTypeAnnotation] = "basic"
+			annotations[authSecretAnnotation] = trigger.Auth.Secret
+		case serverlessv1alpha1.ExternalAuth:
+			annotations[authURLAnnotation] = trigger.Auth.URL
+		}
+	}
+
+	ingress := &networkingv1beta1.Ingress{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:   g.workflow.Namespace,
+			Name:        g.workflow.Name,
+			Annotations: annotations,
+		},
+		Spec: networkingv1beta1.IngressSpec{
+			Rules: []networkingv1beta1.IngressRule{{
+				Host: trigger.Host,
+				IngressRuleValue: networkingv1beta1.IngressRuleValue{
+					HTTP: &networkingv1beta1.HTTPIngressRuleValue{
+						Paths: []networkingv1beta1.HTTPIngressPath{{
+							Path: httpPath(g.workflow),
+							Backend: networkingv1beta1.IngressBackend{
+								ServiceName: g.workflow.Name,
+								ServicePort: intstr.FromInt(execution.ServicePort),
+							},
+						}},
+					},
+				},
+			}},
+		},
+	}
+	if trigger.TLSSecret != "" {
+		ingress.Spec.TLS = []networkingv1beta1.IngressTLS{{
+			SecretName: trigger.TLSSecret,
+		}}
+		if trigger.Host != "" {
+			ingress.Spec.TLS[0].Hosts = []string{trigger.Host}
+		}
+	}
+	return ingress
+}
+
+// desiredCanaryIngress returns the Ingress routing the weight percent of the requests of the HTTPTrigger
+// to the canary WorkflowRuntime Service. It matches the same host and path as the Ingress of the Workflow,
+// and ingress-nginx splits the requests between them.
+func (g generator) desiredCanaryIngress(weight int32) *networkingv1beta1.Ingress {
+	ingress := g.desiredIngress()
+	name := canaryName(g.workflow)
+	ingress.Name = name
+	ingress.Annotations[canaryAnnotation] = "true"
+	ingress.Annotations[canaryWeightAnnotation] = strconv.Itoa(int(weight))
+	ingress.Spec.Rules[0].HTTP.Paths[0].Backend.ServiceName = name
+	return ingress
+}
+
+// publicURL returns the URL the Workflow is reached at through the Ingress
+// When the HTTPTrigger has no Host, the address of the load balancer is used,
+// and an empty string is returned until the load balancer is ready.
+func publicURL(wf *serverlessv1alpha1.Workflow, ingress *networkingv1beta1.Ingress) string {
+	host := wf.Spec.HTTP.Host
+	if host == "" {
+		for _, lb := range ingress.Status.LoadBalancer.Ingress {
+			if lb.Hostname != "" {
+				host = lb.Hostname
+			} else {
+				host = lb.IP
+			}
+			if host != "" {
+				break
+			}
+		}
+	}
+	if host == "" {
+		return ""
+	}
+	scheme := "http"
+	if wf.Spec.HTTP.TLSSecret != "" {
+		scheme = "https"
+	}
+	return scheme + "://" + host + httpPath(wf)
+}
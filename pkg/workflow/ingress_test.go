@@ -0,0 +1,115 @@
+package workflow
+
+import (
+	"reflect"
+	"testing"
+
+	networkingv1beta1 "k8s.io/api/networking/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	serverlessv1alpha1 "github.com/tass-io/tass-operator/api/v1alpha1"
+)
+
+func TestDesiredIngress(t *testing.T) {
+	tests := []struct {
+		name        string
+		trigger     *serverlessv1alpha1.HTTPTrigger
+		wantClass   string
+		wantSnippet string
+	}{
+		{
+			name:        "default",
+			trigger:     &serverlessv1alpha1.HTTPTrigger{},
+			wantClass:   "nginx",
+			wantSnippet: "limit_except POST {\n  deny all;\n}\n",
+		},
+		{
+			name: "methods and class",
+			trigger: &serverlessv1alpha1.HTTPTrigger{
+				IngressClass: "nginx-internal",
+				Methods:      []serverlessv1alpha1.HTTPMethod{"GET", "PUT"},
+			},
+			wantClass:   "nginx-internal",
+			wantSnippet: "limit_except GET PUT {\n  deny all;\n}\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := generator{workflow: &serverlessv1alpha1.Workflow{
+				ObjectMeta: metav1.ObjectMeta{Name: "wf", Namespace: "default"},
+				Spec:       serverlessv1alpha1.WorkflowSpec{HTTP: tt.trigger},
+			}}
+			ingress := g.desiredIngress()
+			if class := ingress.Annotations[ingressClassAnnotation]; class != tt.wantClass {
+				t.Errorf("desiredIngress() class = %s, want %s", class, tt.wantClass)
+			}
+			if snippet := ingress.Annotations[snippetAnnotation]; snippet != tt.wantSnippet {
+				t.Errorf("desiredIngress() snippet = %q, want %q", snippet, tt.wantSnippet)
+			}
+			if path := ingress.Spec.Rules[0].HTTP.Paths[0].Path; path != "/wf" {
+				t.Errorf("desiredIngress() path = %s, want /wf", path)
+			}
+		})
+	}
+}
+
+func TestSyncIngressAnnotations(t *testing.T) {
+	ingress := func(annotations map[string]string) *networkingv1beta1.Ingress {
+		return &networkingv1beta1.Ingress{ObjectMeta: metav1.ObjectMeta{Annotations: annotations}}
+	}
+	tests := []struct {
+		name        string
+		actual      map[string]string
+		desired     map[string]string
+		want        map[string]string
+		wantChanged bool
+	}{
+		{
+			name:        "created by others",
+			actual:      nil,
+			desired:     map[string]string{ingressClassAnnotation: "nginx"},
+			want:        map[string]string{ingressClassAnnotation: "nginx"},
+			wantChanged: true,
+		},
+		{
+			name: "up to date with foreign annotations",
+			actual: map[string]string{
+				ingressClassAnnotation:           "nginx",
+				"cert-manager.io/cluster-issuer": "letsencrypt",
+				"serverless.tass.io/traceparent": "00-1-2-01",
+			},
+			desired: map[string]string{ingressClassAnnotation: "nginx"},
+			want: map[string]string{
+				ingressClassAnnotation:           "nginx",
+				"cert-manager.io/cluster-issuer": "letsencrypt",
+				"serverless.tass.io/traceparent": "00-1-2-01",
+			},
+		},
+		{
+			name: "owned ones changed and removed",
+			actual: map[string]string{
+				ingressClassAnnotation:                 "nginx",
+				authTypeAnnotation:                     "basic",
+				authSecretAnnotation:                   "users",
+				"external-dns.alpha.kubernetes.io/ttl": "60",
+			},
+			desired: map[string]string{ingressClassAnnotation: "nginx-internal", authURLAnnotation: "http://auth"},
+			want: map[string]string{
+				ingressClassAnnotation:                 "nginx-internal",
+				authURLAnnotation:                      "http://auth",
+				"external-dns.alpha.kubernetes.io/ttl": "60",
+			},
+			wantChanged: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actual := ingress(tt.actual)
+			changed := syncIngressAnnotations(actual, ingress(tt.desired))
+			if changed != tt.wantChanged || !reflect.DeepEqual(actual.Annotations, tt.want) {
+				t.Errorf("syncIngressAnnotations() = %v, %v, want %v, %v",
+					changed, actual.Annotations, tt.wantChanged, tt.want)
+			}
+		})
+	}
+}
@@ -2,11 +2,18 @@ package workflow
 
 import (
 	"context"
+	"fmt"
+	"time"
 
 	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1beta1 "k8s.io/api/networking/v1beta1"
+	"k8s.io/apimachinery/pkg/api/equality"
 	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
@@ -14,53 +21,227 @@ import (
 )
 
 type Reconciler struct {
+	// ctx carries the span of the reconcile the API calls are traced in
+	ctx      context.Context
 	cli      client.Client
 	log      logr.Logger
 	scheme   *runtime.Scheme
+	recorder record.EventRecorder
 	instance *serverlessv1alpha1.Workflow
 	gen      *generator
+	// requeueAfter is when the rollout should be checked again, zero if no rollout is progressing
+	requeueAfter time.Duration
 }
 
-func NewReconciler(cli client.Client, l logr.Logger,
-	s *runtime.Scheme, i *serverlessv1alpha1.Workflow) (*Reconciler, error) {
+func NewReconciler(ctx context.Context, cli client.Client, l logr.Logger,
+	s *runtime.Scheme, rec record.EventRecorder, i *serverlessv1alpha1.Workflow) (*Reconciler, error) {
 	g, err := newGenerator(i)
 	if err != nil {
 		return nil, err
 	}
 	return &Reconciler{
+		ctx:      ctx,
 		cli:      cli,
 		log:      l,
 		scheme:   s,
+		recorder: rec,
 		instance: i,
 		gen:      g,
 	}, nil
 }
 
 func (r *Reconciler) Reconcile() error {
-	ctx := context.Background()
+	if err := r.reconcileRollout(); err != nil {
+		return err
+	}
+	if err := r.reconcileIngress(); err != nil {
+		return err
+	}
+	return r.reconcileCanaryIngress()
+}
+
+// RequeueAfter returns when the Workflow should be reconciled again to move the rollout on,
+// it's zero if no rollout is progressing
+func (r *Reconciler) RequeueAfter() time.Duration {
+	return r.requeueAfter
+}
+
+// reconcileWorkflowRuntime creates the WorkflowRuntime serving the current revision of the Workflow,
+// or keeps the fields derived from the Workflow up to date if it exists and sync is true.
+// The WorkflowRuntime in the cluster is returned.
+func (r *Reconciler) reconcileWorkflowRuntime(name string, sync bool) (*serverlessv1alpha1.WorkflowRuntime, error) {
+	ctx := r.ctx
 	log := r.log
 	namespacedName := types.NamespacedName{
 		Namespace: r.instance.Namespace,
-		Name:      r.instance.Name,
+		Name:      name,
 	}
 
-	wfrt := r.gen.desiredWorkflowRuntime()
+	wfrt := r.gen.desiredWorkflowRuntime(name)
 	if err := ctrl.SetControllerReference(r.instance, wfrt, r.scheme); err != nil {
-		return err
+		return nil, err
 	}
 	// try to see if the WorkflowRuntime is already exists
-	if err := r.cli.Get(
-		ctx, namespacedName, &serverlessv1alpha1.WorkflowRuntime{}); errors.IsNotFound(err) {
+	actual := &serverlessv1alpha1.WorkflowRuntime{}
+	if err := r.cli.Get(ctx, namespacedName, actual); errors.IsNotFound(err) {
 		if err := r.cli.Create(ctx, wfrt); err != nil {
-			return err
+			r.recorder.Event(r.instance, corev1.EventTypeWarning, "CreateFailed",
+				"cannot create WorkflowRuntime "+name+": "+err.Error())
+			return nil, err
 		}
 		// Successfully created a WorkflowRuntime
 		log.Info("WorkflowRuntime Created successfully", "wfrt", namespacedName)
-		return nil
+		r.recorder.Event(r.instance, corev1.EventTypeNormal, "Created", "created WorkflowRuntime "+name)
+		return wfrt, nil
 	} else if err != nil {
 		log.Error(err, "cannot create WorkflowRuntime", "wfrt", namespacedName)
+		return nil, err
+	}
+	if !metav1.IsControlledBy(actual, r.instance) {
+		err := fmt.Errorf("WorkflowRuntime %s is not controlled by the Workflow", name)
+		log.Error(err, "cannot update WorkflowRuntime", "wfrt", namespacedName)
+		r.recorder.Event(r.instance, corev1.EventTypeWarning, "NameConflict", err.Error())
+		return nil, err
+	}
+
+	// The WorkflowRuntime exists, keep the fields derived from the Workflow up to date.
+	// The instances in the WorkflowRuntime are maintained by others, so we don't touch them.
+	if sync && r.gen.syncWorkflowRuntime(actual) {
+		if err := r.cli.Update(ctx, actual); err != nil {
+			log.Error(err, "cannot update WorkflowRuntime", "wfrt", namespacedName)
+			r.recorder.Event(r.instance, corev1.EventTypeWarning, "UpdateFailed",
+				"cannot update WorkflowRuntime "+name+": "+err.Error())
+			return nil, err
+		}
+		log.Info("WorkflowRuntime updated successfully", "wfrt", namespacedName)
+		r.recorder.Event(r.instance, corev1.EventTypeNormal, "Updated", "updated WorkflowRuntime "+name)
+	}
+	return actual, nil
+}
+
+// deleteWorkflowRuntime deletes the WorkflowRuntime owned by the Workflow if it exists
+func (r *Reconciler) deleteWorkflowRuntime(name string) error {
+	ctx := r.ctx
+	namespacedName := types.NamespacedName{
+		Namespace: r.instance.Namespace,
+		Name:      name,
+	}
+	actual := &serverlessv1alpha1.WorkflowRuntime{}
+	if err := r.cli.Get(ctx, namespacedName, actual); errors.IsNotFound(err) {
+		return nil
+	} else if err != nil {
+		r.log.Error(err, "cannot get WorkflowRuntime", "wfrt", namespacedName)
 		return err
 	}
+	if !metav1.IsControlledBy(actual, r.instance) {
+		return nil
+	}
+	if err := r.cli.Delete(ctx, actual); err != nil && !errors.IsNotFound(err) {
+		r.log.Error(err, "cannot delete WorkflowRuntime", "wfrt", namespacedName)
+		return err
+	}
+	r.log.Info("WorkflowRuntime deleted successfully", "wfrt", namespacedName)
+	r.recorder.Event(r.instance, corev1.EventTypeNormal, "Deleted", "deleted WorkflowRuntime "+name)
+	return nil
+}
 
+// reconcileIngress creates or updates the Ingress of the HTTPTrigger and reports the URL in the status,
+// the Ingress is deleted when the HTTPTrigger is removed.
+// The status is only changed on the instance, the caller updates it.
+func (r *Reconciler) reconcileIngress() error {
+	if r.instance.Spec.HTTP == nil {
+		r.instance.Status.URL = ""
+		return r.deleteIngress(r.instance.Name)
+	}
+	actual, err := r.applyIngress(r.gen.desiredIngress())
+	if err != nil {
+		return err
+	}
+	r.instance.Status.URL = publicURL(r.instance, actual)
+	return nil
+}
+
+// reconcileCanaryIngress routes the weight of the requests of the HTTPTrigger to the canary
+// while a rollout is progressing, and deletes the canary Ingress otherwise
+func (r *Reconciler) reconcileCanaryIngress() error {
+	status := r.instance.Status.Rollout
+	if r.instance.Spec.HTTP == nil || status == nil || status.Phase != serverlessv1alpha1.RolloutProgressing {
+		return r.deleteIngress(canaryName(r.instance))
+	}
+	_, err := r.applyIngress(r.gen.desiredCanaryIngress(status.Weight))
+	return err
+}
+
+// applyIngress creates the Ingress or updates the existing one to the desired, the Ingress in the cluster is returned
+func (r *Reconciler) applyIngress(desired *networkingv1beta1.Ingress) (*networkingv1beta1.Ingress, error) {
+	ctx := r.ctx
+	namespacedName := types.NamespacedName{
+		Namespace: desired.Namespace,
+		Name:      desired.Name,
+	}
+	log := r.log.WithValues("ingress", namespacedName)
+	if err := ctrl.SetControllerReference(r.instance, desired, r.scheme); err != nil {
+		return nil, err
+	}
+
+	actual := &networkingv1beta1.Ingress{}
+	if err := r.cli.Get(ctx, namespacedName, actual); errors.IsNotFound(err) {
+		if err := r.cli.Create(ctx, desired); err != nil {
+			log.Error(err, "cannot create Ingress")
+			r.recorder.Event(r.instance, corev1.EventTypeWarning, "CreateFailed",
+				"cannot create Ingress "+desired.Name+": "+err.Error())
+			return nil, err
+		}
+		log.Info("Ingress created successfully")
+		r.recorder.Event(r.instance, corev1.EventTypeNormal, "Created", "created Ingress "+desired.Name)
+		return desired, nil
+	} else if err != nil {
+		log.Error(err, "cannot get Ingress")
+		return nil, err
+	}
+	if !metav1.IsControlledBy(actual, r.instance) {
+		err := fmt.Errorf("Ingress %s is not controlled by the Workflow", desired.Name)
+		log.Error(err, "cannot update Ingress")
+		r.recorder.Event(r.instance, corev1.EventTypeWarning, "NameConflict", err.Error())
+		return nil, err
+	}
+
+	// the annotations added by others are kept
+	annotationsChanged := syncIngressAnnotations(actual, desired)
+	if annotationsChanged || !equality.Semantic.DeepEqual(actual.Spec, desired.Spec) {
+		actual.Spec = desired.Spec
+		if err := r.cli.Update(ctx, actual); err != nil {
+			log.Error(err, "cannot update Ingress")
+			return nil, err
+		}
+		log.Info("Ingress updated successfully")
+	}
+	return actual, nil
+}
+
+// deleteIngress deletes the Ingress owned by the Workflow if it exists
+func (r *Reconciler) deleteIngress(name string) error {
+	ctx := r.ctx
+	namespacedName := types.NamespacedName{
+		Namespace: r.instance.Namespace,
+		Name:      name,
+	}
+	log := r.log.WithValues("ingress", namespacedName)
+
+	actual := &networkingv1beta1.Ingress{}
+	if err := r.cli.Get(ctx, namespacedName, actual); errors.IsNotFound(err) {
+		return nil
+	} else if err != nil {
+		log.Error(err, "cannot get Ingress")
+		return err
+	}
+	if !metav1.IsControlledBy(actual, r.instance) {
+		return nil
+	}
+	if err := r.cli.Delete(ctx, actual); err != nil && !errors.IsNotFound(err) {
+		log.Error(err, "cannot delete Ingress")
+		return err
+	}
+	log.Info("Ingress deleted successfully")
 	return nil
 }
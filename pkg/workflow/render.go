@@ -0,0 +1,254 @@
+package workflow
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	serverlessv1alpha1 "github.com/tass-io/tass-operator/api/v1alpha1"
+)
+
+// edge is a directed edge between two Flows in the rendered graph
+type edge struct {
+	from  string
+	to    string
+	label string
+	// onError means the edge is taken when the Function of the from Flow fails
+	onError bool
+}
+
+// edges returns all edges of the workflow graph in the order of the Flows
+// The edges of a switch Flow are labelled with the Conditions leading to the downstream Flow,
+// Outputs not referenced by any Condition are rendered as plain edges.
+func edges(wf *serverlessv1alpha1.Workflow) []edge {
+	var result []edge
+	for i := range wf.Spec.Spec {
+		flow := &wf.Spec.Spec[i]
+		labelled := map[string]bool{}
+		if flow.Statement == serverlessv1alpha1.Switch && len(flow.Conditions) > 0 {
+			for _, e := range conditionEdges(flow) {
+				labelled[e.to] = true
+				result = append(result, e)
+			}
+		}
+		for _, output := range flow.Outputs {
+			if !labelled[output] {
+				result = append(result, edge{from: flow.Name, to: output})
+			}
+		}
+		for _, handler := range flow.OnError {
+			label := "onError"
+			if handler.Type != "" {
+				label += " " + handler.Type
+			}
+			if handler.Message != "" {
+				label += " /" + handler.Message + "/"
+			}
+			for _, next := range handler.Flows {
+				result = append(result, edge{from: flow.Name, to: next, label: label, onError: true})
+			}
+		}
+	}
+	return result
+}
+
+// conditionEdges walks the Destination tree of a switch Flow from the root Condition
+// Each edge is labelled with the predicates on the way, joined with "&&"
+func conditionEdges(flow *serverlessv1alpha1.Flow) []edge {
+	conditionMap := map[string]*serverlessv1alpha1.Condition{}
+	for _, c := range flow.Conditions {
+		if c != nil {
+			conditionMap[c.Name] = c
+		}
+	}
+	var result []edge
+	visited := map[string]bool{}
+	var walk func(c *serverlessv1alpha1.Condition, path []string)
+	walk = func(c *serverlessv1alpha1.Condition, path []string) {
+		if c == nil || visited[c.Name] {
+			return
+		}
+		visited[c.Name] = true
+		predicate := describeCondition(c, conditionMap)
+		branches := []struct {
+			next  serverlessv1alpha1.Next
+			label string
+		}{
+			{next: c.Destination.IsTrue, label: predicate},
+			{next: c.Destination.IsFalse, label: "!(" + predicate + ")"},
+		}
+		for _, branch := range branches {
+			labels := append(append([]string{}, path...), branch.label)
+			for _, f := range branch.next.Flows {
+				result = append(result, edge{from: flow.Name, to: f, label: strings.Join(labels, " && ")})
+			}
+			for _, name := range branch.next.Conditions {
+				walk(conditionMap[name], labels)
+			}
+		}
+	}
+	walk(flow.Conditions[0], nil)
+	return result
+}
+
+// describeCondition returns a short text of a Condition, e.g. "$.a gt 50" or "all(is-vip, big-order)"
+func describeCondition(c *serverlessv1alpha1.Condition, conditionMap map[string]*serverlessv1alpha1.Condition) string {
+	describe := func(names []string) string {
+		parts := make([]string, 0, len(names))
+		for _, name := range names {
+			if operand, ok := conditionMap[name]; ok && !isLogicalCondition(operand) {
+				parts = append(parts, describeCondition(operand, conditionMap))
+			} else {
+				parts = append(parts, name)
+			}
+		}
+		return strings.Join(parts, ", ")
+	}
+	switch {
+	case len(c.All) != 0:
+		return "all(" + describe(c.All) + ")"
+	case len(c.Any) != 0:
+		return "any(" + describe(c.Any) + ")"
+	case c.Not != "":
+		return "not(" + describe([]string{c.Not}) + ")"
+	}
+	target := c.Target
+	if target == "" {
+		target = "$"
+	}
+	if c.Operator == serverlessv1alpha1.Exists || c.Operator == serverlessv1alpha1.IsNull {
+		return target + " " + string(c.Operator)
+	}
+	return target + " " + string(c.Operator) + " " + string(c.Comparison)
+}
+
+// nodeLabel returns the text shown in the node of a Flow
+func nodeLabel(flow *serverlessv1alpha1.Flow) string {
+	label := flow.Name
+	if flow.Workflow != "" {
+		label += "\nworkflow: " + flow.Workflow
+	} else {
+		label += "\nfunction: " + flow.Function
+	}
+	if flow.Statement == serverlessv1alpha1.Foreach && flow.Foreach != nil {
+		target := flow.Foreach.Target
+		if target == "" {
+			target = "$"
+		}
+		label += "\nforeach " + target
+	}
+	if flow.Timeout != nil {
+		label += "\ntimeout: " + flow.Timeout.Duration.String()
+	}
+	return label
+}
+
+// RenderDOT renders the workflow graph in the Graphviz DOT language
+// The role of a Flow is shown as the node shape:
+// start is an oval, end is a double circle, orphan is a hexagon, and others are boxes.
+// A Flow invoking another Workflow is a 3D box. The edges of OnError are dashed.
+func RenderDOT(wf *serverlessv1alpha1.Workflow) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "digraph %s {\n", dotQuote(wf.Name))
+	b.WriteString("  rankdir=TB;\n")
+	b.WriteString("  node [fontname=\"Helvetica\"];\n")
+	for i := range wf.Spec.Spec {
+		flow := &wf.Spec.Spec[i]
+		shape := "box"
+		switch flow.Role {
+		case serverlessv1alpha1.Start:
+			shape = "oval"
+		case serverlessv1alpha1.End:
+			shape = "doublecircle"
+		case serverlessv1alpha1.Orphan:
+			shape = "hexagon"
+		default:
+			if flow.Workflow != "" {
+				shape = "box3d"
+			}
+		}
+		fmt.Fprintf(&b, "  %s [label=%s, shape=%s];\n", dotQuote(flow.Name), dotQuote(nodeLabel(flow)), shape)
+	}
+	for _, e := range edges(wf) {
+		var attrs []string
+		if e.label != "" {
+			attrs = append(attrs, "label="+dotQuote(e.label))
+		}
+		if e.onError {
+			attrs = append(attrs, "style=dashed", "color=red")
+		}
+		fmt.Fprintf(&b, "  %s -> %s", dotQuote(e.from), dotQuote(e.to))
+		if len(attrs) != 0 {
+			fmt.Fprintf(&b, " [%s]", strings.Join(attrs, ", "))
+		}
+		b.WriteString(";\n")
+	}
+	b.WriteString("}\n")
+	return b.String()
+}
+
+// RenderMermaid renders the workflow graph as a Mermaid flowchart
+// The role of a Flow is shown as the node shape:
+// start is a stadium, end is a double circle, orphan is a hexagon, and others are rectangles.
+// A Flow invoking another Workflow is a subroutine. The edges of OnError are dotted.
+func RenderMermaid(wf *serverlessv1alpha1.Workflow) string {
+	var b strings.Builder
+	b.WriteString("flowchart TD\n")
+	// the node IDs are indexed, so that Flow names like "a-b" and "a_b" don't conflict
+	ids := map[string]string{}
+	for i, flow := range wf.Spec.Spec {
+		ids[flow.Name] = fmt.Sprintf("flow%d", i)
+	}
+	mermaidID := func(name string) string {
+		if id, ok := ids[name]; ok {
+			return id
+		}
+		return "undefined_" + mermaidIllegal.ReplaceAllString(name, "_")
+	}
+	for i := range wf.Spec.Spec {
+		flow := &wf.Spec.Spec[i]
+		open, close := "[", "]"
+		switch flow.Role {
+		case serverlessv1alpha1.Start:
+			open, close = "([", "])"
+		case serverlessv1alpha1.End:
+			open, close = "(((", ")))"
+		case serverlessv1alpha1.Orphan:
+			open, close = "{{", "}}"
+		default:
+			if flow.Workflow != "" {
+				open, close = "[[", "]]"
+			}
+		}
+		fmt.Fprintf(&b, "  %s%s%s%s\n", mermaidID(flow.Name), open, mermaidQuote(nodeLabel(flow)), close)
+	}
+	for _, e := range edges(wf) {
+		arrow := "-->"
+		if e.onError {
+			arrow = "-.->"
+		}
+		if e.label != "" {
+			fmt.Fprintf(&b, "  %s %s|%s| %s\n", mermaidID(e.from), arrow, mermaidQuote(e.label), mermaidID(e.to))
+		} else {
+			fmt.Fprintf(&b, "  %s %s %s\n", mermaidID(e.from), arrow, mermaidID(e.to))
+		}
+	}
+	return b.String()
+}
+
+// dotQuote quotes a string as a DOT ID
+func dotQuote(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	s = strings.ReplaceAll(s, `"`, `\"`)
+	s = strings.ReplaceAll(s, "\n", `\n`)
+	return `"` + s + `"`
+}
+
+var mermaidIllegal = regexp.MustCompile(`[^A-Za-z0-9_]`)
+
+// mermaidQuote quotes a string as a Mermaid text
+func mermaidQuote(s string) string {
+	s = strings.ReplaceAll(s, `"`, "#quot;")
+	s = strings.ReplaceAll(s, "\n", "<br/>")
+	return `"` + s + `"`
+}
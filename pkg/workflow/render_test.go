@@ -0,0 +1,165 @@
+package workflow
+
+import (
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	serverlessv1alpha1 "github.com/tass-io/tass-operator/api/v1alpha1"
+)
+
+// renderWorkflow returns a Workflow with every kind of node and edge the renderers draw
+func renderWorkflow() *serverlessv1alpha1.Workflow {
+	return &serverlessv1alpha1.Workflow{
+		ObjectMeta: metav1.ObjectMeta{Name: "order"},
+		Spec: serverlessv1alpha1.WorkflowSpec{Spec: []serverlessv1alpha1.Flow{
+			{
+				Name:      "check",
+				Function:  "check-order",
+				Statement: serverlessv1alpha1.Switch,
+				Role:      serverlessv1alpha1.Start,
+				Outputs:   []string{"ship", "review", "archive"},
+				Conditions: []*serverlessv1alpha1.Condition{
+					{
+						Name:       "big",
+						Type:       "int",
+						Operator:   serverlessv1alpha1.Gt,
+						Target:     "$.amount",
+						Comparison: "50",
+						Destination: serverlessv1alpha1.Destination{
+							IsTrue:  serverlessv1alpha1.Next{Flows: []string{"ship"}},
+							IsFalse: serverlessv1alpha1.Next{Conditions: []string{"vip"}},
+						},
+					},
+					{Name: "has-coupon", Operator: serverlessv1alpha1.Exists, Target: "$.coupon"},
+					{
+						Name: "vip",
+						All:  []string{"has-coupon", "big"},
+						Destination: serverlessv1alpha1.Destination{
+							IsTrue: serverlessv1alpha1.Next{Flows: []string{"review"}},
+						},
+					},
+				},
+				OnError: []serverlessv1alpha1.ErrorHandler{
+					{Type: "timeout", Message: "dead.*", Flows: []string{`say "sorry" [x]`}},
+				},
+			},
+			{Name: "ship", Workflow: "shipping", Outputs: []string{"notify"}},
+			{Name: "review", Function: "review", Outputs: []string{"notify"}},
+			{Name: "archive", Function: "archive", Outputs: []string{"notify"},
+				OnError: []serverlessv1alpha1.ErrorHandler{{Flows: []string{"gone[1]"}}}},
+			{
+				Name:      "notify",
+				Function:  "notify",
+				Statement: serverlessv1alpha1.Foreach,
+				Role:      serverlessv1alpha1.End,
+				Foreach:   &serverlessv1alpha1.Iteration{Target: "$.items"},
+				Timeout:   &metav1.Duration{Duration: 5 * time.Second},
+			},
+			{Name: `say "sorry" [x]`, Function: `say\it`, Role: serverlessv1alpha1.Orphan},
+		}},
+	}
+}
+
+func TestRenderDOT(t *testing.T) {
+	tests := []struct {
+		name string
+		wf   *serverlessv1alpha1.Workflow
+		want string
+	}{
+		{
+			name: "empty",
+			wf:   &serverlessv1alpha1.Workflow{ObjectMeta: metav1.ObjectMeta{Name: "empty"}},
+			want: `digraph "empty" {
+  rankdir=TB;
+  node [fontname="Helvetica"];
+}
+`,
+		},
+		{
+			name: "every node and edge",
+			wf:   renderWorkflow(),
+			want: `digraph "order" {
+  rankdir=TB;
+  node [fontname="Helvetica"];
+  "check" [label="check\nfunction: check-order", shape=oval];
+  "ship" [label="ship\nworkflow: shipping", shape=box3d];
+  "review" [label="review\nfunction: review", shape=box];
+  "archive" [label="archive\nfunction: archive", shape=box];
+  "notify" [label="notify\nfunction: notify\nforeach $.items\ntimeout: 5s", shape=doublecircle];
+  "say \"sorry\" [x]" [label="say \"sorry\" [x]\nfunction: say\\it", shape=hexagon];
+  "check" -> "ship" [label="$.amount gt 50"];
+  "check" -> "review" [label="!($.amount gt 50) && all($.coupon exists, $.amount gt 50)"];
+  "check" -> "archive";
+  "check" -> "say \"sorry\" [x]" [label="onError timeout /dead.*/", style=dashed, color=red];
+  "ship" -> "notify";
+  "review" -> "notify";
+  "archive" -> "notify";
+  "archive" -> "gone[1]" [label="onError", style=dashed, color=red];
+}
+`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := RenderDOT(tt.wf); got != tt.want {
+				t.Errorf("RenderDOT() =\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRenderMermaid(t *testing.T) {
+	tests := []struct {
+		name string
+		wf   *serverlessv1alpha1.Workflow
+		want string
+	}{
+		{
+			name: "empty",
+			wf:   &serverlessv1alpha1.Workflow{ObjectMeta: metav1.ObjectMeta{Name: "empty"}},
+			want: "flowchart TD\n",
+		},
+		{
+			name: "every node and edge",
+			wf:   renderWorkflow(),
+			want: `flowchart TD
+  flow0(["check<br/>function: check-order"])
+  flow1[["ship<br/>workflow: shipping"]]
+  flow2["review<br/>function: review"]
+  flow3["archive<br/>function: archive"]
+  flow4((("notify<br/>function: notify<br/>foreach $.items<br/>timeout: 5s")))
+  flow5{{"say #quot;sorry#quot; [x]<br/>function: say\it"}}
+  flow0 -->|"$.amount gt 50"| flow1
+  flow0 -->|"!($.amount gt 50) && all($.coupon exists, $.amount gt 50)"| flow2
+  flow0 --> flow3
+  flow0 -.->|"onError timeout /dead.*/"| flow5
+  flow1 --> flow4
+  flow2 --> flow4
+  flow3 --> flow4
+  flow3 -.->|"onError"| undefined_gone_1_
+`,
+		},
+		{
+			// the names differing in the characters Mermaid doesn't allow in IDs are different nodes
+			name: "similar names",
+			wf: &serverlessv1alpha1.Workflow{Spec: serverlessv1alpha1.WorkflowSpec{Spec: []serverlessv1alpha1.Flow{
+				{Name: "a-b", Function: "f", Role: serverlessv1alpha1.Start, Outputs: []string{"a_b"}},
+				{Name: "a_b", Function: "f", Role: serverlessv1alpha1.End},
+			}}},
+			want: `flowchart TD
+  flow0(["a-b<br/>function: f"])
+  flow1((("a_b<br/>function: f")))
+  flow0 --> flow1
+`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := RenderMermaid(tt.wf); got != tt.want {
+				t.Errorf("RenderMermaid() =\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
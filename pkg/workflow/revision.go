@@ -0,0 +1,32 @@
+package workflow
+
+import (
+	"encoding/json"
+	"hash/fnv"
+	"strconv"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	serverlessv1alpha1 "github.com/tass-io/tass-operator/api/v1alpha1"
+)
+
+// Revision returns the revision of the Workflow, a hash of the fields the WorkflowRuntime serves
+// Changing the other fields, like the HTTPTrigger or the Rollout itself, doesn't make a new revision.
+func Revision(wf *serverlessv1alpha1.Workflow) string {
+	data, _ := json.Marshal(struct {
+		Env             map[string]string                     `json:"env,omitempty"`
+		Spec            []serverlessv1alpha1.Flow             `json:"spec"`
+		Deadline        *metav1.Duration                      `json:"deadline,omitempty"`
+		StateStore      string                                `json:"stateStore,omitempty"`
+		SecurityContext *serverlessv1alpha1.SchedulerSecurity `json:"securityContext,omitempty"`
+	}{
+		Env:             wf.Spec.Env,
+		Spec:            wf.Spec.Spec,
+		Deadline:        wf.Spec.Deadline,
+		StateStore:      wf.Spec.StateStore,
+		SecurityContext: wf.Spec.SecurityContext,
+	})
+	h := fnv.New32a()
+	_, _ = h.Write(data)
+	return strconv.FormatUint(uint64(h.Sum32()), 16)
+}
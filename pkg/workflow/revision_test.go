@@ -0,0 +1,46 @@
+package workflow
+
+import (
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	serverlessv1alpha1 "github.com/tass-io/tass-operator/api/v1alpha1"
+)
+
+func TestRevision(t *testing.T) {
+	base := func() *serverlessv1alpha1.Workflow {
+		return &serverlessv1alpha1.Workflow{
+			Spec: serverlessv1alpha1.WorkflowSpec{
+				Env:  map[string]string{"a": "b"},
+				Spec: []serverlessv1alpha1.Flow{{Name: "a", Function: "f", Role: serverlessv1alpha1.Start}},
+			},
+		}
+	}
+	tests := []struct {
+		name   string
+		change func(wf *serverlessv1alpha1.Workflow)
+		want   bool
+	}{
+		{"same spec", func(wf *serverlessv1alpha1.Workflow) {}, false},
+		{"trigger only", func(wf *serverlessv1alpha1.Workflow) {
+			wf.Spec.HTTP = &serverlessv1alpha1.HTTPTrigger{Host: "example.com"}
+		}, false},
+		{"env", func(wf *serverlessv1alpha1.Workflow) { wf.Spec.Env["a"] = "c" }, true},
+		{"flows", func(wf *serverlessv1alpha1.Workflow) { wf.Spec.Spec[0].Function = "g" }, true},
+		{"deadline", func(wf *serverlessv1alpha1.Workflow) { wf.Spec.Deadline = &metav1.Duration{} }, true},
+		{"state store", func(wf *serverlessv1alpha1.Workflow) { wf.Spec.StateStore = "redis" }, true},
+		{"security context", func(wf *serverlessv1alpha1.Workflow) {
+			wf.Spec.SecurityContext = &serverlessv1alpha1.SchedulerSecurity{Privileged: true}
+		}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			wf := base()
+			tt.change(wf)
+			if got := Revision(wf) != Revision(base()); got != tt.want {
+				t.Errorf("Revision() changed = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
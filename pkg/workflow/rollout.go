@@ -0,0 +1,241 @@
+package workflow
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	serverlessv1alpha1 "github.com/tass-io/tass-operator/api/v1alpha1"
+	"github.com/tass-io/tass-operator/pkg/execution"
+)
+
+const (
+	canarySuffix = "-canary"
+	// checkInterval is how often the canary is checked while a step is pausing
+	checkInterval = 30 * time.Second
+
+	defaultMaxFailurePercent = 10
+	defaultMinExecutions     = 1
+)
+
+// canaryName returns the name of the canary WorkflowRuntime, its Deployment, Service and Ingress
+// A Workflow named with the suffix fails the validation, so it doesn't take the name.
+func canaryName(wf *serverlessv1alpha1.Workflow) string {
+	return wf.Name + canarySuffix
+}
+
+// reconcileRollout keeps the stable WorkflowRuntime serving the previous revision
+// and rolls the current revision out through the canary WorkflowRuntime.
+// When the Workflow has no Rollout, the stable WorkflowRuntime is updated to the current revision directly.
+// The current revision should have passed the validation and been recorded by ReconcileHistory,
+// so that the revision serving the requests can be rolled back to.
+// The progress is only recorded on the instance status, the caller updates it.
+func (r *Reconciler) reconcileRollout() error {
+	if r.instance.Status.Rollout == nil {
+		r.instance.Status.Rollout = &serverlessv1alpha1.RolloutStatus{}
+	}
+	status := r.instance.Status.Rollout
+	revision := Revision(r.instance)
+
+	stable, err := r.reconcileWorkflowRuntime(r.instance.Name, false)
+	if err != nil {
+		return err
+	}
+	// the WorkflowRuntimes created before revisions were recorded are updated in place
+	// the validation makes sure a Rollout comes with the HTTPTrigger
+	if stable.Spec == nil || stable.Spec.Revision == "" || stable.Spec.Revision == revision ||
+		r.instance.Spec.Rollout == nil {
+		return r.promote(revision)
+	}
+
+	status.StableRevision = stable.Spec.Revision
+	if revision == status.FailedRevision {
+		// the revision has been rolled back, wait for a new change
+		return r.deleteWorkflowRuntime(canaryName(r.instance))
+	}
+	if status.Phase != serverlessv1alpha1.RolloutProgressing || status.CanaryRevision != revision {
+		r.log.Info("rollout started", "stable", status.StableRevision, "canary", revision)
+		r.recorder.Event(r.instance, corev1.EventTypeNormal, "RolloutStarted",
+			"rolling revision "+revision+" out over "+status.StableRevision)
+		status.CanaryRevision = revision
+		status.Phase = serverlessv1alpha1.RolloutProgressing
+		status.Message = ""
+		r.startStep(0)
+	} else if int(status.Step) >= len(r.instance.Spec.Rollout.Steps) {
+		// the steps have been shortened during the rollout
+		r.startStep(int32(len(r.instance.Spec.Rollout.Steps) - 1))
+	}
+	if _, err := r.reconcileWorkflowRuntime(canaryName(r.instance), true); err != nil {
+		return err
+	}
+
+	if err := r.countCanaryExecutions(); err != nil {
+		return err
+	}
+	if reason := r.canaryFailures(); reason != "" {
+		return r.rollback(revision, reason)
+	}
+
+	step := r.instance.Spec.Rollout.Steps[status.Step]
+	remaining := status.StepStartTime.Add(step.Pause.Duration).Sub(time.Now())
+	if remaining > 0 {
+		r.requeueAfter = checkInterval
+		if remaining < checkInterval {
+			r.requeueAfter = remaining
+		}
+		return nil
+	}
+
+	// the step is over, the canary should be available to move on
+	available, err := r.canaryAvailable()
+	if err != nil {
+		return err
+	}
+	if !available {
+		return r.rollback(revision, "canary has no available replica after step "+strconv.Itoa(int(status.Step)))
+	}
+	if int(status.Step)+1 < len(r.instance.Spec.Rollout.Steps) {
+		r.startStep(status.Step + 1)
+		r.log.Info("rollout stepped", "step", status.Step, "weight", status.Weight)
+		r.recorder.Eventf(r.instance, corev1.EventTypeNormal, "RolloutStepped",
+			"step %d routes %d%% of the requests to the canary", status.Step, status.Weight)
+		r.requeueAfter = checkInterval
+		return nil
+	}
+	return r.promote(revision)
+}
+
+// startStep moves the rollout to the step, the health signals are counted from now on
+func (r *Reconciler) startStep(step int32) {
+	status := r.instance.Status.Rollout
+	now := metav1.Now()
+	status.Step = step
+	status.Weight = r.instance.Spec.Rollout.Steps[step].Weight
+	status.StepStartTime = &now
+	status.CanaryExecutions = 0
+	status.CanaryFailures = 0
+	r.requeueAfter = checkInterval
+}
+
+// promote makes the stable WorkflowRuntime serve the revision and removes the canary
+func (r *Reconciler) promote(revision string) error {
+	if _, err := r.reconcileWorkflowRuntime(r.instance.Name, true); err != nil {
+		return err
+	}
+	if err := r.deleteWorkflowRuntime(canaryName(r.instance)); err != nil {
+		return err
+	}
+	status := r.instance.Status.Rollout
+	if status.Phase == serverlessv1alpha1.RolloutProgressing {
+		r.log.Info("rollout promoted", "revision", revision)
+		r.recorder.Event(r.instance, corev1.EventTypeNormal, "RolloutPromoted", "revision "+revision+" promoted")
+		status.Message = "revision " + revision + " promoted"
+	}
+	status.StableRevision = revision
+	status.CanaryRevision = ""
+	status.Phase = serverlessv1alpha1.RolloutStable
+	status.Step = 0
+	status.Weight = 0
+	status.StepStartTime = nil
+	status.CanaryExecutions = 0
+	status.CanaryFailures = 0
+	return nil
+}
+
+// rollback removes the canary and records the revision so that it's not rolled out again
+func (r *Reconciler) rollback(revision, reason string) error {
+	r.log.Info("rollout rolled back", "revision", revision, "reason", reason)
+	r.recorder.Event(r.instance, corev1.EventTypeWarning, "RolloutRolledBack",
+		"revision "+revision+" rolled back: "+reason)
+	status := r.instance.Status.Rollout
+	status.FailedRevision = revision
+	status.CanaryRevision = ""
+	status.Phase = serverlessv1alpha1.RolloutRolledBack
+	status.Weight = 0
+	status.StepStartTime = nil
+	status.Message = "revision " + revision + " rolled back: " + reason
+	r.requeueAfter = 0
+	return r.deleteWorkflowRuntime(canaryName(r.instance))
+}
+
+// countCanaryExecutions counts the finished WorkflowExecutions the canary served in the current step,
+// an execution is served by the canary if any of its Flows ran on a canary instance
+func (r *Reconciler) countCanaryExecutions() error {
+	status := r.instance.Status.Rollout
+	var executions serverlessv1alpha1.WorkflowExecutionList
+	if err := r.cli.List(r.ctx, &executions, client.InNamespace(r.instance.Namespace),
+		client.MatchingLabels{execution.WorkflowLabel: r.instance.Name}); err != nil {
+		r.log.Error(err, "cannot list WorkflowExecutions")
+		return err
+	}
+	prefix := canaryName(r.instance) + "-"
+	status.CanaryExecutions = 0
+	status.CanaryFailures = 0
+	for i := range executions.Items {
+		e := &executions.Items[i]
+		if !execution.Finished(e) || e.Status.StartTime == nil || e.Status.StartTime.Before(status.StepStartTime) {
+			continue
+		}
+		canary := false
+		for _, flow := range e.Status.Flows {
+			if strings.HasPrefix(flow.Instance, prefix) {
+				canary = true
+				break
+			}
+		}
+		if !canary {
+			continue
+		}
+		status.CanaryExecutions++
+		if e.Status.Phase == serverlessv1alpha1.ExecutionFailed {
+			status.CanaryFailures++
+		}
+	}
+	return nil
+}
+
+// canaryFailures returns why the canary fails the check on the WorkflowExecutions it served,
+// or an empty string if it passes
+func (r *Reconciler) canaryFailures() string {
+	rollout := r.instance.Spec.Rollout
+	status := r.instance.Status.Rollout
+	maxPercent := int32(defaultMaxFailurePercent)
+	if rollout.MaxFailurePercent != nil {
+		maxPercent = *rollout.MaxFailurePercent
+	}
+	minExecutions := int32(defaultMinExecutions)
+	if rollout.MinExecutions != nil {
+		minExecutions = *rollout.MinExecutions
+	}
+	if status.CanaryExecutions == 0 || status.CanaryExecutions < minExecutions {
+		return ""
+	}
+	if status.CanaryFailures*100 <= maxPercent*status.CanaryExecutions {
+		return ""
+	}
+	return strconv.Itoa(int(status.CanaryFailures)) + " of " + strconv.Itoa(int(status.CanaryExecutions)) +
+		" executions failed, more than " + strconv.Itoa(int(maxPercent)) + "%"
+}
+
+// canaryAvailable returns whether the Deployment of the canary WorkflowRuntime has an available replica
+func (r *Reconciler) canaryAvailable() (bool, error) {
+	deploy := &appsv1.Deployment{}
+	err := r.cli.Get(r.ctx, types.NamespacedName{
+		Namespace: r.instance.Namespace,
+		Name:      canaryName(r.instance),
+	}, deploy)
+	if errors.IsNotFound(err) {
+		return false, nil
+	} else if err != nil {
+		r.log.Error(err, "cannot get canary Deployment")
+		return false, err
+	}
+	return deploy.Status.AvailableReplicas > 0, nil
+}
@@ -0,0 +1,326 @@
+package workflow
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	serverlessv1alpha1 "github.com/tass-io/tass-operator/api/v1alpha1"
+	"github.com/tass-io/tass-operator/pkg/execution"
+)
+
+// rolloutWorkflow returns a Workflow rolled out in two steps of a minute, routing 10% and then 50%
+func rolloutWorkflow() *serverlessv1alpha1.Workflow {
+	return &serverlessv1alpha1.Workflow{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "wf", UID: "wf-uid"},
+		Spec: serverlessv1alpha1.WorkflowSpec{
+			Spec: []serverlessv1alpha1.Flow{flow("a")},
+			HTTP: &serverlessv1alpha1.HTTPTrigger{},
+			Rollout: &serverlessv1alpha1.Rollout{Steps: []serverlessv1alpha1.RolloutStep{
+				{Weight: 10, Pause: metav1.Duration{Duration: time.Minute}},
+				{Weight: 50, Pause: metav1.Duration{Duration: time.Minute}},
+			}},
+		},
+	}
+}
+
+// progressing records the Workflow is at the step of rolling its current revision out,
+// the step started two minutes ago so that its pause is over
+func progressing(wf *serverlessv1alpha1.Workflow, step int32) *serverlessv1alpha1.Workflow {
+	start := metav1.NewTime(time.Now().Add(-2 * time.Minute))
+	wf.Status.Rollout = &serverlessv1alpha1.RolloutStatus{
+		StableRevision: "stable",
+		CanaryRevision: Revision(wf),
+		Phase:          serverlessv1alpha1.RolloutProgressing,
+		Step:           step,
+		Weight:         wf.Spec.Rollout.Steps[step].Weight,
+		StepStartTime:  &start,
+	}
+	return wf
+}
+
+// runtimeOf returns the WorkflowRuntime of the Workflow serving the revision
+func runtimeOf(wf *serverlessv1alpha1.Workflow, name, revision string) *serverlessv1alpha1.WorkflowRuntime {
+	controller := true
+	return &serverlessv1alpha1.WorkflowRuntime{
+		ObjectMeta: metav1.ObjectMeta{Namespace: wf.Namespace, Name: name, OwnerReferences: []metav1.OwnerReference{{
+			APIVersion: serverlessv1alpha1.GroupVersion.String(),
+			Kind:       "Workflow",
+			Name:       wf.Name,
+			UID:        wf.UID,
+			Controller: &controller,
+		}}},
+		Spec: &serverlessv1alpha1.WorkflowRuntimeSpec{Revision: revision},
+	}
+}
+
+// canaryDeployment returns the Deployment of the canary with the available replicas
+func canaryDeployment(wf *serverlessv1alpha1.Workflow, available int32) *appsv1.Deployment {
+	return &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Namespace: wf.Namespace, Name: canaryName(wf)},
+		Status:     appsv1.DeploymentStatus{AvailableReplicas: available},
+	}
+}
+
+// canaryExecution returns a WorkflowExecution of the Workflow the canary served a minute ago
+func canaryExecution(wf *serverlessv1alpha1.Workflow, name string,
+	phase serverlessv1alpha1.ExecutionPhase) *serverlessv1alpha1.WorkflowExecution {
+	start := metav1.NewTime(time.Now().Add(-time.Minute))
+	return &serverlessv1alpha1.WorkflowExecution{
+		ObjectMeta: metav1.ObjectMeta{Namespace: wf.Namespace, Name: name,
+			Labels: map[string]string{execution.WorkflowLabel: wf.Name}},
+		Status: serverlessv1alpha1.WorkflowExecutionStatus{
+			Phase:     phase,
+			StartTime: &start,
+			Flows:     []serverlessv1alpha1.FlowExecution{{Flow: "a", Instance: canaryName(wf) + "-7d9f-x2"}},
+		},
+	}
+}
+
+// rollout reconciles the rollout of the Workflow against the objects, and returns the client
+func rollout(t *testing.T, wf *serverlessv1alpha1.Workflow, objs ...runtime.Object) (*Reconciler, client.Client) {
+	t.Helper()
+	s := runtime.NewScheme()
+	_ = clientgoscheme.AddToScheme(s)
+	_ = serverlessv1alpha1.AddToScheme(s)
+	cli := fake.NewFakeClientWithScheme(s, objs...)
+	r, err := NewReconciler(context.Background(), cli, log.Log, s, record.NewFakeRecorder(10), wf)
+	if err != nil {
+		t.Fatalf("NewReconciler() error = %v", err)
+	}
+	if err := r.reconcileRollout(); err != nil {
+		t.Fatalf("reconcileRollout() error = %v", err)
+	}
+	return r, cli
+}
+
+// revisionOf returns the revision the WorkflowRuntime serves, or an empty string if it does not exist
+func revisionOf(t *testing.T, cli client.Client, name string) string {
+	t.Helper()
+	wfrt := &serverlessv1alpha1.WorkflowRuntime{}
+	err := cli.Get(context.Background(), types.NamespacedName{Namespace: "default", Name: name}, wfrt)
+	if err != nil {
+		return ""
+	}
+	return wfrt.Spec.Revision
+}
+
+func TestReconcileRolloutStarts(t *testing.T) {
+	wf := rolloutWorkflow()
+	r, cli := rollout(t, wf, runtimeOf(wf, wf.Name, "stable"))
+
+	status := wf.Status.Rollout
+	if status.Phase != serverlessv1alpha1.RolloutProgressing || status.Step != 0 || status.Weight != 10 ||
+		status.StableRevision != "stable" || status.CanaryRevision != Revision(wf) {
+		t.Errorf("status = %+v, want the first step of the rollout", status)
+	}
+	if got := revisionOf(t, cli, wf.Name); got != "stable" {
+		t.Errorf("stable WorkflowRuntime serves %q, want the previous revision", got)
+	}
+	if got := revisionOf(t, cli, canaryName(wf)); got != Revision(wf) {
+		t.Errorf("canary WorkflowRuntime serves %q, want %q", got, Revision(wf))
+	}
+	if r.RequeueAfter() != checkInterval {
+		t.Errorf("RequeueAfter() = %v, want %v", r.RequeueAfter(), checkInterval)
+	}
+}
+
+func TestReconcileRolloutWithoutRollout(t *testing.T) {
+	wf := rolloutWorkflow()
+	wf.Spec.Rollout = nil
+	_, cli := rollout(t, wf, runtimeOf(wf, wf.Name, "stable"))
+
+	if wf.Status.Rollout.Phase != serverlessv1alpha1.RolloutStable || wf.Status.Rollout.StableRevision != Revision(wf) {
+		t.Errorf("status = %+v, want the revision updated in place", wf.Status.Rollout)
+	}
+	if got := revisionOf(t, cli, wf.Name); got != Revision(wf) {
+		t.Errorf("stable WorkflowRuntime serves %q, want %q", got, Revision(wf))
+	}
+}
+
+func TestReconcileRolloutNameConflict(t *testing.T) {
+	wf := rolloutWorkflow()
+	// another Workflow named like the canary got the WorkflowRuntime first
+	other := &serverlessv1alpha1.Workflow{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: canaryName(wf)}}
+	s := runtime.NewScheme()
+	_ = clientgoscheme.AddToScheme(s)
+	_ = serverlessv1alpha1.AddToScheme(s)
+	cli := fake.NewFakeClientWithScheme(s, runtimeOf(wf, wf.Name, "stable"), runtimeOf(other, other.Name, "other"))
+	r, err := NewReconciler(context.Background(), cli, log.Log, s, record.NewFakeRecorder(10), wf)
+	if err != nil {
+		t.Fatalf("NewReconciler() error = %v", err)
+	}
+	if err := r.reconcileRollout(); err == nil {
+		t.Fatal("reconcileRollout() error = nil, want the WorkflowRuntime of another Workflow refused")
+	}
+	if got := revisionOf(t, cli, canaryName(wf)); got != "other" {
+		t.Errorf("WorkflowRuntime of the other Workflow serves %q, want it untouched", got)
+	}
+}
+
+func TestReconcileRolloutPausing(t *testing.T) {
+	wf := progressing(rolloutWorkflow(), 0)
+	start := metav1.NewTime(time.Now().Add(-50 * time.Second))
+	wf.Status.Rollout.StepStartTime = &start
+	r, _ := rollout(t, wf, runtimeOf(wf, wf.Name, "stable"), runtimeOf(wf, canaryName(wf), Revision(wf)))
+
+	if wf.Status.Rollout.Step != 0 {
+		t.Errorf("step = %d, want 0 until the pause is over", wf.Status.Rollout.Step)
+	}
+	if after := r.RequeueAfter(); after <= 0 || after > 10*time.Second {
+		t.Errorf("RequeueAfter() = %v, want the rest of the pause", after)
+	}
+}
+
+func TestReconcileRolloutSteps(t *testing.T) {
+	wf := progressing(rolloutWorkflow(), 0)
+	_, cli := rollout(t, wf, runtimeOf(wf, wf.Name, "stable"), runtimeOf(wf, canaryName(wf), Revision(wf)),
+		canaryDeployment(wf, 1), canaryExecution(wf, "ok", serverlessv1alpha1.ExecutionSucceeded))
+
+	status := wf.Status.Rollout
+	if status.Phase != serverlessv1alpha1.RolloutProgressing || status.Step != 1 || status.Weight != 50 {
+		t.Errorf("status = %+v, want the second step", status)
+	}
+	if status.CanaryExecutions != 0 || status.StepStartTime == nil || time.Since(status.StepStartTime.Time) > time.Minute {
+		t.Errorf("status = %+v, want the health signals counted from the new step", status)
+	}
+	if got := revisionOf(t, cli, canaryName(wf)); got != Revision(wf) {
+		t.Errorf("canary WorkflowRuntime serves %q, want %q", got, Revision(wf))
+	}
+}
+
+func TestReconcileRolloutPromotes(t *testing.T) {
+	wf := progressing(rolloutWorkflow(), 1)
+	_, cli := rollout(t, wf, runtimeOf(wf, wf.Name, "stable"), runtimeOf(wf, canaryName(wf), Revision(wf)),
+		canaryDeployment(wf, 1))
+
+	status := wf.Status.Rollout
+	if status.Phase != serverlessv1alpha1.RolloutStable || status.StableRevision != Revision(wf) ||
+		status.CanaryRevision != "" || status.Weight != 0 || !strings.Contains(status.Message, "promoted") {
+		t.Errorf("status = %+v, want the revision promoted", status)
+	}
+	if got := revisionOf(t, cli, wf.Name); got != Revision(wf) {
+		t.Errorf("stable WorkflowRuntime serves %q, want %q", got, Revision(wf))
+	}
+	if got := revisionOf(t, cli, canaryName(wf)); got != "" {
+		t.Errorf("canary WorkflowRuntime should be deleted, it serves %q", got)
+	}
+}
+
+func TestReconcileRolloutRollsBack(t *testing.T) {
+	tests := []struct {
+		name       string
+		objs       func(wf *serverlessv1alpha1.Workflow) []runtime.Object
+		wantReason string
+	}{
+		{
+			name: "failures beyond the threshold",
+			objs: func(wf *serverlessv1alpha1.Workflow) []runtime.Object {
+				return []runtime.Object{
+					canaryDeployment(wf, 1),
+					canaryExecution(wf, "ok", serverlessv1alpha1.ExecutionSucceeded),
+					canaryExecution(wf, "failed", serverlessv1alpha1.ExecutionFailed),
+				}
+			},
+			wantReason: "1 of 2 executions failed, more than 10%",
+		},
+		{
+			name: "canary unavailable after the step",
+			objs: func(wf *serverlessv1alpha1.Workflow) []runtime.Object {
+				return []runtime.Object{canaryDeployment(wf, 0)}
+			},
+			wantReason: "canary has no available replica after step 0",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			wf := progressing(rolloutWorkflow(), 0)
+			objs := append([]runtime.Object{
+				runtimeOf(wf, wf.Name, "stable"), runtimeOf(wf, canaryName(wf), Revision(wf)),
+			}, tt.objs(wf)...)
+			_, cli := rollout(t, wf, objs...)
+
+			status := wf.Status.Rollout
+			if status.Phase != serverlessv1alpha1.RolloutRolledBack || status.FailedRevision != Revision(wf) ||
+				status.CanaryRevision != "" || status.Weight != 0 {
+				t.Errorf("status = %+v, want the revision rolled back", status)
+			}
+			if !strings.HasSuffix(status.Message, tt.wantReason) {
+				t.Errorf("message = %q, want the reason %q", status.Message, tt.wantReason)
+			}
+			if got := revisionOf(t, cli, wf.Name); got != "stable" {
+				t.Errorf("stable WorkflowRuntime serves %q, want the previous revision", got)
+			}
+			if got := revisionOf(t, cli, canaryName(wf)); got != "" {
+				t.Errorf("canary WorkflowRuntime should be deleted, it serves %q", got)
+			}
+		})
+	}
+}
+
+func TestReconcileRolloutKeepsFailedRevision(t *testing.T) {
+	wf := rolloutWorkflow()
+	wf.Status.Rollout = &serverlessv1alpha1.RolloutStatus{
+		Phase:          serverlessv1alpha1.RolloutRolledBack,
+		FailedRevision: Revision(wf),
+	}
+	_, cli := rollout(t, wf, runtimeOf(wf, wf.Name, "stable"), runtimeOf(wf, canaryName(wf), Revision(wf)))
+
+	if wf.Status.Rollout.Phase != serverlessv1alpha1.RolloutRolledBack {
+		t.Errorf("phase = %s, want the failed revision not rolled out again", wf.Status.Rollout.Phase)
+	}
+	if got := revisionOf(t, cli, canaryName(wf)); got != "" {
+		t.Errorf("canary WorkflowRuntime should be deleted, it serves %q", got)
+	}
+	if got := revisionOf(t, cli, wf.Name); got != "stable" {
+		t.Errorf("stable WorkflowRuntime serves %q, want the previous revision", got)
+	}
+}
+
+func TestCanaryFailures(t *testing.T) {
+	int32Ptr := func(i int32) *int32 { return &i }
+	tests := []struct {
+		name       string
+		rollout    serverlessv1alpha1.Rollout
+		executions int32
+		failures   int32
+		want       string
+	}{
+		{"no execution", serverlessv1alpha1.Rollout{}, 0, 0, ""},
+		{"within default threshold", serverlessv1alpha1.Rollout{}, 10, 1, ""},
+		{"beyond default threshold", serverlessv1alpha1.Rollout{}, 10, 2, "2 of 10 executions failed, more than 10%"},
+		{"at custom threshold", serverlessv1alpha1.Rollout{MaxFailurePercent: int32Ptr(50)}, 4, 2, ""},
+		{"beyond custom threshold", serverlessv1alpha1.Rollout{MaxFailurePercent: int32Ptr(50)}, 4, 3,
+			"3 of 4 executions failed, more than 50%"},
+		{"too few executions", serverlessv1alpha1.Rollout{MinExecutions: int32Ptr(5)}, 4, 4, ""},
+		{"enough executions", serverlessv1alpha1.Rollout{MinExecutions: int32Ptr(5)}, 5, 1,
+			"1 of 5 executions failed, more than 10%"},
+		{"zero tolerance", serverlessv1alpha1.Rollout{MaxFailurePercent: int32Ptr(0)}, 100, 1,
+			"1 of 100 executions failed, more than 0%"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			wf := rolloutWorkflow()
+			wf.Spec.Rollout = &tt.rollout
+			wf.Status.Rollout = &serverlessv1alpha1.RolloutStatus{
+				CanaryExecutions: tt.executions,
+				CanaryFailures:   tt.failures,
+			}
+			r := &Reconciler{instance: wf}
+			if got := r.canaryFailures(); got != tt.want {
+				t.Errorf("canaryFailures() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
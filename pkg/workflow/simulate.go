@@ -0,0 +1,159 @@
+package workflow
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+
+	serverlessv1alpha1 "github.com/tass-io/tass-operator/api/v1alpha1"
+	"github.com/tass-io/tass-operator/pkg/condition"
+)
+
+// Simulate computes the path of a workflow invocation without invoking any Function
+// It starts from the entrance with the input, and takes the mock results of the Flows
+// to decide where the results go through Flow.Outputs, the Conditions and the OnError handlers.
+// A Flow without a mock passes its input to the downstream Flows as its result.
+// Each Flow runs at most once, a Flow reached again is not visited twice.
+// The Phase of the returned status is Failed when the invocation has dead ends.
+func Simulate(wf *serverlessv1alpha1.Workflow, input string,
+	mocks []serverlessv1alpha1.FlowMock) serverlessv1alpha1.WorkflowTestStatus {
+	status := serverlessv1alpha1.WorkflowTestStatus{
+		WorkflowGeneration: wf.Generation,
+	}
+	fail := func(err error) serverlessv1alpha1.WorkflowTestStatus {
+		status.Phase = serverlessv1alpha1.TestFailed
+		status.Message = err.Error()
+		return status
+	}
+
+	flowMap := map[string]*serverlessv1alpha1.Flow{}
+	for i, flow := range wf.Spec.Spec {
+		flowMap[flow.Name] = &wf.Spec.Spec[i]
+	}
+	mockMap := map[string]*serverlessv1alpha1.FlowMock{}
+	for i, mock := range mocks {
+		if _, ok := flowMap[mock.Flow]; !ok {
+			return fail(errors.New("mocked flow " + mock.Flow + " has not defined in workflow " + wf.Name))
+		}
+		if mock.Output != "" && mock.Error != nil {
+			return fail(errors.New("mock of flow " + mock.Flow + " should not have both output and error"))
+		}
+		mockMap[mock.Flow] = &mocks[i]
+	}
+	entrance, err := findEntrance(wf)
+	if err != nil {
+		return fail(err)
+	}
+	var value interface{}
+	if input != "" {
+		if value, err = condition.Decode([]byte(input)); err != nil {
+			return fail(fmt.Errorf("input is not a legal JSON: %v", err))
+		}
+	}
+
+	type step struct {
+		flow  *serverlessv1alpha1.Flow
+		input interface{}
+	}
+	visited := map[string]bool{entrance.Name: true}
+	queue := []step{{flow: entrance, input: value}}
+	reachedExit := false
+	deadEnd := func(flow, reason string) {
+		status.DeadEnds = append(status.DeadEnds, serverlessv1alpha1.DeadEnd{Flow: flow, Reason: reason})
+	}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		flow := current.flow
+		status.VisitedFlows = append(status.VisitedFlows, flow.Name)
+
+		var next []string
+		var output interface{}
+		mock := mockMap[flow.Name]
+		switch {
+		case mock != nil && mock.Error != nil:
+			i := matchErrorHandler(flow, mock.Error)
+			if i < 0 {
+				deadEnd(flow.Name, fmt.Sprintf("error %s %q is not handled", mock.Error.Type, mock.Error.Message))
+				continue
+			}
+			status.Decisions = append(status.Decisions, serverlessv1alpha1.BranchDecision{
+				Flow:      flow.Name,
+				Condition: fmt.Sprintf("onError[%d]", i),
+				Result:    true,
+			})
+			next = flow.OnError[i].Flows
+			output = map[string]interface{}{"type": mock.Error.Type, "message": mock.Error.Message}
+		default:
+			output = current.input
+			if mock != nil && mock.Output != "" {
+				if output, err = condition.Decode([]byte(mock.Output)); err != nil {
+					deadEnd(flow.Name, fmt.Sprintf("mock output is not a legal JSON: %v", err))
+					continue
+				}
+			}
+			var decisions []condition.Decision
+			next, decisions, err = condition.Next(flow, map[string]interface{}{"$": output})
+			for _, decision := range decisions {
+				status.Decisions = append(status.Decisions, serverlessv1alpha1.BranchDecision{
+					Flow:      flow.Name,
+					Condition: decision.Condition,
+					Result:    decision.Result,
+				})
+			}
+			if err != nil {
+				deadEnd(flow.Name, err.Error())
+				continue
+			}
+			if len(flow.Outputs) == 0 {
+				reachedExit = true
+			} else if len(next) == 0 {
+				deadEnd(flow.Name, "no destination matches the result")
+			}
+		}
+
+		for _, name := range next {
+			nextFlow, ok := flowMap[name]
+			if !ok {
+				deadEnd(flow.Name, "downstream flow "+name+" has not defined")
+				continue
+			}
+			if visited[name] {
+				continue
+			}
+			visited[name] = true
+			queue = append(queue, step{flow: nextFlow, input: output})
+		}
+	}
+
+	switch {
+	case len(status.DeadEnds) != 0:
+		status.Phase = serverlessv1alpha1.TestFailed
+		status.Message = fmt.Sprintf("the invocation has %d dead ends", len(status.DeadEnds))
+	case !reachedExit:
+		status.Phase = serverlessv1alpha1.TestFailed
+		status.Message = "the invocation never reaches an exit"
+	default:
+		status.Phase = serverlessv1alpha1.TestSucceeded
+		status.Message = "the invocation reaches an exit"
+	}
+	return status
+}
+
+// matchErrorHandler returns the index of the first OnError handler of the Flow which matches the error,
+// it returns -1 if no handler matches
+func matchErrorHandler(flow *serverlessv1alpha1.Flow, e *serverlessv1alpha1.MockError) int {
+	for i, handler := range flow.OnError {
+		if handler.Type != "" && handler.Type != e.Type {
+			continue
+		}
+		if handler.Message != "" {
+			matched, err := regexp.MatchString(handler.Message, e.Message)
+			if err != nil || !matched {
+				continue
+			}
+		}
+		return i
+	}
+	return -1
+}
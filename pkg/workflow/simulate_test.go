@@ -0,0 +1,203 @@
+package workflow
+
+import (
+	"reflect"
+	"testing"
+
+	serverlessv1alpha1 "github.com/tass-io/tass-operator/api/v1alpha1"
+)
+
+// branch returns a switch Flow going to high when $.amount is greater than 100 and to low otherwise
+func branch(name, high, low string) serverlessv1alpha1.Flow {
+	f := flow(name, high, low)
+	f.Statement = serverlessv1alpha1.Switch
+	f.Conditions = []*serverlessv1alpha1.Condition{{
+		Name:       "large",
+		Type:       serverlessv1alpha1.Int,
+		Operator:   serverlessv1alpha1.Gt,
+		Target:     "$.amount",
+		Comparison: "100",
+		Destination: serverlessv1alpha1.Destination{
+			IsTrue:  serverlessv1alpha1.Next{Flows: []string{high}},
+			IsFalse: serverlessv1alpha1.Next{Flows: []string{low}},
+		},
+	}}
+	return f
+}
+
+func TestSimulate(t *testing.T) {
+	withHandlers := func(f serverlessv1alpha1.Flow, handlers ...serverlessv1alpha1.ErrorHandler) serverlessv1alpha1.Flow {
+		f.OnError = handlers
+		return f
+	}
+	tests := []struct {
+		name          string
+		flows         []serverlessv1alpha1.Flow
+		input         string
+		mocks         []serverlessv1alpha1.FlowMock
+		wantPhase     serverlessv1alpha1.WorkflowTestPhase
+		wantVisited   []string
+		wantDecisions []serverlessv1alpha1.BranchDecision
+		wantDeadEnds  []string
+	}{
+		{
+			name:        "pipeline passes the input through",
+			flows:       []serverlessv1alpha1.Flow{flow("a", "b"), flow("b")},
+			input:       `{"amount": 1}`,
+			wantPhase:   serverlessv1alpha1.TestSucceeded,
+			wantVisited: []string{"a", "b"},
+		},
+		{
+			name:        "condition on the input",
+			flows:       []serverlessv1alpha1.Flow{branch("a", "high", "low"), flow("high"), flow("low")},
+			input:       `{"amount": 120}`,
+			wantPhase:   serverlessv1alpha1.TestSucceeded,
+			wantVisited: []string{"a", "high"},
+			wantDecisions: []serverlessv1alpha1.BranchDecision{
+				{Flow: "a", Condition: "large", Result: true},
+			},
+		},
+		{
+			name:        "condition on a mocked output",
+			flows:       []serverlessv1alpha1.Flow{flow("start", "a"), branch("a", "high", "low"), flow("high"), flow("low")},
+			input:       `{"amount": 120}`,
+			mocks:       []serverlessv1alpha1.FlowMock{{Flow: "start", Output: `{"amount": 7}`}},
+			wantPhase:   serverlessv1alpha1.TestSucceeded,
+			wantVisited: []string{"start", "a", "low"},
+			wantDecisions: []serverlessv1alpha1.BranchDecision{
+				{Flow: "a", Condition: "large", Result: false},
+			},
+		},
+		{
+			name: "handled error",
+			flows: []serverlessv1alpha1.Flow{
+				withHandlers(flow("a", "b"),
+					serverlessv1alpha1.ErrorHandler{Type: "timeout", Flows: []string{"retry"}},
+					serverlessv1alpha1.ErrorHandler{Message: "^card .* declined$", Flows: []string{"refund"}}),
+				flow("b"), flow("retry"), flow("refund"),
+			},
+			mocks: []serverlessv1alpha1.FlowMock{
+				{Flow: "a", Error: &serverlessv1alpha1.MockError{Type: "payment", Message: "card 42 declined"}},
+			},
+			wantPhase:   serverlessv1alpha1.TestSucceeded,
+			wantVisited: []string{"a", "refund"},
+			wantDecisions: []serverlessv1alpha1.BranchDecision{
+				{Flow: "a", Condition: "onError[1]", Result: true},
+			},
+		},
+		{
+			name: "unhandled error",
+			flows: []serverlessv1alpha1.Flow{
+				withHandlers(flow("a", "b"), serverlessv1alpha1.ErrorHandler{Type: "timeout", Flows: []string{"retry"}}),
+				flow("b"), flow("retry"),
+			},
+			mocks: []serverlessv1alpha1.FlowMock{
+				{Flow: "a", Error: &serverlessv1alpha1.MockError{Type: "payment"}},
+			},
+			wantPhase:    serverlessv1alpha1.TestFailed,
+			wantVisited:  []string{"a"},
+			wantDeadEnds: []string{"a"},
+		},
+		{
+			name: "no destination matches",
+			flows: func() []serverlessv1alpha1.Flow {
+				f := branch("a", "high", "low")
+				f.Conditions[0].Destination.IsFalse = serverlessv1alpha1.Next{}
+				return []serverlessv1alpha1.Flow{f, flow("high"), flow("low")}
+			}(),
+			input:       `{"amount": 1}`,
+			wantPhase:   serverlessv1alpha1.TestFailed,
+			wantVisited: []string{"a"},
+			wantDecisions: []serverlessv1alpha1.BranchDecision{
+				{Flow: "a", Condition: "large", Result: false},
+			},
+			wantDeadEnds: []string{"a"},
+		},
+		{
+			name:         "mocked output is not a JSON",
+			flows:        []serverlessv1alpha1.Flow{flow("a", "b"), flow("b")},
+			mocks:        []serverlessv1alpha1.FlowMock{{Flow: "a", Output: "{"}},
+			wantPhase:    serverlessv1alpha1.TestFailed,
+			wantVisited:  []string{"a"},
+			wantDeadEnds: []string{"a"},
+		},
+		{
+			name:        "flow reached twice runs once",
+			flows:       []serverlessv1alpha1.Flow{flow("a", "b", "c"), flow("b", "d"), flow("c", "d"), flow("d")},
+			wantPhase:   serverlessv1alpha1.TestSucceeded,
+			wantVisited: []string{"a", "b", "c", "d"},
+		},
+		{
+			name:      "mock of an undefined flow",
+			flows:     []serverlessv1alpha1.Flow{flow("a")},
+			mocks:     []serverlessv1alpha1.FlowMock{{Flow: "missing"}},
+			wantPhase: serverlessv1alpha1.TestFailed,
+		},
+		{
+			name:  "mock with both output and error",
+			flows: []serverlessv1alpha1.Flow{flow("a")},
+			mocks: []serverlessv1alpha1.FlowMock{
+				{Flow: "a", Output: "1", Error: &serverlessv1alpha1.MockError{Type: "timeout"}},
+			},
+			wantPhase: serverlessv1alpha1.TestFailed,
+		},
+		{
+			name:      "input is not a JSON",
+			flows:     []serverlessv1alpha1.Flow{flow("a")},
+			input:     "{",
+			wantPhase: serverlessv1alpha1.TestFailed,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			wf := &serverlessv1alpha1.Workflow{Spec: serverlessv1alpha1.WorkflowSpec{Spec: tt.flows}}
+			got := Simulate(wf, tt.input, tt.mocks)
+			if got.Phase != tt.wantPhase {
+				t.Fatalf("Phase = %s, want %s: %s", got.Phase, tt.wantPhase, got.Message)
+			}
+			if !reflect.DeepEqual(got.VisitedFlows, tt.wantVisited) {
+				t.Errorf("VisitedFlows = %v, want %v", got.VisitedFlows, tt.wantVisited)
+			}
+			if !reflect.DeepEqual(got.Decisions, tt.wantDecisions) {
+				t.Errorf("Decisions = %v, want %v", got.Decisions, tt.wantDecisions)
+			}
+			var deadEnds []string
+			for _, deadEnd := range got.DeadEnds {
+				deadEnds = append(deadEnds, deadEnd.Flow)
+			}
+			if !reflect.DeepEqual(deadEnds, tt.wantDeadEnds) {
+				t.Errorf("DeadEnds = %v, want %v", got.DeadEnds, tt.wantDeadEnds)
+			}
+		})
+	}
+}
+
+func TestMatchErrorHandler(t *testing.T) {
+	f := flow("a")
+	f.OnError = []serverlessv1alpha1.ErrorHandler{
+		{Type: "timeout", Flows: []string{"retry"}},
+		{Type: "payment", Message: "declined$", Flows: []string{"refund"}},
+		{Message: "(", Flows: []string{"broken"}},
+		{Flows: []string{"fallback"}},
+	}
+	tests := []struct {
+		name string
+		err  serverlessv1alpha1.MockError
+		want int
+	}{
+		{name: "type", err: serverlessv1alpha1.MockError{Type: "timeout", Message: "declined"}, want: 0},
+		{name: "type and message", err: serverlessv1alpha1.MockError{Type: "payment", Message: "card declined"}, want: 1},
+		{name: "illegal pattern is skipped", err: serverlessv1alpha1.MockError{Type: "payment", Message: "("}, want: 3},
+		{name: "catch all", err: serverlessv1alpha1.MockError{Type: "io"}, want: 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := matchErrorHandler(&f, &tt.err); got != tt.want {
+				t.Errorf("matchErrorHandler() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+	if got := matchErrorHandler(&serverlessv1alpha1.Flow{}, &serverlessv1alpha1.MockError{}); got != -1 {
+		t.Errorf("matchErrorHandler() without handlers = %d, want -1", got)
+	}
+}
@@ -2,23 +2,150 @@ package workflow
 
 import (
 	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
 
 	serverlessv1alpha1 "github.com/tass-io/tass-operator/api/v1alpha1"
+	"github.com/tass-io/tass-operator/pkg/condition"
+	"github.com/tass-io/tass-operator/pkg/metrics"
 )
 
+// ValidationError is a failed check of a Workflow, Reason names the check in the metrics and the traces
+type ValidationError struct {
+	Reason string
+	Err    error
+}
+
+func (e *ValidationError) Error() string {
+	return e.Err.Error()
+}
+
+// Validate runs all the checks of a Workflow and returns the failed ones
+// The Functions and the Workflows the Flows reference are only checked if the lists are given,
+// a nil list is checked as an empty one when the other is given.
+func Validate(wf *serverlessv1alpha1.Workflow, functions *serverlessv1alpha1.FunctionList,
+	workflows *serverlessv1alpha1.WorkflowList) []*ValidationError {
+	type check struct {
+		reason string
+		run    func() error
+	}
+	var checks []check
+	if functions != nil || workflows != nil {
+		if functions == nil {
+			functions = &serverlessv1alpha1.FunctionList{}
+		}
+		if workflows == nil {
+			workflows = &serverlessv1alpha1.WorkflowList{}
+		}
+		checks = append(checks, check{metrics.ReasonFunctions, func() error {
+			return ValidateFuncExist(wf, functions, workflows)
+		}})
+	}
+	checks = append(checks,
+		check{metrics.ReasonFlows, func() error { return ValidateFlows(wf) }},
+		check{metrics.ReasonTimeouts, func() error { return ValidateTimeouts(wf) }},
+		check{metrics.ReasonHTTP, func() error { return ValidateHTTPTrigger(wf) }},
+		check{metrics.ReasonRollout, func() error { return ValidateRollout(wf) }},
+		check{metrics.ReasonEnv, func() error { return ValidateEnv(wf) }},
+	)
+
+	var errs []*ValidationError
+	for _, c := range checks {
+		if err := c.run(); err != nil {
+			errs = append(errs, &ValidationError{Reason: c.reason, Err: err})
+		}
+	}
+	return errs
+}
+
 // ValidateFuncExist validates that each Function declared in the workflow
 // has been defined in Function CRD, or it will return error
-func ValidateFuncExist(wf *serverlessv1alpha1.Workflow, fl *serverlessv1alpha1.FunctionList) error {
+// Each Flow should reference either a Function or a Workflow.
+// The Workflows referenced are validated recursively,
+// and a Workflow including itself through any nested Workflows is illegal.
+func ValidateFuncExist(wf *serverlessv1alpha1.Workflow, fl *serverlessv1alpha1.FunctionList,
+	wfl *serverlessv1alpha1.WorkflowList) error {
 	domainFunctionMap := map[string]bool{}
 	for _, pre := range fl.Items {
 		domainFunctionMap[pre.Name] = true
 	}
-	for _, flow := range wf.Spec.Spec {
-		if !domainFunctionMap[flow.Function] {
-			return errors.New("function " + flow.Function + " not defined in namespace [" + wf.Namespace + "]")
+	domainWorkflowMap := map[string]*serverlessv1alpha1.Workflow{}
+	for i, pre := range wfl.Items {
+		domainWorkflowMap[pre.Name] = &wfl.Items[i]
+	}
+	// the list may not contain the latest version of the workflow itself
+	domainWorkflowMap[wf.Name] = wf
+
+	checked := map[string]bool{}
+	var validate func(wf *serverlessv1alpha1.Workflow, path []string) error
+	validate = func(wf *serverlessv1alpha1.Workflow, path []string) error {
+		path = append(path, wf.Name)
+		for _, flow := range wf.Spec.Spec {
+			if (flow.Function == "") == (flow.Workflow == "") {
+				return errors.New("flow " + flow.Name + " in workflow " + wf.Name +
+					" should reference one and only one of function and workflow")
+			}
+			if flow.Function != "" {
+				if !domainFunctionMap[flow.Function] {
+					return errors.New("function " + flow.Function + " not defined in namespace [" + wf.Namespace + "]")
+				}
+				continue
+			}
+			for _, name := range path {
+				if name == flow.Workflow {
+					return errors.New("workflow " + flow.Workflow + " includes itself recursively: " +
+						strings.Join(append(path, flow.Workflow), " -> "))
+				}
+			}
+			child, ok := domainWorkflowMap[flow.Workflow]
+			if !ok {
+				return errors.New("workflow " + flow.Workflow + " not defined in namespace [" + wf.Namespace + "]")
+			}
+			if checked[child.Name] {
+				continue
+			}
+			if err := validate(child, path); err != nil {
+				return err
+			}
 		}
+		checked[wf.Name] = true
+		return nil
 	}
-	return nil
+	return validate(wf, nil)
+}
+
+// Referrers returns the sorted names of the Workflows in the list calling the named Workflow,
+// directly or through any nested Workflows, so that their references are validated again when it changes
+func Referrers(name string, wfl *serverlessv1alpha1.WorkflowList) []string {
+	callers := map[string][]string{}
+	for _, wf := range wfl.Items {
+		for _, flow := range wf.Spec.Spec {
+			if flow.Workflow != "" {
+				callers[flow.Workflow] = append(callers[flow.Workflow], wf.Name)
+			}
+		}
+	}
+	visited := map[string]bool{name: true}
+	var referrers []string
+	queue := []string{name}
+	for len(queue) != 0 {
+		current := queue[0]
+		queue = queue[1:]
+		for _, caller := range callers[current] {
+			if visited[caller] {
+				continue
+			}
+			visited[caller] = true
+			referrers = append(referrers, caller)
+			queue = append(queue, caller)
+		}
+	}
+	sort.Strings(referrers)
+	return referrers
 }
 
 // ValidateFlows validates wether the graph of Flows is legal or not
@@ -28,28 +155,25 @@ func ValidateFuncExist(wf *serverlessv1alpha1.Workflow, fl *serverlessv1alpha1.F
 // - Every Flow in Inputs & Outputs should have been defined in []Flow
 // - If a Flow has a Condition, every Flow in Condition.Destination
 //   should have been defined in Outputs
+// - Every Flow in OnError should have been defined in []Flow
+// - A Flow has a Foreach if and only if the Statement is 'foreach'
+// - If a Flow is 'switch', its Conditions should be legal, see validateConditions
+// - Every Flow should be reachable from the entrance
+// The edges of OnError are legitimate edges of the graph,
+// so a compensation Flow that is only reachable by an error is not an orphan.
 func ValidateFlows(wf *serverlessv1alpha1.Workflow) error {
 	flowMap := map[string]*serverlessv1alpha1.Flow{}
-	var entrance *serverlessv1alpha1.Flow
 	var hasExit bool
 	for i, flow := range wf.Spec.Spec {
 		if _, ok := flowMap[flow.Name]; ok {
 			return errors.New("flow " + flow.Name + " has defined more than once")
 		}
 		flowMap[flow.Name] = &wf.Spec.Spec[i]
-		// TODO: find Flow entrance
 		if len(flow.Outputs) == 0 {
 			hasExit = true
 		}
 	}
 
-	if entrance == nil {
-		return errors.New("flows has no entrance")
-	}
-	if !hasExit {
-		return errors.New("flows has no exit")
-	}
-
 	for _, flow := range wf.Spec.Spec {
 		// check outputs
 		outputMap := map[string]bool{}
@@ -59,13 +183,484 @@ func ValidateFlows(wf *serverlessv1alpha1.Workflow) error {
 			}
 			outputMap[output] = true
 		}
-		if flow.Statement == "direct" {
-			return nil
+		if err := validateErrorHandlers(&flow, flowMap); err != nil {
+			return err
+		}
+		if err := validateForeach(&flow); err != nil {
+			return err
+		}
+		if flow.Statement != serverlessv1alpha1.Switch {
+			continue
 		}
 
-		// TODO: More Conditions check
 		if flow.Conditions == nil {
-			return errors.New("condition should be defined when the Statement is not 'direct'")
+			return errors.New("condition should be defined when the Statement is 'switch'")
+		}
+		if err := validateConditions(&flow, outputMap); err != nil {
+			return err
+		}
+	}
+
+	entrance, err := findEntrance(wf)
+	if err != nil {
+		return err
+	}
+	if !hasExit {
+		return errors.New("flows has no exit")
+	}
+
+	// every Flow should be reachable from the entrance
+	reached := map[string]bool{entrance.Name: true}
+	queue := []*serverlessv1alpha1.Flow{entrance}
+	for len(queue) > 0 {
+		flow := queue[0]
+		queue = queue[1:]
+		for _, next := range successors(flow) {
+			if !reached[next] {
+				reached[next] = true
+				queue = append(queue, flowMap[next])
+			}
+		}
+	}
+	for _, flow := range wf.Spec.Spec {
+		if !reached[flow.Name] {
+			return errors.New("flow " + flow.Name + " is unreachable from the entrance " + entrance.Name)
+		}
+	}
+	return nil
+}
+
+// findEntrance returns the entrance of the workflow, the only Flow without upstream Flows
+func findEntrance(wf *serverlessv1alpha1.Workflow) (*serverlessv1alpha1.Flow, error) {
+	// predecessors counts the upstream Flows of each Flow
+	predecessors := map[string]int{}
+	for i := range wf.Spec.Spec {
+		for _, next := range successors(&wf.Spec.Spec[i]) {
+			predecessors[next]++
+		}
+	}
+	var entrance *serverlessv1alpha1.Flow
+	for i, flow := range wf.Spec.Spec {
+		if predecessors[flow.Name] > 0 {
+			continue
+		}
+		if entrance != nil {
+			return nil, errors.New("flows has more than one entrance: " + entrance.Name + ", " + flow.Name)
+		}
+		entrance = &wf.Spec.Spec[i]
+	}
+	if entrance == nil {
+		return nil, errors.New("flows has no entrance")
+	}
+	return entrance, nil
+}
+
+// validateConditions validates the Condition group of a switch Flow
+// - Every Condition name is unique in the group
+// - A Condition is either a predicate or a logical group of other Conditions
+// - The Operator of a predicate is supported by its Type,
+//   and the Comparison can be converted to the Type
+// - The Conditions a logical group refers to have been defined and don't refer back to it
+// - Every Flow in Destination should have been defined in Outputs,
+//   and every Condition in Destination should have been defined in the group
+func validateConditions(flow *serverlessv1alpha1.Flow, outputMap map[string]bool) error {
+	conditionMap := map[string]*serverlessv1alpha1.Condition{}
+	for _, condition := range flow.Conditions {
+		if condition == nil {
+			return errors.New("condition in Flow " + flow.Name + " should not be null")
+		}
+		if _, ok := conditionMap[condition.Name]; ok {
+			return errors.New("condition " + condition.Name + " in Flow " + flow.Name + " has defined more than once")
+		}
+		conditionMap[condition.Name] = condition
+	}
+
+	for _, condition := range flow.Conditions {
+		prefix := "condition " + condition.Name + " in Flow " + flow.Name
+		var err error
+		if isLogicalCondition(condition) {
+			err = validateLogicalCondition(condition, conditionMap)
+		} else {
+			err = validatePredicate(condition)
+		}
+		if err != nil {
+			return errors.New(prefix + ": " + err.Error())
+		}
+		for _, next := range []serverlessv1alpha1.Next{condition.Destination.IsTrue, condition.Destination.IsFalse} {
+			for _, name := range next.Flows {
+				if !outputMap[name] {
+					return errors.New(prefix + ": destination flow " + name + " has not defined in Outputs")
+				}
+			}
+			for _, name := range next.Conditions {
+				if _, ok := conditionMap[name]; !ok {
+					return errors.New(prefix + ": destination condition " + name + " has not define")
+				}
+			}
+		}
+	}
+
+	// a logical group must not refer to itself through other groups
+	visiting := map[string]bool{}
+	done := map[string]bool{}
+	var walk func(condition *serverlessv1alpha1.Condition) error
+	walk = func(condition *serverlessv1alpha1.Condition) error {
+		if done[condition.Name] {
+			return nil
+		}
+		if visiting[condition.Name] {
+			return errors.New("condition " + condition.Name + " in Flow " + flow.Name + " refers to itself")
+		}
+		visiting[condition.Name] = true
+		for _, name := range operands(condition) {
+			if err := walk(conditionMap[name]); err != nil {
+				return err
+			}
+		}
+		visiting[condition.Name] = false
+		done[condition.Name] = true
+		return nil
+	}
+	for _, condition := range flow.Conditions {
+		if err := walk(condition); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// isLogicalCondition returns true if the Condition is a logical group of other Conditions
+func isLogicalCondition(condition *serverlessv1alpha1.Condition) bool {
+	return len(condition.All) != 0 || len(condition.Any) != 0 || condition.Not != ""
+}
+
+// operands returns the names of the Conditions a logical group refers to
+func operands(condition *serverlessv1alpha1.Condition) []string {
+	names := append([]string{}, condition.All...)
+	names = append(names, condition.Any...)
+	if condition.Not != "" {
+		names = append(names, condition.Not)
+	}
+	return names
+}
+
+// validateLogicalCondition validates a logical group Condition
+func validateLogicalCondition(condition *serverlessv1alpha1.Condition,
+	conditionMap map[string]*serverlessv1alpha1.Condition) error {
+	groups := 0
+	for _, specified := range []bool{len(condition.All) != 0, len(condition.Any) != 0, condition.Not != ""} {
+		if specified {
+			groups++
+		}
+	}
+	if groups != 1 {
+		return errors.New("one and only one of all, any and not can be specified")
+	}
+	if condition.Type != "" || condition.Operator != "" || condition.Target != "" || condition.Comparison != "" {
+		return errors.New("type, operator, target and comparison should not be specified in a logical group")
+	}
+	for _, name := range operands(condition) {
+		if _, ok := conditionMap[name]; !ok {
+			return errors.New("operand " + name + " has not define")
+		}
+	}
+	return nil
+}
+
+// validatePredicate validates a Condition which compares the flow result with the Comparison
+func validatePredicate(c *serverlessv1alpha1.Condition) error {
+	switch c.Type {
+	case serverlessv1alpha1.String, serverlessv1alpha1.Int, serverlessv1alpha1.Float, serverlessv1alpha1.Bool:
+	default:
+		return errors.New("type " + string(c.Type) + " is not supported")
+	}
+	if _, err := condition.ParsePath(c.Target); err != nil {
+		return err
+	}
+	comparison := string(c.Comparison)
+	switch c.Operator {
+	case serverlessv1alpha1.Eq, serverlessv1alpha1.Ne:
+	case serverlessv1alpha1.Lt, serverlessv1alpha1.Le, serverlessv1alpha1.Gt, serverlessv1alpha1.Ge:
+		if c.Type == serverlessv1alpha1.Bool {
+			return errors.New("operator " + string(c.Operator) + " does not accept bool")
+		}
+	case serverlessv1alpha1.Contains, serverlessv1alpha1.Matches:
+		if c.Type != serverlessv1alpha1.String {
+			return errors.New("operator " + string(c.Operator) + " only accepts string")
+		}
+		if c.Operator == serverlessv1alpha1.Matches && !condition.IsReference(comparison) {
+			if _, err := regexp.Compile(comparison); err != nil {
+				return fmt.Errorf("comparison is not a legal regular expression: %v", err)
+			}
+			return nil
+		}
+	case serverlessv1alpha1.In:
+		if condition.IsReference(comparison) {
+			return nil
+		}
+		_, err := condition.CoerceList(c.Type, comparison)
+		return err
+	case serverlessv1alpha1.Exists, serverlessv1alpha1.IsNull:
+		if comparison != "" {
+			return errors.New("comparison should be empty for operator " + string(c.Operator))
+		}
+		return nil
+	default:
+		return errors.New("operator " + string(c.Operator) + " is not supported")
+	}
+	if condition.IsReference(comparison) {
+		return nil
+	}
+	if _, err := condition.Coerce(c.Type, comparison); err != nil {
+		return errors.New("comparison " + comparison + " is not " + string(c.Type))
+	}
+	return nil
+}
+
+// validateForeach validates the Foreach of a Flow
+// The Foreach should be claimed when the Statement is 'foreach', and only in this case.
+func validateForeach(flow *serverlessv1alpha1.Flow) error {
+	if flow.Statement != serverlessv1alpha1.Foreach {
+		if flow.Foreach != nil {
+			return errors.New("foreach should not be defined when the Statement of Flow " + flow.Name + " is not 'foreach'")
+		}
+		return nil
+	}
+	if flow.Foreach == nil {
+		return errors.New("foreach should be defined when the Statement of Flow " + flow.Name + " is 'foreach'")
+	}
+	if _, err := condition.ParsePath(flow.Foreach.Target); err != nil {
+		return errors.New("foreach in Flow " + flow.Name + ": " + err.Error())
+	}
+	if flow.Foreach.MaxConcurrency < 0 {
+		return errors.New("foreach maxConcurrency in Flow " + flow.Name + " should not be negative")
+	}
+	switch flow.Foreach.Aggregation {
+	case "", serverlessv1alpha1.List, serverlessv1alpha1.Merge, serverlessv1alpha1.Discard:
+	default:
+		return errors.New("foreach aggregation " + string(flow.Foreach.Aggregation) +
+			" in Flow " + flow.Name + " is not supported")
+	}
+	return nil
+}
+
+// validateErrorHandlers validates the OnError handlers of a Flow
+// Every Flow a handler routes to should have been defined, and the Message should be a legal regular expression
+func validateErrorHandlers(flow *serverlessv1alpha1.Flow, flowMap map[string]*serverlessv1alpha1.Flow) error {
+	for i, handler := range flow.OnError {
+		if len(handler.Flows) == 0 {
+			return fmt.Errorf("onError handler %d in Flow %s has no flows", i, flow.Name)
+		}
+		for _, next := range handler.Flows {
+			if _, ok := flowMap[next]; !ok {
+				return errors.New("onError flow " + next + " in Flow " + flow.Name + " has not define")
+			}
+			if next == flow.Name {
+				return errors.New("onError flow of Flow " + flow.Name + " should not be itself")
+			}
+		}
+		if handler.Message != "" {
+			if _, err := regexp.Compile(handler.Message); err != nil {
+				return fmt.Errorf("onError handler %d in Flow %s has an illegal message pattern: %v", i, flow.Name, err)
+			}
+		}
+	}
+	return nil
+}
+
+// ValidateTimeouts validates the Flow timeouts and the Workflow deadline
+// Every timeout and the deadline must be positive if they are specified.
+// When the Workflow has a deadline, the sum of the Flow timeouts along
+// the longest path of the graph must not exceed it.
+// Flows without a timeout count as zero, they are only bounded by the deadline at runtime.
+func ValidateTimeouts(wf *serverlessv1alpha1.Workflow) error {
+	flowMap := map[string]*serverlessv1alpha1.Flow{}
+	for i, flow := range wf.Spec.Spec {
+		if flow.Timeout != nil && flow.Timeout.Duration <= 0 {
+			return errors.New("timeout of flow " + flow.Name + " should be positive")
+		}
+		flowMap[flow.Name] = &wf.Spec.Spec[i]
+	}
+	if wf.Spec.Deadline == nil {
+		return nil
+	}
+	if wf.Spec.Deadline.Duration <= 0 {
+		return errors.New("deadline of workflow " + wf.Name + " should be positive")
+	}
+
+	// longest memorizes the longest accumulated timeout starting from a Flow
+	longest := map[string]time.Duration{}
+	visiting := map[string]bool{}
+	var walk func(name string) (time.Duration, error)
+	walk = func(name string) (time.Duration, error) {
+		if d, ok := longest[name]; ok {
+			return d, nil
+		}
+		if visiting[name] {
+			return 0, errors.New("flow " + name + " is in a cycle, the deadline cannot be guaranteed")
+		}
+		visiting[name] = true
+		flow := flowMap[name]
+		var max time.Duration
+		for _, next := range successors(flow) {
+			if _, ok := flowMap[next]; !ok {
+				// undefined Flows are reported by ValidateFlows
+				continue
+			}
+			d, err := walk(next)
+			if err != nil {
+				return 0, err
+			}
+			if d > max {
+				max = d
+			}
+		}
+		if flow.Timeout != nil {
+			max += flow.Timeout.Duration
+		}
+		visiting[name] = false
+		longest[name] = max
+		return max, nil
+	}
+
+	for _, flow := range wf.Spec.Spec {
+		d, err := walk(flow.Name)
+		if err != nil {
+			return err
+		}
+		if d > wf.Spec.Deadline.Duration {
+			return fmt.Errorf("flow timeouts along the path from %s take %s, longer than the deadline %s",
+				flow.Name, d, wf.Spec.Deadline.Duration)
+		}
+	}
+	return nil
+}
+
+// successors returns the names of the downstream Flows of the given Flow
+// It contains the Flows in Outputs and the Flows the errors go
+func successors(flow *serverlessv1alpha1.Flow) []string {
+	if len(flow.OnError) == 0 {
+		return flow.Outputs
+	}
+	next := append([]string{}, flow.Outputs...)
+	for _, handler := range flow.OnError {
+		next = append(next, handler.Flows...)
+	}
+	return next
+}
+
+// ValidateHTTPTrigger validates the HTTPTrigger of the workflow
+// The path should start with "/", the class should be one of ingress-nginx,
+// and the auth should claim what its type needs.
+func ValidateHTTPTrigger(wf *serverlessv1alpha1.Workflow) error {
+	trigger := wf.Spec.HTTP
+	if trigger == nil {
+		return nil
+	}
+	if trigger.Path != "" && !strings.HasPrefix(trigger.Path, "/") {
+		return errors.New("http path " + trigger.Path + " should start with \"/\"")
+	}
+	if strings.ContainsAny(trigger.Path, " \t;{}") {
+		return errors.New("http path " + trigger.Path + " has illegal characters")
+	}
+	if !nginxClass(trigger.IngressClass) {
+		return errors.New("http ingress class " + trigger.IngressClass +
+			" is not supported, the annotations of the ingress are only served by ingress-nginx")
+	}
+	if trigger.Auth == nil {
+		return nil
+	}
+	switch trigger.Auth.Type {
+	case serverlessv1alpha1.BasicAuth:
+		if trigger.Auth.Secret == "" {
+			return errors.New("http auth basic requires the secret")
+		}
+	case serverlessv1alpha1.ExternalAuth:
+		if !strings.HasPrefix(trigger.Auth.URL, "http://") && !strings.HasPrefix(trigger.Auth.URL, "https://") {
+			return errors.New("http auth external requires a http or https url")
+		}
+	default:
+		return errors.New("http auth type " + string(trigger.Auth.Type) + " is not supported")
+	}
+	return nil
+}
+
+// ValidateRollout checks the steps of the Rollout
+// A Rollout splits the requests of the HTTPTrigger, so it requires the HTTPTrigger.
+// The name of any Workflow must not end with the canary suffix, or it takes the canary runtime of another one.
+func ValidateRollout(wf *serverlessv1alpha1.Workflow) error {
+	if strings.HasSuffix(wf.Name, canarySuffix) {
+		return errors.New("workflow name " + wf.Name + " should not end with " + canarySuffix +
+			", the suffix is reserved for the canary of a rollout")
+	}
+	rollout := wf.Spec.Rollout
+	if rollout == nil {
+		return nil
+	}
+	if wf.Spec.HTTP == nil {
+		return errors.New("rollout requires the http trigger to split the requests")
+	}
+	if len(rollout.Steps) == 0 {
+		return errors.New("rollout has no step")
+	}
+	for i, step := range rollout.Steps {
+		if step.Weight < 1 || step.Weight > 100 {
+			return errors.New("rollout step " + strconv.Itoa(i) + " has weight " +
+				strconv.Itoa(int(step.Weight)) + " out of [1, 100]")
+		}
+		if step.Pause.Duration < 0 {
+			return errors.New("rollout step " + strconv.Itoa(i) + " has a negative pause")
+		}
+	}
+	if p := rollout.MaxFailurePercent; p != nil && (*p < 0 || *p > 100) {
+		return errors.New("rollout maxFailurePercent " + strconv.Itoa(int(*p)) + " is out of [0, 100]")
+	}
+	if m := rollout.MinExecutions; m != nil && *m < 0 {
+		return errors.New("rollout minExecutions should not be negative")
+	}
+	return nil
+}
+
+// envNamePattern matches the C identifiers, the environment variable names every shell accepts
+var envNamePattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// reservedEnvPrefixes are the prefixes of the environment variables the runtime owns,
+// KUBERNETES_ is set by the kubelet for the scheduler to reach the API server
+var reservedEnvPrefixes = []string{"TASS_", "KUBERNETES_"}
+
+// reservedEnvNames are the environment variables the scheduler relies on,
+// HOSTNAME is the Pod name the instance is registered with
+var reservedEnvNames = []string{"HOSTNAME"}
+
+// ValidateEnv checks the names of the environment variables of the workflow
+// The names should be C identifiers and not be reserved by the runtime.
+func ValidateEnv(wf *serverlessv1alpha1.Workflow) error {
+	names := make([]string, 0, len(wf.Spec.Env))
+	for name := range wf.Spec.Env {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if err := ValidateEnvName(name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ValidateEnvName checks the name of an environment variable is a C identifier not reserved by the runtime
+func ValidateEnvName(name string) error {
+	if !envNamePattern.MatchString(name) {
+		return errors.New("env " + name + " is not a valid environment variable name")
+	}
+	for _, reserved := range reservedEnvNames {
+		if name == reserved {
+			return errors.New("env " + name + " is reserved by the runtime")
+		}
+	}
+	for _, prefix := range reservedEnvPrefixes {
+		if strings.HasPrefix(name, prefix) {
+			return errors.New("env " + name + " is reserved by the runtime, the prefix " + prefix + " is not allowed")
 		}
 	}
 	return nil
@@ -0,0 +1,601 @@
+package workflow
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	serverlessv1alpha1 "github.com/tass-io/tass-operator/api/v1alpha1"
+	"github.com/tass-io/tass-operator/pkg/metrics"
+)
+
+// flow returns a direct Flow calling a Function named after it
+func flow(name string, outputs ...string) serverlessv1alpha1.Flow {
+	return serverlessv1alpha1.Flow{
+		Name:      name,
+		Function:  name,
+		Statement: serverlessv1alpha1.Direct,
+		Outputs:   outputs,
+	}
+}
+
+// timed returns the Flow with the timeout
+func timed(f serverlessv1alpha1.Flow, timeout time.Duration) serverlessv1alpha1.Flow {
+	f.Timeout = &metav1.Duration{Duration: timeout}
+	return f
+}
+
+func TestValidateTimeouts(t *testing.T) {
+	tests := []struct {
+		name     string
+		flows    []serverlessv1alpha1.Flow
+		deadline time.Duration
+		wantErr  bool
+	}{
+		{
+			name:  "no deadline",
+			flows: []serverlessv1alpha1.Flow{timed(flow("a", "b"), time.Hour), timed(flow("b"), time.Hour)},
+		},
+		{
+			name:    "non positive timeout",
+			flows:   []serverlessv1alpha1.Flow{timed(flow("a"), 0)},
+			wantErr: true,
+		},
+		{
+			name:     "non positive deadline",
+			flows:    []serverlessv1alpha1.Flow{flow("a")},
+			deadline: -time.Second,
+			wantErr:  true,
+		},
+		{
+			name: "longest path fits",
+			flows: []serverlessv1alpha1.Flow{
+				timed(flow("a", "b", "c"), 10*time.Second),
+				timed(flow("b", "d"), 20*time.Second),
+				timed(flow("c", "d"), 5*time.Second),
+				timed(flow("d"), 30*time.Second),
+			},
+			deadline: time.Minute,
+		},
+		{
+			name: "longest path overflows",
+			flows: []serverlessv1alpha1.Flow{
+				timed(flow("a", "b", "c"), 10*time.Second),
+				timed(flow("b", "d"), 25*time.Second),
+				timed(flow("c", "d"), 5*time.Second),
+				timed(flow("d"), 30*time.Second),
+			},
+			deadline: time.Minute,
+			wantErr:  true,
+		},
+		{
+			name: "flows without timeout count as zero",
+			flows: []serverlessv1alpha1.Flow{
+				flow("a", "b"),
+				timed(flow("b", "c"), time.Minute),
+				flow("c"),
+			},
+			deadline: time.Minute,
+		},
+		{
+			name: "error handlers are paths",
+			flows: []serverlessv1alpha1.Flow{
+				func() serverlessv1alpha1.Flow {
+					f := timed(flow("a", "b"), 30*time.Second)
+					f.OnError = []serverlessv1alpha1.ErrorHandler{{Flows: []string{"fallback"}}}
+					return f
+				}(),
+				timed(flow("b"), 10*time.Second),
+				timed(flow("fallback"), 40*time.Second),
+			},
+			deadline: time.Minute,
+			wantErr:  true,
+		},
+		{
+			name: "cycle",
+			flows: []serverlessv1alpha1.Flow{
+				flow("a", "b"),
+				flow("b", "c"),
+				flow("c", "b"),
+			},
+			deadline: time.Minute,
+			wantErr:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			wf := &serverlessv1alpha1.Workflow{Spec: serverlessv1alpha1.WorkflowSpec{Spec: tt.flows}}
+			if tt.deadline != 0 {
+				wf.Spec.Deadline = &metav1.Duration{Duration: tt.deadline}
+			}
+			if err := ValidateTimeouts(wf); (err != nil) != tt.wantErr {
+				t.Errorf("ValidateTimeouts() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// onError returns the Flow routing its errors to the fallback Flows
+func onError(f serverlessv1alpha1.Flow, fallbacks ...string) serverlessv1alpha1.Flow {
+	f.OnError = append(f.OnError, serverlessv1alpha1.ErrorHandler{Flows: fallbacks})
+	return f
+}
+
+func TestValidateFlows(t *testing.T) {
+	tests := []struct {
+		name    string
+		flows   []serverlessv1alpha1.Flow
+		wantErr bool
+	}{
+		{
+			name:  "pipeline",
+			flows: []serverlessv1alpha1.Flow{flow("a", "b"), flow("b", "c"), flow("c")},
+		},
+		{
+			name:    "defined twice",
+			flows:   []serverlessv1alpha1.Flow{flow("a", "b"), flow("b"), flow("b")},
+			wantErr: true,
+		},
+		{
+			name:    "undefined output",
+			flows:   []serverlessv1alpha1.Flow{flow("a", "missing")},
+			wantErr: true,
+		},
+		{
+			name:    "multiple entrances",
+			flows:   []serverlessv1alpha1.Flow{flow("a", "c"), flow("b", "c"), flow("c")},
+			wantErr: true,
+		},
+		{
+			name:    "no entrance",
+			flows:   []serverlessv1alpha1.Flow{flow("a", "b", "c"), flow("b", "a"), flow("c")},
+			wantErr: true,
+		},
+		{
+			name:    "no exit",
+			flows:   []serverlessv1alpha1.Flow{flow("a", "b"), flow("b", "c"), flow("c", "b")},
+			wantErr: true,
+		},
+		{
+			name: "unreachable cycle",
+			// d and e only reach each other, so a is the only entrance but they cannot be reached
+			flows:   []serverlessv1alpha1.Flow{flow("a", "b"), flow("b"), flow("d", "e"), flow("e", "d")},
+			wantErr: true,
+		},
+		{
+			name:  "reachable cycle with an exit",
+			flows: []serverlessv1alpha1.Flow{flow("a", "b"), flow("b", "c", "d"), flow("c", "b"), flow("d")},
+		},
+		{
+			name:  "compensation reached by an error only",
+			flows: []serverlessv1alpha1.Flow{onError(flow("a", "b"), "compensate"), flow("b"), flow("compensate")},
+		},
+		{
+			name:    "undefined error flow",
+			flows:   []serverlessv1alpha1.Flow{onError(flow("a", "b"), "missing"), flow("b")},
+			wantErr: true,
+		},
+		{
+			name:    "error flow is itself",
+			flows:   []serverlessv1alpha1.Flow{onError(flow("a", "b"), "a"), flow("b")},
+			wantErr: true,
+		},
+		{
+			name:    "error handler without flows",
+			flows:   []serverlessv1alpha1.Flow{onError(flow("a", "b")), flow("b")},
+			wantErr: true,
+		},
+		{
+			name: "illegal error message pattern",
+			flows: []serverlessv1alpha1.Flow{
+				func() serverlessv1alpha1.Flow {
+					f := onError(flow("a", "b"), "c")
+					f.OnError[0].Message = "(timeout"
+					return f
+				}(),
+				flow("b"),
+				flow("c"),
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			wf := &serverlessv1alpha1.Workflow{Spec: serverlessv1alpha1.WorkflowSpec{Spec: tt.flows}}
+			if err := ValidateFlows(wf); (err != nil) != tt.wantErr {
+				t.Errorf("ValidateFlows() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidatePredicate(t *testing.T) {
+	tests := []struct {
+		name      string
+		condition serverlessv1alpha1.Condition
+		wantErr   bool
+	}{
+		{name: "int", condition: predicate(serverlessv1alpha1.Int, serverlessv1alpha1.Gt, "$.a", "3")},
+		{name: "float", condition: predicate(serverlessv1alpha1.Float, serverlessv1alpha1.Le, "$.a.b", "0.5")},
+		{name: "whole result", condition: predicate(serverlessv1alpha1.String, serverlessv1alpha1.Eq, "$", "ok")},
+		{name: "reference", condition: predicate(serverlessv1alpha1.Int, serverlessv1alpha1.Lt, "$.a", "$.b")},
+		{name: "in list", condition: predicate(serverlessv1alpha1.Int, serverlessv1alpha1.In, "$.a", "[1, 2]")},
+		{name: "matches", condition: predicate(serverlessv1alpha1.String, serverlessv1alpha1.Matches, "$.a", "^t.*s$")},
+		{name: "exists", condition: predicate(serverlessv1alpha1.String, serverlessv1alpha1.Exists, "$.a", "")},
+		{
+			name:      "unsupported type",
+			condition: predicate("date", serverlessv1alpha1.Eq, "$.a", "1"),
+			wantErr:   true,
+		},
+		{
+			name:      "unsupported operator",
+			condition: predicate(serverlessv1alpha1.Int, "between", "$.a", "1"),
+			wantErr:   true,
+		},
+		{
+			name:      "target without $.",
+			condition: predicate(serverlessv1alpha1.Int, serverlessv1alpha1.Eq, "a", "1"),
+			wantErr:   true,
+		},
+		{
+			name:      "target with an empty key",
+			condition: predicate(serverlessv1alpha1.Int, serverlessv1alpha1.Eq, "$.a..b", "1"),
+			wantErr:   true,
+		},
+		{
+			name:      "comparison not of the type",
+			condition: predicate(serverlessv1alpha1.Int, serverlessv1alpha1.Eq, "$.a", "one"),
+			wantErr:   true,
+		},
+		{
+			name:      "ordering bool",
+			condition: predicate(serverlessv1alpha1.Bool, serverlessv1alpha1.Gt, "$.a", "true"),
+			wantErr:   true,
+		},
+		{
+			name:      "contains on int",
+			condition: predicate(serverlessv1alpha1.Int, serverlessv1alpha1.Contains, "$.a", "1"),
+			wantErr:   true,
+		},
+		{
+			name:      "illegal regular expression",
+			condition: predicate(serverlessv1alpha1.String, serverlessv1alpha1.Matches, "$.a", "(t"),
+			wantErr:   true,
+		},
+		{
+			name:      "in without a list",
+			condition: predicate(serverlessv1alpha1.Int, serverlessv1alpha1.In, "$.a", "1"),
+			wantErr:   true,
+		},
+		{
+			name:      "in list not of the type",
+			condition: predicate(serverlessv1alpha1.Int, serverlessv1alpha1.In, "$.a", `["a"]`),
+			wantErr:   true,
+		},
+		{
+			name:      "exists with a comparison",
+			condition: predicate(serverlessv1alpha1.String, serverlessv1alpha1.Exists, "$.a", "x"),
+			wantErr:   true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := validatePredicate(&tt.condition); (err != nil) != tt.wantErr {
+				t.Errorf("validatePredicate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func predicate(t serverlessv1alpha1.ConditionType, op serverlessv1alpha1.OperatorType,
+	target, comparison string) serverlessv1alpha1.Condition {
+	return serverlessv1alpha1.Condition{
+		Name:       "c",
+		Type:       t,
+		Operator:   op,
+		Target:     target,
+		Comparison: serverlessv1alpha1.Comparison(comparison),
+	}
+}
+
+func TestValidateConditions(t *testing.T) {
+	leaf := func(name string) *serverlessv1alpha1.Condition {
+		c := predicate(serverlessv1alpha1.Int, serverlessv1alpha1.Gt, "$.a", "1")
+		c.Name = name
+		return &c
+	}
+	group := func(name string, all, any []string, not string) *serverlessv1alpha1.Condition {
+		return &serverlessv1alpha1.Condition{Name: name, All: all, Any: any, Not: not}
+	}
+	withDestination := func(c *serverlessv1alpha1.Condition, flows, conditions []string) *serverlessv1alpha1.Condition {
+		c.Destination.IsTrue = serverlessv1alpha1.Next{Flows: flows, Conditions: conditions}
+		return c
+	}
+	tests := []struct {
+		name       string
+		conditions []*serverlessv1alpha1.Condition
+		wantErr    bool
+	}{
+		{
+			name: "groups",
+			conditions: []*serverlessv1alpha1.Condition{
+				leaf("x"), leaf("y"),
+				group("both", []string{"x", "y"}, nil, ""),
+				withDestination(group("neither", nil, nil, "both"), []string{"b"}, []string{"x"}),
+			},
+		},
+		{name: "null", conditions: []*serverlessv1alpha1.Condition{nil}, wantErr: true},
+		{name: "defined twice", conditions: []*serverlessv1alpha1.Condition{leaf("x"), leaf("x")}, wantErr: true},
+		{
+			name:       "more than one group",
+			conditions: []*serverlessv1alpha1.Condition{leaf("x"), group("g", []string{"x"}, []string{"x"}, "")},
+			wantErr:    true,
+		},
+		{
+			name: "group with a predicate",
+			conditions: []*serverlessv1alpha1.Condition{leaf("x"), func() *serverlessv1alpha1.Condition {
+				g := group("g", nil, nil, "x")
+				g.Type = serverlessv1alpha1.Int
+				return g
+			}()},
+			wantErr: true,
+		},
+		{
+			name:       "undefined operand",
+			conditions: []*serverlessv1alpha1.Condition{group("g", []string{"missing"}, nil, "")},
+			wantErr:    true,
+		},
+		{
+			name: "groups referring to each other",
+			conditions: []*serverlessv1alpha1.Condition{
+				leaf("x"),
+				group("g", []string{"x", "h"}, nil, ""),
+				group("h", nil, []string{"g"}, ""),
+			},
+			wantErr: true,
+		},
+		{
+			name:       "destination flow not in outputs",
+			conditions: []*serverlessv1alpha1.Condition{withDestination(leaf("x"), []string{"c"}, nil)},
+			wantErr:    true,
+		},
+		{
+			name:       "undefined destination condition",
+			conditions: []*serverlessv1alpha1.Condition{withDestination(leaf("x"), nil, []string{"missing"})},
+			wantErr:    true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := flow("a", "b")
+			f.Statement = serverlessv1alpha1.Switch
+			f.Conditions = tt.conditions
+			if err := validateConditions(&f, map[string]bool{"b": true}); (err != nil) != tt.wantErr {
+				t.Errorf("validateConditions() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidateEnv(t *testing.T) {
+	tests := []struct {
+		name    string
+		env     map[string]string
+		wantErr bool
+	}{
+		{name: "empty"},
+		{name: "user defined", env: map[string]string{"lang": "CH", "LOG_LEVEL": "debug", "_x1": ""}},
+		{name: "not an identifier", env: map[string]string{"1lang": "CH"}, wantErr: true},
+		{name: "dash", env: map[string]string{"log-level": "debug"}, wantErr: true},
+		{name: "reserved name", env: map[string]string{"HOSTNAME": "a"}, wantErr: true},
+		{name: "reserved prefix", env: map[string]string{"TASS_STORE": "a"}, wantErr: true},
+		{name: "kubernetes prefix", env: map[string]string{"KUBERNETES_SERVICE_HOST": "a"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			wf := &serverlessv1alpha1.Workflow{Spec: serverlessv1alpha1.WorkflowSpec{Env: tt.env}}
+			if err := ValidateEnv(wf); (err != nil) != tt.wantErr {
+				t.Errorf("ValidateEnv() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidate(t *testing.T) {
+	functions := &serverlessv1alpha1.FunctionList{Items: []serverlessv1alpha1.Function{
+		{ObjectMeta: metav1.ObjectMeta{Name: "a"}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "b"}},
+	}}
+	tests := []struct {
+		name        string
+		flows       []serverlessv1alpha1.Flow
+		functions   *serverlessv1alpha1.FunctionList
+		workflows   *serverlessv1alpha1.WorkflowList
+		wantReasons []string
+	}{
+		{
+			name:  "valid",
+			flows: []serverlessv1alpha1.Flow{flow("a", "b"), flow("b")},
+		},
+		{
+			name:      "valid with references",
+			flows:     []serverlessv1alpha1.Flow{flow("a", "b"), flow("b")},
+			functions: functions,
+		},
+		{
+			name:        "references skipped without lists",
+			flows:       []serverlessv1alpha1.Flow{flow("a", "c"), flow("c")},
+			wantReasons: nil,
+		},
+		{
+			name:        "undefined function",
+			flows:       []serverlessv1alpha1.Flow{flow("a", "c"), flow("c")},
+			functions:   functions,
+			wantReasons: []string{metrics.ReasonFunctions},
+		},
+		{
+			name:        "nil function list with workflows",
+			flows:       []serverlessv1alpha1.Flow{flow("a")},
+			workflows:   &serverlessv1alpha1.WorkflowList{},
+			wantReasons: []string{metrics.ReasonFunctions},
+		},
+		{
+			name:        "every failed check",
+			flows:       []serverlessv1alpha1.Flow{timed(flow("a", "c"), 0), flow("b"), flow("c")},
+			functions:   functions,
+			wantReasons: []string{metrics.ReasonFunctions, metrics.ReasonFlows, metrics.ReasonTimeouts},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			wf := &serverlessv1alpha1.Workflow{
+				ObjectMeta: metav1.ObjectMeta{Name: "wf", Namespace: "default"},
+				Spec:       serverlessv1alpha1.WorkflowSpec{Spec: tt.flows},
+			}
+			var reasons []string
+			for _, err := range Validate(wf, tt.functions, tt.workflows) {
+				reasons = append(reasons, err.Reason)
+			}
+			if !reflect.DeepEqual(reasons, tt.wantReasons) {
+				t.Errorf("Validate() reasons = %v, want %v", reasons, tt.wantReasons)
+			}
+		})
+	}
+}
+
+func TestValidateRollout(t *testing.T) {
+	steps := []serverlessv1alpha1.RolloutStep{{Weight: 10, Pause: metav1.Duration{Duration: time.Minute}}}
+	tests := []struct {
+		name    string
+		wfName  string
+		http    *serverlessv1alpha1.HTTPTrigger
+		rollout *serverlessv1alpha1.Rollout
+		wantErr bool
+	}{
+		{name: "no rollout", wfName: "wf"},
+		{name: "rollout", wfName: "wf", http: &serverlessv1alpha1.HTTPTrigger{},
+			rollout: &serverlessv1alpha1.Rollout{Steps: steps}},
+		{name: "rollout without http", wfName: "wf", rollout: &serverlessv1alpha1.Rollout{Steps: steps},
+			wantErr: true},
+		{name: "rollout without step", wfName: "wf", http: &serverlessv1alpha1.HTTPTrigger{},
+			rollout: &serverlessv1alpha1.Rollout{}, wantErr: true},
+		{name: "name of a canary", wfName: "wf-canary", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			wf := &serverlessv1alpha1.Workflow{
+				ObjectMeta: metav1.ObjectMeta{Name: tt.wfName},
+				Spec:       serverlessv1alpha1.WorkflowSpec{HTTP: tt.http, Rollout: tt.rollout},
+			}
+			if err := ValidateRollout(wf); (err != nil) != tt.wantErr {
+				t.Errorf("ValidateRollout() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidateHTTPTrigger(t *testing.T) {
+	tests := []struct {
+		name    string
+		trigger *serverlessv1alpha1.HTTPTrigger
+		wantErr bool
+	}{
+		{
+			name: "no trigger",
+		},
+		{
+			name:    "default class",
+			trigger: &serverlessv1alpha1.HTTPTrigger{Path: "/orders"},
+		},
+		{
+			name:    "nginx class",
+			trigger: &serverlessv1alpha1.HTTPTrigger{IngressClass: "nginx"},
+		},
+		{
+			name:    "prefixed nginx class",
+			trigger: &serverlessv1alpha1.HTTPTrigger{IngressClass: "nginx-internal"},
+		},
+		{
+			name:    "unsupported class",
+			trigger: &serverlessv1alpha1.HTTPTrigger{IngressClass: "traefik"},
+			wantErr: true,
+		},
+		{
+			name:    "class only containing nginx",
+			trigger: &serverlessv1alpha1.HTTPTrigger{IngressClass: "nginxinc"},
+			wantErr: true,
+		},
+		{
+			name:    "relative path",
+			trigger: &serverlessv1alpha1.HTTPTrigger{Path: "orders"},
+			wantErr: true,
+		},
+		{
+			name:    "path breaking the snippet",
+			trigger: &serverlessv1alpha1.HTTPTrigger{Path: "/orders;deny"},
+			wantErr: true,
+		},
+		{
+			name: "basic auth without secret",
+			trigger: &serverlessv1alpha1.HTTPTrigger{
+				Auth: &serverlessv1alpha1.HTTPAuth{Type: serverlessv1alpha1.BasicAuth},
+			},
+			wantErr: true,
+		},
+		{
+			name: "external auth",
+			trigger: &serverlessv1alpha1.HTTPTrigger{
+				Auth: &serverlessv1alpha1.HTTPAuth{Type: serverlessv1alpha1.ExternalAuth, URL: "https://auth/check"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			wf := &serverlessv1alpha1.Workflow{
+				ObjectMeta: metav1.ObjectMeta{Name: "wf"},
+				Spec:       serverlessv1alpha1.WorkflowSpec{HTTP: tt.trigger},
+			}
+			if err := ValidateHTTPTrigger(wf); (err != nil) != tt.wantErr {
+				t.Errorf("ValidateHTTPTrigger() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestReferrers(t *testing.T) {
+	// calling returns a Workflow whose Flows call the Workflows
+	calling := func(name string, children ...string) serverlessv1alpha1.Workflow {
+		wf := serverlessv1alpha1.Workflow{ObjectMeta: metav1.ObjectMeta{Name: name}}
+		for _, child := range children {
+			wf.Spec.Spec = append(wf.Spec.Spec, serverlessv1alpha1.Flow{Name: child, Workflow: child})
+		}
+		wf.Spec.Spec = append(wf.Spec.Spec, flow("f"))
+		return wf
+	}
+	workflows := &serverlessv1alpha1.WorkflowList{Items: []serverlessv1alpha1.Workflow{
+		calling("root", "middle", "leaf"),
+		calling("middle", "leaf"),
+		calling("leaf"),
+		calling("other", "missing"),
+		calling("loop", "loop"),
+	}}
+	tests := []struct {
+		name string
+		want []string
+	}{
+		{"leaf", []string{"middle", "root"}},
+		{"middle", []string{"root"}},
+		{"root", nil},
+		{"missing", []string{"other"}},
+		{"loop", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Referrers(tt.name, workflows); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Referrers() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
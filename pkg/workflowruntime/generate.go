@@ -2,9 +2,12 @@ package workflowruntime
 
 import (
 	"fmt"
+	"sort"
 	"strconv"
+	"strings"
 
 	serverlessv1alpha1 "github.com/tass-io/tass-operator/api/v1alpha1"
+	"github.com/tass-io/tass-operator/pkg/workflow"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
@@ -13,18 +16,32 @@ import (
 )
 
 const (
-	defaultRole = "tass-operator"
+	// schedulerContainer is the name of the container running the local scheduler
+	schedulerContainer = "scheduler"
 	// local scheduler image info
 	imageName          = "registry.cn-shanghai.aliyuncs.com/tassio/scheduler"
 	imageVersion       = "v0.2.0"
 	containerPort      = 80
 	storeServerAddress = "100.103.79.199"
 	storeServerPort    = "6379"
+	// the environment variables of the scheduler telling the StateStore the WorkflowRuntime references
+	storeHostEnv     = "TASS_STORE_HOST"
+	storePortEnv     = "TASS_STORE_PORT"
+	storePasswordEnv = "TASS_STORE_PASSWORD"
+	// localhostSeccompPrefix is the prefix of the profiles of SchedulerSecurity loaded from the node
+	localhostSeccompPrefix = "localhost/"
+	// nonRootUser is the UID the scheduler runs as when it must not run as root and no UID is claimed
+	nonRootUser = 65532
+	// tmpVolume is the writable volume of the scheduler having a read-only root filesystem
+	tmpVolume = "tmp"
+	tmpPath   = "/tmp"
 )
 
 type generator struct {
 	workflowruntime *serverlessv1alpha1.WorkflowRuntime
 	labels          map[string]string
+	// store is the status of the StateStore referenced by the WorkflowRuntime, nil if there is none
+	store *serverlessv1alpha1.StateStoreStatus
 }
 
 func newGenerator(wfrt *serverlessv1alpha1.WorkflowRuntime,
@@ -65,7 +82,6 @@ func (g generator) desiredService() *corev1.Service {
 
 // desiredDeploymentWithServiceAccount returns a default config of a Deployment
 func (g generator) desiredDeploymentWithServiceAccount(sa string) *appsv1.Deployment {
-	trueFlag := true
 	selector := &metav1.LabelSelector{
 		MatchLabels: g.labels,
 	}
@@ -84,24 +100,19 @@ func (g generator) desiredDeploymentWithServiceAccount(sa string) *appsv1.Deploy
 				},
 				Spec: corev1.PodSpec{
 					ServiceAccountName: sa,
+					Volumes:            g.volumes(),
 					Containers: []corev1.Container{
 						{
-							Name:  "scheduler",
+							Name:  schedulerContainer,
 							Image: imageName + ":" + imageVersion,
 							Ports: []corev1.ContainerPort{{
 								ContainerPort: containerPort,
 								Protocol:      "TCP",
 							}},
-							Args: []string{
-								// "-i", // enable static middleware layer
-								"-c", // enable collect mode
-								"-p", // enable prestart mode
-								"-a", strconv.Itoa(containerPort),
-								"-I", storeServerAddress, "-P", storeServerPort,
-							},
-							SecurityContext: &corev1.SecurityContext{
-								Privileged: &trueFlag,
-							},
+							Args:            g.args(),
+							Env:             g.env(),
+							VolumeMounts:    g.volumeMounts(),
+							SecurityContext: g.securityContext(),
 						},
 					},
 				},
@@ -110,6 +121,161 @@ func (g generator) desiredDeploymentWithServiceAccount(sa string) *appsv1.Deploy
 	}
 }
 
+// args returns the arguments of the local scheduler,
+// it connects to the StateStore if one is referenced and to the default store server otherwise
+func (g generator) args() []string {
+	host, port := storeServerAddress, storeServerPort
+	if g.store != nil {
+		host, port = g.store.Host, strconv.Itoa(int(g.store.Port))
+	}
+	return []string{
+		// "-i", // enable static middleware layer
+		"-c", // enable collect mode
+		"-p", // enable prestart mode
+		"-a", strconv.Itoa(containerPort),
+		"-I", host, "-P", port,
+	}
+}
+
+// env returns the environment variables of the WorkflowRuntime sorted by name,
+// so that the Deployment is only rolled when they change,
+// followed by the connection details of the StateStore if one is referenced.
+// The WorkflowRuntime can be edited directly, so the reserved and malformed names are dropped
+// rather than overriding the ones of the runtime or failing the Deployment, see droppedEnv.
+func (g generator) env() []corev1.EnvVar {
+	var env []corev1.EnvVar
+	if g.workflowruntime.Spec != nil && len(g.workflowruntime.Spec.Env) != 0 {
+		names := make([]string, 0, len(g.workflowruntime.Spec.Env))
+		for name := range g.workflowruntime.Spec.Env {
+			if workflow.ValidateEnvName(name) == nil {
+				names = append(names, name)
+			}
+		}
+		sort.Strings(names)
+		env = make([]corev1.EnvVar, 0, len(names))
+		for _, name := range names {
+			env = append(env, corev1.EnvVar{Name: name, Value: g.workflowruntime.Spec.Env[name]})
+		}
+	}
+	if g.store == nil {
+		return env
+	}
+	env = append(env,
+		corev1.EnvVar{Name: storeHostEnv, Value: g.store.Host},
+		corev1.EnvVar{Name: storePortEnv, Value: strconv.Itoa(int(g.store.Port))})
+	if g.store.PasswordSecret != nil {
+		// the password is read from the Secret by the kubelet, it never shows in the Deployment
+		env = append(env, corev1.EnvVar{
+			Name:      storePasswordEnv,
+			ValueFrom: &corev1.EnvVarSource{SecretKeyRef: g.store.PasswordSecret.DeepCopy()},
+		})
+	}
+	return env
+}
+
+// droppedEnv returns the errors of the environment variables of the WorkflowRuntime env leaves out, sorted by name
+func (g generator) droppedEnv() []error {
+	if g.workflowruntime.Spec == nil {
+		return nil
+	}
+	names := make([]string, 0, len(g.workflowruntime.Spec.Env))
+	for name := range g.workflowruntime.Spec.Env {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var errs []error
+	for _, name := range names {
+		if err := workflow.ValidateEnvName(name); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errs
+}
+
+// security returns the security configuration of the scheduler, the zero value means the least privilege
+func (g generator) security() serverlessv1alpha1.SchedulerSecurity {
+	if g.workflowruntime.Spec == nil || g.workflowruntime.Spec.SecurityContext == nil {
+		return serverlessv1alpha1.SchedulerSecurity{}
+	}
+	return *g.workflowruntime.Spec.SecurityContext
+}
+
+// securityContext returns the security context of the scheduler container,
+// a privileged one is only returned when the WorkflowRuntime opts in
+func (g generator) securityContext() *corev1.SecurityContext {
+	security := g.security()
+	if security.Privileged {
+		privileged := true
+		return &corev1.SecurityContext{Privileged: &privileged}
+	}
+
+	runAsNonRoot := true
+	if security.RunAsNonRoot != nil {
+		runAsNonRoot = *security.RunAsNonRoot
+	}
+	runAsUser := security.RunAsUser
+	if runAsUser == nil && runAsNonRoot {
+		// the image may not claim a non-root user, the kubelet refuses to start it then
+		uid := int64(nonRootUser)
+		runAsUser = &uid
+	}
+	add := security.Capabilities
+	if len(add) == 0 {
+		add = []corev1.Capability{"NET_BIND_SERVICE"}
+	}
+	allowPrivilegeEscalation := false
+	readOnlyRootFilesystem := security.ReadOnlyRootFilesystem
+	return &corev1.SecurityContext{
+		Capabilities: &corev1.Capabilities{
+			Add:  add,
+			Drop: []corev1.Capability{"ALL"},
+		},
+		RunAsNonRoot:             &runAsNonRoot,
+		RunAsUser:                runAsUser,
+		AllowPrivilegeEscalation: &allowPrivilegeEscalation,
+		ReadOnlyRootFilesystem:   &readOnlyRootFilesystem,
+		SeccompProfile:           seccompProfile(security.SeccompProfile),
+	}
+}
+
+// seccompProfile returns the seccomp profile of the security context claimed by the profile of SchedulerSecurity,
+// the runtime default one if it's empty or unknown
+func seccompProfile(profile string) *corev1.SeccompProfile {
+	switch {
+	case profile == "unconfined":
+		return &corev1.SeccompProfile{Type: corev1.SeccompProfileTypeUnconfined}
+	case strings.HasPrefix(profile, localhostSeccompPrefix) && len(profile) > len(localhostSeccompPrefix):
+		path := strings.TrimPrefix(profile, localhostSeccompPrefix)
+		return &corev1.SeccompProfile{Type: corev1.SeccompProfileTypeLocalhost, LocalhostProfile: &path}
+	default:
+		return &corev1.SeccompProfile{Type: corev1.SeccompProfileTypeRuntimeDefault}
+	}
+}
+
+// volumes returns the volumes of the Pods, a scheduler with a read-only root filesystem writes to an emptyDir
+func (g generator) volumes() []corev1.Volume {
+	if !g.readOnlyRootFilesystem() {
+		return nil
+	}
+	return []corev1.Volume{{
+		Name:         tmpVolume,
+		VolumeSource: corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{}},
+	}}
+}
+
+// volumeMounts returns the volume mounts of the scheduler container
+func (g generator) volumeMounts() []corev1.VolumeMount {
+	if !g.readOnlyRootFilesystem() {
+		return nil
+	}
+	return []corev1.VolumeMount{{Name: tmpVolume, MountPath: tmpPath}}
+}
+
+func (g generator) readOnlyRootFilesystem() bool {
+	security := g.security()
+	return !security.Privileged && security.ReadOnlyRootFilesystem
+}
+
 // desiredServiceAccount returns a ServiceAccount without owner
 func (g generator) desiredServiceAccount() *corev1.ServiceAccount {
 	sa := &corev1.ServiceAccount{
@@ -125,9 +291,111 @@ func (g generator) desiredServiceAccount() *corev1.ServiceAccount {
 	return sa
 }
 
-// desiredRoleBinding binds a ServiceAccount and a Role
+// desiredRole returns the Role of the scheduler
+// The scheduler only updates its own WorkflowRuntime, and gets by name the WorkflowRuntimes of the Workflows
+// its Flows call, the Workflow owning it and the Functions and Workflows its Flows call.
+// The names don't restrict listing and watching, so these are granted by the rules without names:
+// the scheduler can read every WorkflowRuntime, Workflow and Pod of the namespace,
+// and every Function of it once its Flows call any.
+func (g generator) desiredRole() *rbacv1.Role {
+	functions, workflows, children := g.references()
+	rules := []rbacv1.PolicyRule{
+		{
+			APIGroups:     []string{serverlessv1alpha1.GroupVersion.Group},
+			Resources:     []string{"workflowruntimes"},
+			ResourceNames: []string{g.workflowruntime.Name},
+			Verbs:         []string{"get", "update", "patch"},
+		},
+	}
+	if len(children) != 0 {
+		rules = append(rules, rbacv1.PolicyRule{
+			APIGroups:     []string{serverlessv1alpha1.GroupVersion.Group},
+			Resources:     []string{"workflowruntimes"},
+			ResourceNames: children,
+			Verbs:         []string{"get"},
+		})
+	}
+	rules = append(rules, listRule("workflowruntimes"))
+	// a rule without names grants all of them, so there is none when no Workflow or Function is called
+	if len(workflows) != 0 {
+		rules = append(rules, getRule("workflows", workflows), listRule("workflows"))
+	}
+	if len(functions) != 0 {
+		rules = append(rules, getRule("functions", functions), listRule("functions"))
+	}
+	// the names of the Pods are generated, they cannot be claimed
+	rules = append(rules, rbacv1.PolicyRule{
+		APIGroups: []string{""},
+		Resources: []string{"pods"},
+		Verbs:     []string{"get", "list", "watch"},
+	})
+	return &rbacv1.Role{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: g.workflowruntime.Namespace,
+			Name:      g.workflowruntime.Name,
+			Labels:    g.labels,
+		},
+		Rules: rules,
+	}
+}
+
+// getRule grants getting the named resources of the group
+func getRule(resource string, names []string) rbacv1.PolicyRule {
+	return rbacv1.PolicyRule{
+		APIGroups:     []string{serverlessv1alpha1.GroupVersion.Group},
+		Resources:     []string{resource},
+		ResourceNames: names,
+		Verbs:         []string{"get"},
+	}
+}
+
+// listRule grants listing and watching the resources of the group
+func listRule(resource string) rbacv1.PolicyRule {
+	return rbacv1.PolicyRule{
+		APIGroups: []string{serverlessv1alpha1.GroupVersion.Group},
+		Resources: []string{resource},
+		Verbs:     []string{"list", "watch"},
+	}
+}
+
+// references returns the sorted names of the Functions, the Workflows and the child WorkflowRuntimes
+// the scheduler reads: the Functions and Workflows called by the Flows, the Workflow owning the WorkflowRuntime,
+// and the WorkflowRuntimes serving the called Workflows, which have the names of the Workflows
+func (g generator) references() ([]string, []string, []string) {
+	functions, workflows, children := map[string]bool{}, map[string]bool{}, map[string]bool{}
+	if owner := metav1.GetControllerOf(g.workflowruntime); owner != nil && owner.Kind == "Workflow" {
+		workflows[owner.Name] = true
+	}
+	if g.workflowruntime.Spec != nil {
+		for _, flow := range g.workflowruntime.Spec.Flows {
+			if flow.Function != "" {
+				functions[flow.Function] = true
+			}
+			if flow.Workflow != "" {
+				workflows[flow.Workflow] = true
+				children[flow.Workflow] = true
+			}
+		}
+	}
+	// its own WorkflowRuntime is granted by the rule updating it
+	delete(children, g.workflowruntime.Name)
+	return sortedNames(functions), sortedNames(workflows), sortedNames(children)
+}
+
+func sortedNames(set map[string]bool) []string {
+	if len(set) == 0 {
+		return nil
+	}
+	names := make([]string, 0, len(set))
+	for name := range set {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// desiredRoleBinding binds a ServiceAccount and the Role of the WorkflowRuntime
 // Each Deployment has a ServiceAccount
-// Role has pre-defined in `hack/prepare.yaml`
 func (g generator) desiredRoleBinding(sa *corev1.ServiceAccount) *rbacv1.RoleBinding {
 	// RoleBinding and ServiceAccount use same Namespace and Name naming
 	crb := &rbacv1.RoleBinding{
@@ -145,7 +413,7 @@ func (g generator) desiredRoleBinding(sa *corev1.ServiceAccount) *rbacv1.RoleBin
 		},
 		RoleRef: rbacv1.RoleRef{
 			Kind:     "Role",
-			Name:     defaultRole,
+			Name:     g.workflowruntime.Name,
 			APIGroup: "rbac.authorization.k8s.io",
 		},
 	}
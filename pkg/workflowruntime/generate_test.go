@@ -0,0 +1,332 @@
+package workflowruntime
+
+import (
+	"strings"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	serverlessv1alpha1 "github.com/tass-io/tass-operator/api/v1alpha1"
+)
+
+func TestSecurityContext(t *testing.T) {
+	yes, no := true, false
+	uid, root := int64(nonRootUser), int64(0)
+	profile := "profiles/tass.json"
+	tests := []struct {
+		name     string
+		security *serverlessv1alpha1.SchedulerSecurity
+		want     *corev1.SecurityContext
+		wantTmp  bool
+	}{
+		{
+			name: "least privilege by default",
+			want: &corev1.SecurityContext{
+				Capabilities: &corev1.Capabilities{
+					Add:  []corev1.Capability{"NET_BIND_SERVICE"},
+					Drop: []corev1.Capability{"ALL"},
+				},
+				RunAsNonRoot:             &yes,
+				RunAsUser:                &uid,
+				AllowPrivilegeEscalation: &no,
+				ReadOnlyRootFilesystem:   &no,
+				SeccompProfile:           &corev1.SeccompProfile{Type: corev1.SeccompProfileTypeRuntimeDefault},
+			},
+		},
+		{
+			name: "privileged opt-in",
+			security: &serverlessv1alpha1.SchedulerSecurity{
+				Privileged:             true,
+				ReadOnlyRootFilesystem: true,
+				SeccompProfile:         "localhost/tass.json",
+			},
+			want: &corev1.SecurityContext{Privileged: &yes},
+		},
+		{
+			name: "customized",
+			security: &serverlessv1alpha1.SchedulerSecurity{
+				Capabilities:           []corev1.Capability{"SYS_PTRACE"},
+				RunAsNonRoot:           &no,
+				RunAsUser:              &root,
+				SeccompProfile:         "unconfined",
+				ReadOnlyRootFilesystem: true,
+			},
+			want: &corev1.SecurityContext{
+				Capabilities: &corev1.Capabilities{
+					Add:  []corev1.Capability{"SYS_PTRACE"},
+					Drop: []corev1.Capability{"ALL"},
+				},
+				RunAsNonRoot:             &no,
+				RunAsUser:                &root,
+				AllowPrivilegeEscalation: &no,
+				ReadOnlyRootFilesystem:   &yes,
+				SeccompProfile:           &corev1.SeccompProfile{Type: corev1.SeccompProfileTypeUnconfined},
+			},
+			wantTmp: true,
+		},
+		{
+			name:     "localhost seccomp profile",
+			security: &serverlessv1alpha1.SchedulerSecurity{SeccompProfile: "localhost/" + profile},
+			want: &corev1.SecurityContext{
+				Capabilities: &corev1.Capabilities{
+					Add:  []corev1.Capability{"NET_BIND_SERVICE"},
+					Drop: []corev1.Capability{"ALL"},
+				},
+				RunAsNonRoot:             &yes,
+				RunAsUser:                &uid,
+				AllowPrivilegeEscalation: &no,
+				ReadOnlyRootFilesystem:   &no,
+				SeccompProfile: &corev1.SeccompProfile{
+					Type:             corev1.SeccompProfileTypeLocalhost,
+					LocalhostProfile: &profile,
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g, _ := newGenerator(&serverlessv1alpha1.WorkflowRuntime{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "sample"},
+				Spec:       &serverlessv1alpha1.WorkflowRuntimeSpec{SecurityContext: tt.security},
+			}, map[string]string{"name": "sample"})
+			deploy := g.desiredDeploymentWithServiceAccount("sample")
+			pod := deploy.Spec.Template
+			if got := pod.Spec.Containers[0].SecurityContext; !equality.Semantic.DeepEqual(got, tt.want) {
+				t.Errorf("SecurityContext = %+v, want %+v", got, tt.want)
+			}
+			if len(pod.Annotations) != 0 {
+				t.Errorf("Annotations = %v, want none", pod.Annotations)
+			}
+			if gotTmp := len(pod.Spec.Volumes) == 1 && len(pod.Spec.Containers[0].VolumeMounts) == 1; gotTmp != tt.wantTmp {
+				t.Errorf("volumes = %+v, mounts = %+v, want the tmp volume %v",
+					pod.Spec.Volumes, pod.Spec.Containers[0].VolumeMounts, tt.wantTmp)
+			}
+		})
+	}
+}
+
+// TestRestrictedPodSecurity checks the default Pods of the scheduler against every control of
+// the "restricted" Pod Security Standard, which includes the "baseline" one
+func TestRestrictedPodSecurity(t *testing.T) {
+	for name, pod := range map[string]corev1.PodSpec{
+		"default":   desiredPod(t, nil),
+		"read-only": desiredPod(t, &serverlessv1alpha1.SchedulerSecurity{ReadOnlyRootFilesystem: true}),
+	} {
+		t.Run(name, func(t *testing.T) {
+			if pod.HostNetwork || pod.HostPID || pod.HostIPC {
+				t.Errorf("the Pod shares the host namespaces")
+			}
+			for _, volume := range pod.Volumes {
+				if volume.EmptyDir == nil {
+					t.Errorf("volume %s is not an emptyDir", volume.Name)
+				}
+			}
+			if len(pod.InitContainers) != 0 || len(pod.EphemeralContainers) != 0 {
+				t.Errorf("the Pod has containers not checked")
+			}
+			for _, c := range pod.Containers {
+				checkRestricted(t, pod.SecurityContext, c)
+			}
+		})
+	}
+}
+
+// desiredPod returns the Pod template of the scheduler with the security configuration
+func desiredPod(t *testing.T, security *serverlessv1alpha1.SchedulerSecurity) corev1.PodSpec {
+	g, err := newGenerator(&serverlessv1alpha1.WorkflowRuntime{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "sample"},
+		Spec:       &serverlessv1alpha1.WorkflowRuntimeSpec{SecurityContext: security},
+	}, map[string]string{"name": "sample"})
+	if err != nil {
+		t.Fatalf("newGenerator() error = %v", err)
+	}
+	return g.desiredDeploymentWithServiceAccount("sample").Spec.Template.Spec
+}
+
+// checkRestricted checks the container meets the "restricted" controls, the Pod level fields apply if it has none
+func checkRestricted(t *testing.T, pod *corev1.PodSecurityContext, c corev1.Container) {
+	sc := c.SecurityContext
+	if sc == nil {
+		t.Fatalf("container %s has no security context", c.Name)
+	}
+	if sc.Privileged != nil && *sc.Privileged {
+		t.Errorf("container %s is privileged", c.Name)
+	}
+	if sc.AllowPrivilegeEscalation == nil || *sc.AllowPrivilegeEscalation {
+		t.Errorf("container %s allows the privilege escalation", c.Name)
+	}
+	runAsNonRoot := sc.RunAsNonRoot
+	if runAsNonRoot == nil && pod != nil {
+		runAsNonRoot = pod.RunAsNonRoot
+	}
+	if runAsNonRoot == nil || !*runAsNonRoot {
+		t.Errorf("container %s may run as root", c.Name)
+	}
+	if sc.RunAsUser != nil && *sc.RunAsUser == 0 {
+		t.Errorf("container %s runs as UID 0", c.Name)
+	}
+	seccomp := sc.SeccompProfile
+	if seccomp == nil && pod != nil {
+		seccomp = pod.SeccompProfile
+	}
+	if seccomp == nil || (seccomp.Type != corev1.SeccompProfileTypeRuntimeDefault &&
+		seccomp.Type != corev1.SeccompProfileTypeLocalhost) {
+		t.Errorf("container %s has the seccomp profile %+v, want RuntimeDefault or Localhost", c.Name, seccomp)
+	}
+	if sc.Capabilities == nil || len(sc.Capabilities.Drop) != 1 || sc.Capabilities.Drop[0] != "ALL" {
+		t.Errorf("container %s doesn't drop ALL the capabilities", c.Name)
+	} else {
+		for _, capability := range sc.Capabilities.Add {
+			if capability != "NET_BIND_SERVICE" {
+				t.Errorf("container %s adds the capability %s", c.Name, capability)
+			}
+		}
+	}
+	if sc.ProcMount != nil && *sc.ProcMount != corev1.DefaultProcMount {
+		t.Errorf("container %s has the proc mount %s", c.Name, *sc.ProcMount)
+	}
+	if sc.SELinuxOptions != nil && (sc.SELinuxOptions.User != "" || sc.SELinuxOptions.Role != "") {
+		t.Errorf("container %s sets the SELinux user or role", c.Name)
+	}
+	for _, port := range c.Ports {
+		if port.HostPort != 0 {
+			t.Errorf("container %s uses the host port %d", c.Name, port.HostPort)
+		}
+	}
+}
+
+// grants returns the names each rule grants by the resource and the verbs, like "functions get"
+func grants(role *rbacv1.Role) map[string][]string {
+	names := map[string][]string{}
+	for _, rule := range role.Rules {
+		names[rule.Resources[0]+" "+strings.Join(rule.Verbs, ",")] = rule.ResourceNames
+	}
+	return names
+}
+
+func TestDesiredRole(t *testing.T) {
+	controller := true
+	owner := []metav1.OwnerReference{{
+		APIVersion: serverlessv1alpha1.GroupVersion.String(),
+		Kind:       "Workflow",
+		Name:       "sample",
+		Controller: &controller,
+	}}
+	tests := []struct {
+		name string
+		wfrt *serverlessv1alpha1.WorkflowRuntime
+		want map[string][]string
+	}{
+		{
+			name: "functions only",
+			wfrt: &serverlessv1alpha1.WorkflowRuntime{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "sample", OwnerReferences: owner},
+				Spec: &serverlessv1alpha1.WorkflowRuntimeSpec{Flows: []serverlessv1alpha1.Flow{
+					{Name: "start", Function: "function2"},
+					{Name: "end", Function: "function1"},
+					{Name: "again", Function: "function2"},
+				}},
+			},
+			want: map[string][]string{
+				"workflowruntimes get,update,patch": {"sample"},
+				"workflowruntimes list,watch":       nil,
+				"workflows get":                     {"sample"},
+				"workflows list,watch":              nil,
+				"functions get":                     {"function1", "function2"},
+				"functions list,watch":              nil,
+				"pods get,list,watch":               nil,
+			},
+		},
+		{
+			name: "children",
+			wfrt: &serverlessv1alpha1.WorkflowRuntime{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "sample-canary", OwnerReferences: owner},
+				Spec: &serverlessv1alpha1.WorkflowRuntimeSpec{Flows: []serverlessv1alpha1.Flow{
+					{Name: "start", Function: "function1"},
+					{Name: "next", Workflow: "child2"},
+					{Name: "other", Workflow: "child1"},
+					{Name: "again", Workflow: "child2"},
+				}},
+			},
+			want: map[string][]string{
+				"workflowruntimes get,update,patch": {"sample-canary"},
+				"workflowruntimes get":              {"child1", "child2"},
+				"workflowruntimes list,watch":       nil,
+				"workflows get":                     {"child1", "child2", "sample"},
+				"workflows list,watch":              nil,
+				"functions get":                     {"function1"},
+				"functions list,watch":              nil,
+				"pods get,list,watch":               nil,
+			},
+		},
+		{
+			// without Flows nor owner, no rule grants the Functions or Workflows
+			name: "orphan",
+			wfrt: &serverlessv1alpha1.WorkflowRuntime{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "orphan"},
+			},
+			want: map[string][]string{
+				"workflowruntimes get,update,patch": {"orphan"},
+				"workflowruntimes list,watch":       nil,
+				"pods get,list,watch":               nil,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g, _ := newGenerator(tt.wfrt, map[string]string{"name": tt.wfrt.Name})
+			role := g.desiredRole()
+			if got := grants(role); !equality.Semantic.DeepEqual(got, tt.want) {
+				t.Errorf("desiredRole() grants %v, want %v", got, tt.want)
+			}
+			// the names only restrict getting, updating and patching
+			for _, rule := range role.Rules {
+				for _, verb := range rule.Verbs {
+					if len(rule.ResourceNames) != 0 && (verb == "list" || verb == "watch") {
+						t.Errorf("rule %+v restricts %s by the names", rule, verb)
+					}
+				}
+			}
+			if binding := g.desiredRoleBinding(g.desiredServiceAccount()); binding.RoleRef.Name != role.Name {
+				t.Errorf("RoleBinding refers to %q, want the Role %q", binding.RoleRef.Name, role.Name)
+			}
+		})
+	}
+}
+
+func TestEnv(t *testing.T) {
+	g := &generator{
+		workflowruntime: &serverlessv1alpha1.WorkflowRuntime{Spec: &serverlessv1alpha1.WorkflowRuntimeSpec{
+			Env: map[string]string{
+				"LOG_LEVEL":       "debug",
+				"_private":        "1",
+				"TASS_STORE_HOST": "attacker",
+				"KUBERNETES_PORT": "1",
+				"HOSTNAME":        "other",
+				"1BAD":            "x",
+				"BAD-NAME":        "x",
+			},
+		}},
+		store: &serverlessv1alpha1.StateStoreStatus{Host: "redis", Port: 6379},
+	}
+	want := []corev1.EnvVar{
+		{Name: "LOG_LEVEL", Value: "debug"},
+		{Name: "_private", Value: "1"},
+		{Name: storeHostEnv, Value: "redis"},
+		{Name: storePortEnv, Value: "6379"},
+	}
+	if got := g.env(); !equality.Semantic.DeepEqual(got, want) {
+		t.Errorf("env() = %v, want %v", got, want)
+	}
+	var dropped []string
+	for _, err := range g.droppedEnv() {
+		dropped = append(dropped, strings.Fields(err.Error())[1])
+	}
+	wantDropped := []string{"1BAD", "BAD-NAME", "HOSTNAME", "KUBERNETES_PORT", "TASS_STORE_HOST"}
+	if strings.Join(dropped, ",") != strings.Join(wantDropped, ",") {
+		t.Errorf("droppedEnv() = %v, want %v", dropped, wantDropped)
+	}
+}
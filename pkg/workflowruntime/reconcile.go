@@ -2,30 +2,41 @@ package workflowruntime
 
 import (
 	"context"
+	"errors"
+	"time"
 
 	"github.com/go-logr/logr"
 	corev1 "k8s.io/api/core/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 
 	serverlessv1alpha1 "github.com/tass-io/tass-operator/api/v1alpha1"
+	"github.com/tass-io/tass-operator/pkg/metrics"
 )
 
+// controllerName labels the reconcile step latencies of the WorkflowRuntime reconciler
+const controllerName = "workflowruntime"
+
 type Reconciler struct {
+	// ctx carries the span of the reconcile the API calls are traced in
+	ctx      context.Context
 	cli      client.Client
 	log      logr.Logger
 	scheme   *runtime.Scheme
+	recorder record.EventRecorder
 	instance *serverlessv1alpha1.WorkflowRuntime
 	gen      *generator
 }
 
-func NewReconciler(cli client.Client, l logr.Logger,
-	s *runtime.Scheme, i *serverlessv1alpha1.WorkflowRuntime,
+func NewReconciler(ctx context.Context, cli client.Client, l logr.Logger,
+	s *runtime.Scheme, rec record.EventRecorder, i *serverlessv1alpha1.WorkflowRuntime,
 	labels map[string]string) (*Reconciler, error) {
 
 	g, err := newGenerator(i, labels)
@@ -33,26 +44,65 @@ func NewReconciler(cli client.Client, l logr.Logger,
 		return nil, err
 	}
 	return &Reconciler{
+		ctx:      ctx,
 		cli:      cli,
 		log:      l,
 		scheme:   s,
+		recorder: rec,
 		instance: i,
 		gen:      g,
 	}, nil
 }
 
 func (r *Reconciler) Reconcile() error {
+	if err := r.reconcileStateStore(); err != nil {
+		return err
+	}
+	start := time.Now()
 	serviceAccountName, err := r.reconcileRBAC()
+	metrics.ObserveStep(controllerName, metrics.StepRBAC, start)
+	if err != nil {
+		return err
+	}
+	start = time.Now()
+	err = r.reconcileDeployment(serviceAccountName)
+	metrics.ObserveStep(controllerName, metrics.StepDeployment, start)
 	if err != nil {
 		return err
 	}
-	if err := r.reconcileDeployment(serviceAccountName); err != nil {
+
+	start = time.Now()
+	err = r.reconcileService()
+	metrics.ObserveStep(controllerName, metrics.StepService, start)
+	if err != nil {
 		return err
 	}
+	return nil
+}
 
-	if err := r.reconcileService(); err != nil {
+// reconcileStateStore gets the StateStore referenced by the WorkflowRuntime,
+// the scheduler is not deployed until the store is ready to be connected
+func (r *Reconciler) reconcileStateStore() error {
+	if r.instance.Spec == nil || r.instance.Spec.StateStore == "" {
+		return nil
+	}
+	name := r.instance.Spec.StateStore
+	store := &serverlessv1alpha1.StateStore{}
+	if err := r.cli.Get(r.ctx, types.NamespacedName{
+		Namespace: r.instance.Namespace,
+		Name:      name,
+	}, store); err != nil {
+		r.log.Error(err, "cannot get the StateStore", "statestore", name)
+		r.recorder.Event(r.instance, corev1.EventTypeWarning, "StateStoreNotFound",
+			"cannot get StateStore "+name+": "+err.Error())
 		return err
 	}
+	if !store.Status.Ready {
+		r.recorder.Event(r.instance, corev1.EventTypeWarning, "StateStoreNotReady",
+			"waiting for StateStore "+name+" to be ready")
+		return errors.New("StateStore " + name + " is not ready")
+	}
+	r.gen.store = store.Status.DeepCopy()
 	return nil
 }
 
@@ -61,6 +111,9 @@ func (r *Reconciler) reconcileRBAC() (string, error) {
 	if err != nil {
 		return "", err
 	}
+	if err := r.reconcileRole(); err != nil {
+		return "", err
+	}
 	if err := r.reconcileRoleBinding(sa); err != nil {
 		return "", err
 	}
@@ -68,7 +121,7 @@ func (r *Reconciler) reconcileRBAC() (string, error) {
 }
 
 func (r *Reconciler) reconcileServiceAccount() (*corev1.ServiceAccount, error) {
-	ctx := context.Background()
+	ctx := r.ctx
 	namespacedName := types.NamespacedName{
 		Namespace: r.instance.Namespace,
 		Name:      r.instance.Name,
@@ -88,8 +141,11 @@ func (r *Reconciler) reconcileServiceAccount() (*corev1.ServiceAccount, error) {
 	if err != nil && k8serrors.IsNotFound(err) {
 		if err := r.cli.Create(ctx, desired); err != nil {
 			log.Error(err, "failed to create the serviceaccount")
+			r.recorder.Event(r.instance, corev1.EventTypeWarning, "CreateFailed",
+				"cannot create ServiceAccount "+desired.Name+": "+err.Error())
 			return nil, err
 		}
+		r.recorder.Event(r.instance, corev1.EventTypeNormal, "Created", "created ServiceAccount "+desired.Name)
 	} else if err != nil {
 		log.Error(err, "failed to get the expected serviceaccount")
 		return nil, err
@@ -98,8 +154,52 @@ func (r *Reconciler) reconcileServiceAccount() (*corev1.ServiceAccount, error) {
 	return desired, nil
 }
 
+// reconcileRole creates the Role of the scheduler, the rules changed by hand or by the Flows are restored
+func (r *Reconciler) reconcileRole() error {
+	ctx := r.ctx
+	desired := r.gen.desiredRole()
+	log := r.log.WithValues("role", types.NamespacedName{Namespace: desired.Namespace, Name: desired.Name})
+	if err := ctrl.SetControllerReference(r.instance, desired, r.scheme); err != nil {
+		return err
+	}
+
+	actual := &rbacv1.Role{}
+	err := r.cli.Get(ctx, types.NamespacedName{
+		Name:      desired.GetName(),
+		Namespace: desired.GetNamespace()},
+		actual)
+	if err != nil && k8serrors.IsNotFound(err) {
+		if err := r.cli.Create(ctx, desired); err != nil {
+			log.Error(err, "failed to create the role")
+			r.recorder.Event(r.instance, corev1.EventTypeWarning, "CreateFailed",
+				"cannot create Role "+desired.Name+": "+err.Error())
+			return err
+		}
+		r.recorder.Event(r.instance, corev1.EventTypeNormal, "Created", "created Role "+desired.Name)
+		return nil
+	} else if err != nil {
+		log.Error(err, "failed to get the expected role")
+		return err
+	}
+	if equality.Semantic.DeepEqual(actual.Rules, desired.Rules) {
+		return nil
+	}
+	actual.Rules = desired.Rules
+	if err := r.cli.Update(ctx, actual); err != nil {
+		log.Error(err, "failed to update the role")
+		r.recorder.Event(r.instance, corev1.EventTypeWarning, "UpdateFailed",
+			"cannot update Role "+desired.Name+": "+err.Error())
+		return err
+	}
+	log.Info("role rules restored")
+	r.recorder.Event(r.instance, corev1.EventTypeNormal, "Updated", "restored the rules of Role "+desired.Name)
+	return nil
+}
+
+// reconcileRoleBinding creates the RoleBinding of the scheduler and repairs it,
+// the role it refers to cannot be changed, so a RoleBinding referring to another one is recreated
 func (r *Reconciler) reconcileRoleBinding(sa *corev1.ServiceAccount) error {
-	ctx := context.Background()
+	ctx := r.ctx
 	namespacedName := types.NamespacedName{
 		Namespace: r.instance.Namespace,
 		Name:      r.instance.Name,
@@ -121,18 +221,51 @@ func (r *Reconciler) reconcileRoleBinding(sa *corev1.ServiceAccount) error {
 	if err != nil && k8serrors.IsNotFound(err) {
 		if err := r.cli.Create(ctx, desired); err != nil {
 			log.Error(err, "failed to create the rolebinding")
+			r.recorder.Event(r.instance, corev1.EventTypeWarning, "CreateFailed",
+				"cannot create RoleBinding "+desired.Name+": "+err.Error())
 			return err
 		}
+		r.recorder.Event(r.instance, corev1.EventTypeNormal, "Created", "created RoleBinding "+desired.Name)
+		return nil
 	} else if err != nil {
 		log.Error(err, "failed to get the expected rolebinding")
 		return err
 	}
+
+	if actual.RoleRef != desired.RoleRef {
+		if err := r.cli.Delete(ctx, actual); err != nil && !k8serrors.IsNotFound(err) {
+			log.Error(err, "failed to delete the rolebinding referring to another role")
+			return err
+		}
+		if err := r.cli.Create(ctx, desired); err != nil {
+			log.Error(err, "failed to recreate the rolebinding")
+			r.recorder.Event(r.instance, corev1.EventTypeWarning, "CreateFailed",
+				"cannot create RoleBinding "+desired.Name+": "+err.Error())
+			return err
+		}
+		log.Info("rolebinding recreated", "previousRole", actual.RoleRef.Name)
+		r.recorder.Event(r.instance, corev1.EventTypeNormal, "Updated",
+			"recreated RoleBinding "+desired.Name+" referring to Role "+actual.RoleRef.Name)
+		return nil
+	}
+	if !equality.Semantic.DeepEqual(actual.Subjects, desired.Subjects) {
+		actual.Subjects = desired.Subjects
+		if err := r.cli.Update(ctx, actual); err != nil {
+			log.Error(err, "failed to update the rolebinding")
+			r.recorder.Event(r.instance, corev1.EventTypeWarning, "UpdateFailed",
+				"cannot update RoleBinding "+desired.Name+": "+err.Error())
+			return err
+		}
+		log.Info("rolebinding subjects restored")
+		r.recorder.Event(r.instance, corev1.EventTypeNormal, "Updated",
+			"restored the subjects of RoleBinding "+desired.Name)
+	}
 	return nil
 }
 
 // reconcileDeployment creates a new Deploy resource or updates an existing Deploy
 func (r *Reconciler) reconcileDeployment(serviceAccountName string) error {
-	ctx := context.Background()
+	ctx := r.ctx
 	namespacedName := types.NamespacedName{
 		Namespace: r.instance.Namespace,
 		Name:      r.instance.Name,
@@ -147,24 +280,89 @@ func (r *Reconciler) reconcileDeployment(serviceAccountName string) error {
 	// deployMutateFn is called regardless of creating or updating an object.
 	// If it's a `create` action, it creates a new resource, and the `replicas` is the default value
 	// If it's an `update` action, it updates the resource with the new `replicas`
+	// The environment variables, the arguments and the security context of the scheduler
+	// are kept up to date as well, a change of them rolls the Pods of the Deployment.
+	env, args := r.gen.env(), r.gen.args()
+	for _, err := range r.gen.droppedEnv() {
+		log.Info("environment variable dropped", "reason", err.Error())
+		r.recorder.Event(r.instance, corev1.EventTypeWarning, "InvalidEnv", err.Error()+", it is not set")
+	}
+	securityContext, volumeMounts, volumes := r.gen.securityContext(), r.gen.volumeMounts(), r.gen.volumes()
+	seccompKey := corev1.SeccompContainerAnnotationKeyPrefix + schedulerContainer
+	var previous *int32
+	envChanged, argsChanged, securityChanged := false, false, false
 	deployMutateFn := func() error {
+		previous = deploy.Spec.Replicas
 		deploy.Spec.Replicas = r.instance.Spec.Replicas
+		template := &deploy.Spec.Template
+		containers := template.Spec.Containers
+		for i := range containers {
+			if containers[i].Name != schedulerContainer {
+				continue
+			}
+			if !equality.Semantic.DeepEqual(containers[i].Env, env) {
+				containers[i].Env = env
+				envChanged = true
+			}
+			if !equality.Semantic.DeepEqual(containers[i].Args, args) {
+				containers[i].Args = args
+				argsChanged = true
+			}
+			if !equality.Semantic.DeepEqual(containers[i].SecurityContext, securityContext) ||
+				!equality.Semantic.DeepEqual(containers[i].VolumeMounts, volumeMounts) {
+				containers[i].SecurityContext = securityContext
+				containers[i].VolumeMounts = volumeMounts
+				securityChanged = true
+			}
+		}
+		if !equality.Semantic.DeepEqual(template.Spec.Volumes, volumes) {
+			template.Spec.Volumes = volumes
+			securityChanged = true
+		}
+		// the seccomp profile is claimed by the security context, the deprecated annotation the Deployments
+		// got from the former versions is removed, since the API server rejects it when it differs from the field.
+		// The other annotations of the template, like the ones of "kubectl rollout restart", are kept.
+		if _, ok := template.Annotations[seccompKey]; ok {
+			delete(template.Annotations, seccompKey)
+			securityChanged = true
+		}
 		return nil
 	}
 
 	operationResult, err := controllerutil.CreateOrUpdate(ctx, r.cli, deploy, deployMutateFn)
 	if err != nil {
 		log.Error(err, "cannot create/update Deployment")
+		r.recorder.Event(r.instance, corev1.EventTypeWarning, "DeploymentFailed",
+			"cannot create/update Deployment "+deploy.Name+": "+err.Error())
 		return err
 	}
 	log.Info("Deployment " + string(operationResult))
+	switch {
+	case operationResult == controllerutil.OperationResultCreated:
+		r.recorder.Event(r.instance, corev1.EventTypeNormal, "Created", "created Deployment "+deploy.Name)
+	case operationResult == controllerutil.OperationResultUpdated && replicas(previous) != replicas(deploy.Spec.Replicas):
+		r.recorder.Eventf(r.instance, corev1.EventTypeNormal, "DeploymentScaled",
+			"scaled Deployment %s from %d to %d replicas", deploy.Name, replicas(previous), replicas(deploy.Spec.Replicas))
+	}
+	if operationResult == controllerutil.OperationResultUpdated && envChanged {
+		r.recorder.Event(r.instance, corev1.EventTypeNormal, "EnvChanged",
+			"rolling Deployment "+deploy.Name+" for the changed environment variables")
+	}
+	if operationResult == controllerutil.OperationResultUpdated && securityChanged {
+		r.recorder.Event(r.instance, corev1.EventTypeNormal, "SecurityContextChanged",
+			"rolling Deployment "+deploy.Name+" for the changed security context")
+	}
+	if operationResult == controllerutil.OperationResultUpdated && argsChanged {
+		r.recorder.Event(r.instance, corev1.EventTypeNormal, "StateStoreChanged",
+			"rolling Deployment "+deploy.Name+" for the changed state store")
+	}
 	return nil
 }
 
 // reconcileService creates a new Service resource,
 // if the resource exists, it will ignore the request
 func (r *Reconciler) reconcileService() error {
-	ctx := context.Background()
+	ctx := r.ctx
 	namespacedName := types.NamespacedName{
 		Namespace: r.instance.Namespace,
 		Name:      r.instance.Name,
@@ -180,8 +378,21 @@ func (r *Reconciler) reconcileService() error {
 		controllerutil.CreateOrUpdate(ctx, r.cli, svc, func() error { return nil })
 	if err != nil {
 		log.Error(err, "cannot create/update Service")
+		r.recorder.Event(r.instance, corev1.EventTypeWarning, "ServiceFailed",
+			"cannot create/update Service "+svc.Name+": "+err.Error())
 		return err
 	}
 	log.Info("Service " + string(operationResult))
+	if operationResult == controllerutil.OperationResultCreated {
+		r.recorder.Event(r.instance, corev1.EventTypeNormal, "Created", "created Service "+svc.Name)
+	}
 	return nil
 }
+
+// replicas returns the number of the replicas, a Deployment without replicas has 1 by default
+func replicas(n *int32) int32 {
+	if n == nil {
+		return 1
+	}
+	return *n
+}